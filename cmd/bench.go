@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/namin2/gh-assistant/internal/ai"
+	"github.com/namin2/gh-assistant/internal/git"
+	"github.com/spf13/cobra"
+)
+
+var (
+	benchDiffFile string
+	benchModels   []string
+)
+
+var benchCmd = &cobra.Command{
+	Use:   "bench",
+	Short: "Compare the commit messages of several models on a diff",
+	Long: `Generates a commit message for a diff with each model and prints the
+messages together with latency, token usage and estimated cost, to help pick
+a default model. The provider of each model is inferred from its name
+(gpt-*, o1*, o3* are OpenAI, claude* is Anthropic), falling back to the
+configured provider.
+
+Examples:
+  git diff --cached > patch.diff
+  gh-assistant bench --diff-file patch.diff --models gpt-4o-mini,gpt-4o,claude-3-5-haiku-20241022`,
+	Args: cobra.NoArgs,
+	RunE: runBench,
+}
+
+func init() {
+	rootCmd.AddCommand(benchCmd)
+	benchCmd.Flags().StringVar(&benchDiffFile, "diff-file", "", "Diff to generate messages for (- for stdin)")
+	benchCmd.Flags().StringSliceVar(&benchModels, "models", nil, "Comma-separated models to compare")
+	benchCmd.MarkFlagRequired("diff-file")
+	benchCmd.MarkFlagRequired("models")
+}
+
+// benchResult is one model's result
+type benchResult struct {
+	model     string
+	message   string
+	err       error
+	latency   time.Duration
+	usage     ai.Usage
+	cost      float64
+	costKnown bool
+}
+
+func runBench(cmd *cobra.Command, args []string) error {
+	var data []byte
+	var err error
+	if benchDiffFile == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(benchDiffFile)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read diff: %w", err)
+	}
+	req := ai.CommitRequest{Diff: string(data)}
+	if strings.TrimSpace(req.Diff) == "" {
+		return fmt.Errorf("the diff is empty")
+	}
+
+	var results []benchResult
+	for _, model := range benchModels {
+		model = strings.TrimSpace(model)
+		provider, ok := ai.ProviderForModel(model)
+		if !ok {
+			provider = resolveProvider()
+		}
+		apiKey := providerAPIKey(provider)
+		if apiKey == "" && provider.UsesAPIKey() {
+			results = append(results, benchResult{model: model, err: fmt.Errorf("no API key for %s", provider)})
+			continue
+		}
+
+		fmt.Printf("🤖 Generating with %s...\n", model)
+		cfg := aiConfig(git.New(""), provider, apiKey)
+		cfg.Model = model
+		cfg.ModelTiers = nil
+		client := ai.New(cfg)
+
+		start := time.Now()
+		ctx, stop := aiContext()
+		message, err := client.GenerateCommitMessage(ctx, req)
+		stop()
+		r := benchResult{model: model, message: message, err: err, latency: time.Since(start), usage: client.Usage()}
+		r.cost, r.costKnown = client.EstimateCost(r.usage)
+		recordSpend(client)
+		results = append(results, r)
+	}
+
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "MODEL\tLATENCY\tINPUT\tOUTPUT\tCOST")
+	for _, r := range results {
+		if r.err != nil {
+			fmt.Fprintf(w, "%s\t-\t-\t-\t-\n", r.model)
+			continue
+		}
+		cost := "unknown"
+		if r.costKnown {
+			cost = fmt.Sprintf("$%.4f", r.cost)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%s\n", r.model, r.latency.Round(time.Millisecond),
+			r.usage.InputTokens, r.usage.OutputTokens, cost)
+	}
+	w.Flush()
+
+	for _, r := range results {
+		fmt.Println()
+		fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+		fmt.Printf("📋 %s\n\n", r.model)
+		if r.err != nil {
+			fmt.Printf("   ❌ %v\n", r.err)
+			continue
+		}
+		for _, line := range strings.Split(r.message, "\n") {
+			fmt.Printf("   %s\n", line)
+		}
+	}
+	return nil
+}
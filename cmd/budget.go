@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/namin2/gh-assistant/internal/ai"
+	"github.com/namin2/gh-assistant/internal/state"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var resetBudget bool
+
+var budgetCmd = &cobra.Command{
+	Use:   "budget",
+	Short: "Show or reset AI spend against the configured cost budget",
+	Long: `Shows how much of the cost_budget (USD) has been spent in the current period.
+Spend is estimated from token usage and list prices and is reset automatically
+according to cost_budget_period (daily, weekly, monthly; default monthly).
+
+Examples:
+  gh-assistant budget
+  gh-assistant budget --reset`,
+	RunE: runBudget,
+}
+
+func init() {
+	rootCmd.AddCommand(budgetCmd)
+	budgetCmd.Flags().BoolVar(&resetBudget, "reset", false, "Reset the recorded spend to zero")
+}
+
+func runBudget(cmd *cobra.Command, args []string) error {
+	st, err := loadBudgetState()
+	if err != nil {
+		return err
+	}
+
+	if resetBudget {
+		st.ResetBudget(time.Now())
+		if err := st.Save(); err != nil {
+			return err
+		}
+		fmt.Println("✅ Budget spend reset")
+		return nil
+	}
+
+	limit := viper.GetFloat64("cost_budget")
+	fmt.Printf("💰 Spent this period: $%.4f\n", st.Budget.SpentUSD)
+	fmt.Printf("📅 Period started: %s\n", st.Budget.PeriodStart.Format("2006-01-02"))
+	if limit > 0 {
+		fmt.Printf("🧮 Budget: $%.2f (remaining $%.4f)\n", limit, limit-st.Budget.SpentUSD)
+	} else {
+		fmt.Println("🧮 Budget: not set")
+	}
+	return nil
+}
+
+// budgetPeriod returns the configured budget reset period
+func budgetPeriod() string {
+	if period := viper.GetString("cost_budget_period"); period != "" {
+		return period
+	}
+	return "monthly"
+}
+
+// loadBudgetState loads the state file with the budget period rolled forward
+func loadBudgetState() (*state.State, error) {
+	st, err := state.Load()
+	if err != nil {
+		return nil, err
+	}
+	st.RollBudgetPeriod(budgetPeriod(), time.Now())
+	return st, nil
+}
+
+// budgetGuard returns a pre-request check that refuses AI calls whose estimated
+// cost would exceed the remaining cost_budget, or nil if no budget is configured.
+// Allowed calls reserve their estimate until recordSpend saves the client's
+// actual spend, so calls made meanwhile, by any client, can't overspend.
+func budgetGuard() func(*ai.Client, float64, bool) error {
+	limit := viper.GetFloat64("cost_budget")
+	if limit <= 0 {
+		return nil
+	}
+
+	warned := false
+	return func(client *ai.Client, estimate float64, known bool) error {
+		if !known {
+			if !warned {
+				fmt.Println("⚠️  Warning: No pricing known for this model, cost budget can't be enforced")
+				warned = true
+			}
+			return nil
+		}
+
+		spendMu.Lock()
+		defer spendMu.Unlock()
+		st, err := loadBudgetState()
+		if err != nil {
+			return err
+		}
+
+		remaining := limit - st.Budget.SpentUSD - budgetReserved()
+		if estimate > remaining {
+			return fmt.Errorf("estimated cost $%.4f exceeds remaining budget $%.4f of $%.2f (reset with 'gh-assistant budget --reset')",
+				estimate, remaining, limit)
+		}
+		reservations[client] += estimate
+		return nil
+	}
+}
+
+// spendMu serializes recordSpend, budget reservations and other state file
+// updates for repositories pushed concurrently
+var spendMu sync.Mutex
+
+// reservations hold the estimated cost of each client's AI calls whose spend
+// hasn't been recorded yet; guarded by spendMu
+var reservations = map[*ai.Client]float64{}
+
+// budgetReserved returns the total cost reserved by calls not yet recorded;
+// spendMu must be held
+func budgetReserved() float64 {
+	total := 0.0
+	for _, estimate := range reservations {
+		total += estimate
+	}
+	return total
+}
+
+// recordSpend adds the client's usage since the last call to the session
+// totals and its cost to the budget state, releasing the client's
+// reservations
+func recordSpend(client *ai.Client) {
+	spendMu.Lock()
+	defer spendMu.Unlock()
+
+	delete(reservations, client)
+	cost, known := takeUsage(client)
+	if viper.GetFloat64("cost_budget") <= 0 {
+		return
+	}
+	if !known || cost == 0 {
+		return
+	}
+
+	st, err := loadBudgetState()
+	if err != nil {
+		fmt.Printf("⚠️  Warning: Could not record spend: %v\n", err)
+		return
+	}
+	st.Budget.SpentUSD += cost
+	if err := st.Save(); err != nil {
+		fmt.Printf("⚠️  Warning: Could not record spend: %v\n", err)
+	}
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/namin2/gh-assistant/internal/ai"
+	"github.com/spf13/viper"
+)
+
+// spendProvider's backend uses 160,000 input tokens per call, which cost
+// $0.40 with gpt-4o
+const spendProvider ai.Provider = "spend"
+
+func init() {
+	ai.RegisterBackend(spendProvider, func(c *ai.Client) ai.Backend {
+		return ai.BackendFunc(func(ctx context.Context, prompt string) (string, error) {
+			c.AddUsage(160000, 0)
+			return "feat: add x", nil
+		})
+	})
+}
+
+func TestBudgetGuardNearLimit(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	viper.Set("cost_budget", 1.0)
+	t.Cleanup(func() {
+		viper.Set("cost_budget", 0)
+		reservations = map[*ai.Client]float64{}
+	})
+
+	// Each client gets its own guard, as aiConfig builds one per client
+	first, second := budgetGuard(), budgetGuard()
+	a := ai.New(ai.Config{Provider: spendProvider, Model: "gpt-4o"})
+	b := ai.New(ai.Config{Provider: spendProvider, Model: "gpt-4o"})
+	spend := func() {
+		t.Helper()
+		if _, err := a.GenerateCommitMessage(context.Background(), ai.CommitRequest{Diff: "+x"}); err != nil {
+			t.Fatalf("GenerateCommitMessage() error = %v", err)
+		}
+		recordSpend(a)
+	}
+
+	steps := []struct {
+		name     string
+		run      func() error
+		wantErr  bool
+		reserved float64
+		spent    float64
+	}{
+		{"first call reserves its estimate", func() error { return first(a, 0.45, true) }, false, 0.45, 0},
+		{"recording the spend releases it", func() error { spend(); return nil }, false, 0, 0.40},
+		{"the estimate isn't counted again", func() error { return first(a, 0.45, true) }, false, 0.45, 0.40},
+		{"other clients see the reservation", func() error { return second(b, 0.30, true) }, true, 0.45, 0.40},
+		{"spend is recorded once", func() error { spend(); return nil }, false, 0, 0.80},
+		{"calls fit the rest of the budget", func() error { return second(b, 0.15, true) }, false, 0.15, 0.80},
+		{"calls past the budget are refused", func() error { return first(a, 0.10, true) }, true, 0.15, 0.80},
+		{"unpriced calls are allowed", func() error { return first(a, 5, false) }, false, 0.15, 0.80},
+	}
+	for _, step := range steps {
+		err := step.run()
+		if (err != nil) != step.wantErr {
+			t.Fatalf("%s: error = %v, want error %v", step.name, err, step.wantErr)
+		}
+		if got := budgetReserved(); math.Abs(got-step.reserved) > 1e-9 {
+			t.Errorf("%s: reserved $%g, want $%g", step.name, got, step.reserved)
+		}
+		st, err := loadBudgetState()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(st.Budget.SpentUSD-step.spent) > 1e-9 {
+			t.Errorf("%s: spent $%g, want $%g", step.name, st.Budget.SpentUSD, step.spent)
+		}
+	}
+}
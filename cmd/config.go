@@ -1,11 +1,18 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/namin2/gh-assistant/internal/ai"
+	"github.com/namin2/gh-assistant/internal/commitmsg"
+	"github.com/namin2/gh-assistant/internal/git"
+	"github.com/namin2/gh-assistant/internal/jira"
+	"github.com/namin2/gh-assistant/internal/state"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"gopkg.in/yaml.v3"
@@ -15,6 +22,14 @@ var (
 	apiKey      string
 	providerArg string
 	modelArg    string
+	ollamaHost  string
+	openaiURL   string
+	azureURL    string
+	azureDeploy string
+	temperature float64
+	topP        float64
+	maxTokens   int
+	language    string
 	// Jira config flags
 	jiraURL     string
 	jiraEmail   string
@@ -30,8 +45,20 @@ var configCmd = &cobra.Command{
 Examples:
   gh-assistant config --api-key sk-xxx --provider openai
   gh-assistant config --api-key sk-ant-xxx --provider anthropic
+  gh-assistant config --api-key AIza-xxx --provider gemini
+  gh-assistant config --api-key xxx --provider mistral
+  gh-assistant config --api-key sk-or-xxx --provider openrouter --model anthropic/claude-3.5-sonnet
+  gh-assistant config --provider openai     # Switch back, keeping both keys
+  gh-assistant config --provider openai --openai-base-url http://localhost:4000/v1
+  gh-assistant config --provider ollama --ollama-host localhost:11434
+  gh-assistant config --provider bedrock --model anthropic.claude-3-haiku-20240307-v1:0
+  gh-assistant config --provider azure --api-key xxx \
+    --azure-endpoint https://my-resource.openai.azure.com --azure-deployment gpt-4o-mini
   gh-assistant config --model gpt-4o
-  gh-assistant config --show`,
+  gh-assistant config --temperature 0.2 --top-p 0.9 --max-tokens 1024
+  gh-assistant config --language Japanese
+  gh-assistant config --show
+  gh-assistant config dump`,
 	RunE: runConfig,
 }
 
@@ -39,9 +66,17 @@ var showConfig bool
 
 func init() {
 	rootCmd.AddCommand(configCmd)
-	configCmd.Flags().StringVar(&apiKey, "api-key", "", "Set the API key")
-	configCmd.Flags().StringVar(&providerArg, "provider", "", "Set the AI provider (openai, anthropic)")
+	configCmd.Flags().StringVar(&apiKey, "api-key", "", "Set the API key of the provider (--provider, or the configured one)")
+	configCmd.Flags().StringVar(&providerArg, "provider", "", "Set the AI provider (openai, anthropic, ollama, gemini, azure, bedrock, mistral, openrouter, mock)")
 	configCmd.Flags().StringVar(&modelArg, "model", "", "Set the model to use")
+	configCmd.Flags().StringVar(&openaiURL, "openai-base-url", "", "Set the base URL of an OpenAI-compatible server (e.g., http://localhost:4000/v1)")
+	configCmd.Flags().StringVar(&ollamaHost, "ollama-host", "", "Set the Ollama server address (default localhost:11434)")
+	configCmd.Flags().StringVar(&azureURL, "azure-endpoint", "", "Set the Azure OpenAI resource endpoint (e.g., https://my-resource.openai.azure.com)")
+	configCmd.Flags().StringVar(&azureDeploy, "azure-deployment", "", "Set the Azure OpenAI deployment name")
+	configCmd.Flags().Float64Var(&temperature, "temperature", 0, "Set the sampling temperature (0-2; lower is more deterministic)")
+	configCmd.Flags().Float64Var(&topP, "top-p", 0, "Set the nucleus sampling probability (above 0, up to 1)")
+	configCmd.Flags().IntVar(&maxTokens, "max-tokens", 0, "Set the completion token budget (default sized to the request)")
+	configCmd.Flags().StringVar(&language, "language", "", "Set the language of generated messages and pull request descriptions (e.g., Japanese, pt-BR; \"English\" restores the default)")
 	configCmd.Flags().BoolVar(&showConfig, "show", false, "Show current configuration")
 	// Jira configuration flags
 	configCmd.Flags().StringVar(&jiraURL, "jira-url", "", "Set Jira base URL (e.g., https://yourcompany.atlassian.net)")
@@ -51,49 +86,110 @@ func init() {
 }
 
 func runConfig(cmd *cobra.Command, args []string) error {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return fmt.Errorf("failed to get home directory: %w", err)
-	}
-
-	configPath := filepath.Join(home, ".gh-assistant.yaml")
-
 	// Show current config
 	if showConfig {
 		return showCurrentConfig()
 	}
 
 	// Load existing config
-	config := make(map[string]interface{})
-	if data, err := os.ReadFile(configPath); err == nil {
-		yaml.Unmarshal(data, &config)
+	config, err := loadConfigFile()
+	if err != nil {
+		return err
 	}
 
 	// Update config
 	updated := false
 
-	if apiKey != "" {
-		config["api_key"] = apiKey
-		updated = true
-		fmt.Println("✅ API key configured")
-	}
-
 	if providerArg != "" {
-		p := ai.Provider(providerArg)
-		if p != ai.ProviderOpenAI && p != ai.ProviderAnthropic {
-			return fmt.Errorf("invalid provider: %s (use 'openai' or 'anthropic')", providerArg)
+		if !validProvider(ai.Provider(providerArg)) {
+			return fmt.Errorf("invalid provider: %s (use %s)", providerArg, providerNames())
 		}
 		config["provider"] = providerArg
 		updated = true
 		fmt.Printf("✅ Provider set to: %s\n", providerArg)
 	}
 
+	if apiKey != "" {
+		provider := resolveProvider()
+		if providerArg != "" {
+			provider = ai.Provider(providerArg)
+		}
+		config[apiKeyName(provider)] = apiKey
+		updated = true
+		fmt.Printf("✅ %s API key configured\n", provider)
+	}
+
 	if modelArg != "" {
+		if err := checkOpenRouterModel(modelArg); err != nil {
+			return err
+		}
 		config["model"] = modelArg
 		updated = true
 		fmt.Printf("✅ Model set to: %s\n", modelArg)
 	}
 
+	if openaiURL != "" {
+		config["openai_base_url"] = openaiURL
+		updated = true
+		fmt.Printf("✅ OpenAI base URL set to: %s\n", openaiURL)
+	}
+
+	if ollamaHost != "" {
+		config["ollama_host"] = ollamaHost
+		updated = true
+		fmt.Printf("✅ Ollama host set to: %s\n", ollamaHost)
+	}
+
+	if azureURL != "" {
+		config["azure_endpoint"] = azureURL
+		updated = true
+		fmt.Printf("✅ Azure endpoint set to: %s\n", azureURL)
+	}
+
+	if azureDeploy != "" {
+		config["azure_deployment"] = azureDeploy
+		updated = true
+		fmt.Printf("✅ Azure deployment set to: %s\n", azureDeploy)
+	}
+
+	if cmd.Flags().Changed("temperature") {
+		if temperature < 0 || temperature > 2 {
+			return fmt.Errorf("invalid temperature: %g (use 0 to 2)", temperature)
+		}
+		config["temperature"] = temperature
+		updated = true
+		fmt.Printf("✅ Temperature set to: %g\n", temperature)
+	}
+
+	if cmd.Flags().Changed("top-p") {
+		if topP <= 0 || topP > 1 {
+			return fmt.Errorf("invalid top-p: %g (use a value above 0, up to 1)", topP)
+		}
+		config["top_p"] = topP
+		updated = true
+		fmt.Printf("✅ Top-p set to: %g\n", topP)
+	}
+
+	if cmd.Flags().Changed("max-tokens") {
+		if maxTokens < 1 {
+			return fmt.Errorf("invalid max-tokens: %d (use a positive number)", maxTokens)
+		}
+		config["max_tokens"] = maxTokens
+		updated = true
+		fmt.Printf("✅ Max tokens set to: %d\n", maxTokens)
+	}
+
+	if language != "" {
+		if strings.EqualFold(language, "english") || strings.EqualFold(language, "en") {
+			delete(config, "language")
+			language = "English (default)"
+		} else {
+			config["language"] = language
+		}
+		updated = true
+		fmt.Printf("✅ Language set to: %s\n", language)
+	}
+
 	// Jira configuration
 	if jiraURL != "" {
 		config["jira_url"] = jiraURL
@@ -124,7 +220,39 @@ func runConfig(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	// Save config
+	return saveConfigFile(config)
+}
+
+// configFilePath returns the path of the user config file
+func configFilePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".gh-assistant.yaml"), nil
+}
+
+// loadConfigFile reads the user config file, returning an empty map if it doesn't exist
+func loadConfigFile() (map[string]interface{}, error) {
+	configPath, err := configFilePath()
+	if err != nil {
+		return nil, err
+	}
+
+	config := make(map[string]interface{})
+	if data, err := os.ReadFile(configPath); err == nil {
+		yaml.Unmarshal(data, &config)
+	}
+	return config, nil
+}
+
+// saveConfigFile writes the config map back to the user config file
+func saveConfigFile(config map[string]interface{}) error {
+	configPath, err := configFilePath()
+	if err != nil {
+		return err
+	}
+
 	data, err := yaml.Marshal(config)
 	if err != nil {
 		return fmt.Errorf("failed to serialize config: %w", err)
@@ -138,6 +266,134 @@ func runConfig(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// checkOpenRouterModel validates a model against OpenRouter's model list when
+// OpenRouter is the provider, since its "vendor/model" IDs are easy to get
+// wrong. A list that can't be fetched only warns.
+func checkOpenRouterModel(model string) error {
+	provider := resolveProvider()
+	if providerArg != "" {
+		provider = ai.Provider(providerArg)
+	}
+	if provider != ai.ProviderOpenRouter {
+		return nil
+	}
+
+	cfg := aiConfig(nil, provider, providerAPIKey(provider))
+	cfg.Model = model
+	ctx, stop := aiContext()
+	err := ai.New(cfg).CheckModel(ctx)
+	stop()
+	var notFound *ai.ModelNotFoundError
+	if errors.As(err, &notFound) {
+		return err
+	}
+	if err != nil {
+		fmt.Printf("⚠️  Warning: Couldn't check the model against OpenRouter's list: %v\n", err)
+	}
+	return nil
+}
+
+// requireAPIKey returns the API key of the provider in use, or "" for a
+// provider that doesn't use one. With --mock-fallback, a missing key
+// switches this run to the mock provider instead of failing.
+func requireAPIKey() (string, error) {
+	provider := resolveProvider()
+	apiKey, err := providerCredentials(provider)
+	if err != nil && mockFallback {
+		fmt.Printf("🧪 No %s credentials configured, using the mock provider (--mock-fallback)\n", provider)
+		providerOverride = ai.ProviderMock
+		return "", nil
+	}
+	return apiKey, err
+}
+
+// providerCredentials returns the provider's API key, or an error explaining
+// how to configure its credentials
+func providerCredentials(provider ai.Provider) (string, error) {
+	if provider == ai.ProviderBedrock {
+		return "", requireAWSCredentials()
+	}
+	if !provider.UsesAPIKey() {
+		return "", nil
+	}
+	// Servers behind openai_base_url (LM Studio, vLLM) often need no key
+	if provider == ai.ProviderOpenAI && viper.GetString("openai_base_url") != "" {
+		return providerAPIKey(provider), nil
+	}
+	if apiKey := providerAPIKey(provider); apiKey != "" {
+		return apiKey, nil
+	}
+
+	env := strings.ToUpper(apiKeyName(provider))
+	return "", fmt.Errorf(`%s API key not configured. Set it up using one of:
+  1. Run: gh-assistant config --api-key YOUR_KEY --provider %s
+  2. Set environment variable: export %s=your_key`, provider, provider, env)
+}
+
+// providerOverride is the provider chosen for this run with push --provider
+var providerOverride ai.Provider
+
+// resolveProvider returns the provider chosen with --provider or in the
+// config, falling back to the provider implied by whichever API key is set
+func resolveProvider() ai.Provider {
+	if providerOverride != "" {
+		return providerOverride
+	}
+	provider := ai.Provider(viper.GetString("provider"))
+	if provider == "" {
+		provider = ai.ProviderOpenAI
+		if viper.GetString(apiKeyName(ai.ProviderAnthropic)) != "" {
+			provider = ai.ProviderAnthropic
+		} else if viper.GetString(apiKeyName(ai.ProviderOpenAI)) == "" {
+			// Otherwise the first provider with a key, e.g. MISTRAL_API_KEY
+			for _, p := range ai.Providers {
+				if p.UsesAPIKey() && viper.GetString(apiKeyName(p)) != "" {
+					provider = p
+					break
+				}
+			}
+		}
+	}
+	return provider
+}
+
+// apiKeyName returns the config key holding a provider's API key, e.g.
+// openai_api_key; the upper-cased name (OPENAI_API_KEY) is its environment
+// variable
+func apiKeyName(provider ai.Provider) string {
+	return string(provider) + "_api_key"
+}
+
+// validProvider reports whether p is one of ai.Providers
+func validProvider(p ai.Provider) bool {
+	for _, provider := range ai.Providers {
+		if p == provider {
+			return true
+		}
+	}
+	return false
+}
+
+// apiKeyVariables lists the API key environment variables for error messages
+func apiKeyVariables() string {
+	var names []string
+	for _, p := range ai.Providers {
+		if p.UsesAPIKey() {
+			names = append(names, strings.ToUpper(apiKeyName(p)))
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// providerNames lists the supported providers for error messages
+func providerNames() string {
+	names := make([]string, len(ai.Providers))
+	for i, p := range ai.Providers {
+		names[i] = "'" + string(p) + "'"
+	}
+	return strings.Join(names, ", ")
+}
+
 func showCurrentConfig() error {
 	fmt.Println("Current configuration:")
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
@@ -157,34 +413,53 @@ func showCurrentConfig() error {
 	// Provider
 	provider := viper.GetString("provider")
 	if provider == "" {
-		if os.Getenv("ANTHROPIC_API_KEY") != "" {
-			provider = "anthropic (from env)"
-		} else if os.Getenv("OPENAI_API_KEY") != "" {
-			provider = "openai (from env)"
-		} else {
-			provider = "not set"
+		provider = "not set"
+		if p := resolveProvider(); os.Getenv(strings.ToUpper(apiKeyName(p))) != "" {
+			provider = string(p) + " (from env)"
 		}
 	}
 	fmt.Printf("🤖 Provider: %s\n", provider)
 
-	// API Key
-	key := viper.GetString("api_key")
-	if key == "" {
-		key = os.Getenv("OPENAI_API_KEY")
-		if key == "" {
-			key = os.Getenv("ANTHROPIC_API_KEY")
+	// API keys, one per provider
+	for _, p := range ai.Providers {
+		if !p.UsesAPIKey() {
+			continue
 		}
+		if key := viper.GetString(apiKeyName(p)); key != "" {
+			fmt.Printf("🔑 %s API Key: %s\n", p, maskSecret(key))
+		} else {
+			fmt.Printf("🔑 %s API Key: not set\n", p)
+		}
+	}
+	if key := viper.GetString("api_key"); key != "" {
+		fmt.Printf("🔑 API Key (legacy, used for %s): %s\n", resolveProvider(), maskSecret(key))
 	}
-	if key != "" {
-		// Mask the key
-		if len(key) > 8 {
-			key = key[:4] + "..." + key[len(key)-4:]
+	if baseURL := viper.GetString("openai_base_url"); baseURL != "" {
+		fmt.Printf("🔗 OpenAI base URL: %s\n", baseURL)
+	}
+	if resolveProvider() == ai.ProviderOllama || viper.IsSet("ollama_host") {
+		fmt.Printf("🦙 Ollama host: %s\n", ai.OllamaURL(viper.GetString("ollama_host")))
+	}
+	if resolveProvider() == ai.ProviderAzure {
+		endpoint := viper.GetString("azure_endpoint")
+		if endpoint == "" {
+			endpoint = "not set"
+		}
+		deployment := viper.GetString("azure_deployment")
+		if deployment == "" {
+			deployment = "not set (uses the model name)"
+		}
+		fmt.Printf("☁️  Azure endpoint: %s\n", endpoint)
+		fmt.Printf("☁️  Azure deployment: %s\n", deployment)
+	}
+	if resolveProvider() == ai.ProviderBedrock {
+		creds := awsCredentials()
+		if creds.AccessKeyID != "" {
+			fmt.Printf("🔑 AWS access key: %s\n", maskSecret(creds.AccessKeyID))
 		} else {
-			key = "****"
+			fmt.Println("🔑 AWS access key: not set")
 		}
-		fmt.Printf("🔑 API Key: %s\n", key)
-	} else {
-		fmt.Println("🔑 API Key: not set")
+		fmt.Printf("🌎 AWS region: %s\n", awsRegion())
 	}
 
 	// Model
@@ -193,12 +468,19 @@ func showCurrentConfig() error {
 		model = "default"
 	}
 	fmt.Printf("📦 Model: %s\n", model)
+	if params := generationParams(); params != "" {
+		fmt.Printf("🎛️  Parameters: %s\n", params)
+	}
+	if lang := viper.GetString("language"); lang != "" {
+		fmt.Printf("🌐 Language: %s\n", lang)
+	}
 
 	fmt.Println()
 	fmt.Println("Jira Integration:")
+	jiraCfg := jiraConfig()
 
 	// Jira URL
-	jURL := viper.GetString("jira_url")
+	jURL := jiraCfg.BaseURL
 	if jURL != "" {
 		fmt.Printf("🔗 Jira URL: %s\n", jURL)
 	} else {
@@ -206,7 +488,7 @@ func showCurrentConfig() error {
 	}
 
 	// Jira Email
-	jEmail := viper.GetString("jira_email")
+	jEmail := jiraCfg.Email
 	if jEmail != "" {
 		fmt.Printf("📧 Jira Email: %s\n", jEmail)
 	} else {
@@ -214,20 +496,15 @@ func showCurrentConfig() error {
 	}
 
 	// Jira Token
-	jToken := viper.GetString("jira_token")
+	jToken := jiraCfg.APIToken
 	if jToken != "" {
-		if len(jToken) > 8 {
-			jToken = jToken[:4] + "..." + jToken[len(jToken)-4:]
-		} else {
-			jToken = "****"
-		}
-		fmt.Printf("🔑 Jira Token: %s\n", jToken)
+		fmt.Printf("🔑 Jira Token: %s\n", maskSecret(jToken))
 	} else {
 		fmt.Println("🔑 Jira Token: not set")
 	}
 
 	// Jira Project
-	jProject := viper.GetString("jira_project")
+	jProject := jiraCfg.Project
 	if jProject != "" {
 		fmt.Printf("📋 Jira Project: %s\n", jProject)
 	} else {
@@ -239,3 +516,342 @@ func showCurrentConfig() error {
 	return nil
 }
 
+// newAIClient builds an AI client for the provider and key, applying the
+// remaining settings from the loaded configuration. A provider other than
+// the configured one (e.g. push --provider) gets its default model.
+func newAIClient(g *git.Git, provider ai.Provider, apiKey string) *ai.Client {
+	if configured := viper.GetString("provider"); configured != "" && provider != ai.Provider(configured) {
+		return newAlternateClient(g, provider, apiKey)
+	}
+	return ai.New(aiConfig(g, provider, apiKey))
+}
+
+// providerAPIKey returns the API key for a provider from its own setting
+// (e.g. openai_api_key or OPENAI_API_KEY), falling back to the legacy
+// api_key for the provider in use
+func providerAPIKey(provider ai.Provider) string {
+	if apiKey := viper.GetString(apiKeyName(provider)); apiKey != "" {
+		return apiKey
+	}
+	if provider == resolveProvider() {
+		return viper.GetString("api_key")
+	}
+	return ""
+}
+
+// newAlternateClient builds a client for a provider other than the configured
+// one. The model settings belong to the configured provider, so the
+// provider's default model is used.
+func newAlternateClient(g *git.Git, provider ai.Provider, apiKey string) *ai.Client {
+	cfg := aiConfig(g, provider, apiKey)
+	cfg.Model = ""
+	cfg.ModelTiers = nil
+	return ai.New(cfg)
+}
+
+// aiLimiter, when set, is shared by every AI client to bound concurrent
+// requests (push --recursive --concurrency)
+var aiLimiter *ai.Limiter
+
+// aiConfig builds the AI client configuration from the loaded configuration
+// for requests about g's repository (nil when there is none)
+func aiConfig(g *git.Git, provider ai.Provider, apiKey string) ai.Config {
+	return ai.Config{
+		Provider:         provider,
+		APIKey:           apiKey,
+		Model:            viper.GetString("model"),
+		AnthropicVersion: viper.GetString("anthropic_version"),
+		AnthropicBeta:    viper.GetString("anthropic_beta"),
+		OpenAIBaseURL:    viper.GetString("openai_base_url"),
+		OpenAIHeaders:    viper.GetStringMapString("openai_headers"),
+		OllamaHost:       viper.GetString("ollama_host"),
+		AzureEndpoint:    viper.GetString("azure_endpoint"),
+		AzureDeployment:  viper.GetString("azure_deployment"),
+		AzureAPIVersion:  viper.GetString("azure_api_version"),
+		AWSRegion:        awsRegion(),
+		AWS:              awsCredentials(),
+		MaxTokens:        viper.GetInt("max_tokens"),
+		Temperature:      optionalFloat("temperature"),
+		TopP:             optionalFloat("top_p"),
+		MaxAttempts:      viper.GetInt("retry_max_attempts"),
+		OnRetry:          printRetry,
+		CheckCost:        budgetGuard(),
+		ModelTiers:       modelTiers(),
+		Style:            ai.Style(viper.GetString("commit_style")),
+		GitmojiMap:       gitmojiMap(),
+		EnforceGitmoji:   viper.GetBool("gitmoji_enforce"),
+		Truncation:       truncationStrategy(),
+		ContextWindows:   contextWindows(),
+		VerbForms:        verbForms(),
+		Limiter:          aiLimiter,
+		Bullet:           bodyBullet(),
+		ForbiddenWords:   viper.GetStringSlice("forbidden_words"),
+		RequiredSections: viper.GetStringSlice("required_sections"),
+
+		PriorityExtensions:  viper.GetStringSlice("priority_extensions"),
+		OpenRouterFallbacks: viper.GetStringSlice("openrouter_fallbacks"),
+		PromptTemplate:      promptTemplatePath(g),
+		BulletBody:          bulletBody(),
+		BodyWidth:           viper.GetInt("max_body_line_length"),
+		Language:            viper.GetString("language"),
+	}
+}
+
+// promptTemplatePath resolves commit_prompt_template. "~/" is the home
+// directory, and a relative path is relative to the root of g's repository
+// (or the current directory without one), so teams can commit theirs and
+// push --recursive uses each repository's own.
+func promptTemplatePath(g *git.Git) string {
+	path := viper.GetString("commit_prompt_template")
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	if g == nil {
+		return path
+	}
+	if root, err := g.Toplevel(); err == nil {
+		return filepath.Join(root, path)
+	}
+	return path
+}
+
+// optionalFloat reads a numeric setting, or nil when it isn't set, so that
+// 0 can be configured
+func optionalFloat(key string) *float64 {
+	if !viper.IsSet(key) {
+		return nil
+	}
+	value := viper.GetFloat64(key)
+	return &value
+}
+
+// generationParams describes the configured model parameters, e.g.
+// "temperature 0.2, top_p 0.9", or "" when all are left to the defaults
+func generationParams() string {
+	var params []string
+	for _, key := range []string{"temperature", "top_p", "max_tokens"} {
+		if viper.IsSet(key) {
+			params = append(params, fmt.Sprintf("%s %v", key, viper.Get(key)))
+		}
+	}
+	return strings.Join(params, ", ")
+}
+
+// printRetry reports a failed AI request that is about to be retried
+func printRetry(attempt, maxAttempts int, wait time.Duration, reason string) {
+	fmt.Printf("⏳ %s, retrying in %s (attempt %d of %d)...\n",
+		reason, wait.Round(100*time.Millisecond), attempt, maxAttempts)
+}
+
+// awsCredentials reads the Bedrock credentials from aws_access_key_id,
+// aws_secret_access_key and aws_session_token, or the AWS_* variables
+func awsCredentials() ai.AWSCredentials {
+	return ai.AWSCredentials{
+		AccessKeyID:     viper.GetString("aws_access_key_id"),
+		SecretAccessKey: viper.GetString("aws_secret_access_key"),
+		SessionToken:    viper.GetString("aws_session_token"),
+	}
+}
+
+// awsRegion reads aws_region (AWS_REGION), falling back to AWS_DEFAULT_REGION
+func awsRegion() string {
+	if region := viper.GetString("aws_region"); region != "" {
+		return region
+	}
+	return os.Getenv("AWS_DEFAULT_REGION")
+}
+
+// requireAWSCredentials checks that Bedrock requests can be signed
+func requireAWSCredentials() error {
+	creds := awsCredentials()
+	if creds.AccessKeyID != "" && creds.SecretAccessKey != "" {
+		return nil
+	}
+	return fmt.Errorf(`AWS credentials not configured for bedrock. Set them up using one of:
+  1. Set environment variables: export AWS_ACCESS_KEY_ID=... AWS_SECRET_ACCESS_KEY=...
+  2. Add aws_access_key_id and aws_secret_access_key to the config file`)
+}
+
+// bodyOverride is push --body when given for this run
+var bodyOverride *bool
+
+// bulletBody reports whether generated messages get a bulleted body: push
+// --body when given, otherwise commit_body
+func bulletBody() bool {
+	if bodyOverride != nil {
+		return *bodyOverride
+	}
+	return viper.GetBool("commit_body")
+}
+
+// bodyBullet reads body_bullet, warning about markers other than "-" and "*"
+func bodyBullet() string {
+	bullet := viper.GetString("body_bullet")
+	if bullet != "" && bullet != "-" && bullet != "*" {
+		fmt.Printf("⚠️  Warning: Unknown body_bullet %q, using \"-\"\n", bullet)
+		return "-"
+	}
+	return bullet
+}
+
+// verbForms returns the non-imperative verb forms to rewrite when
+// imperative_mood is enabled, with imperative_verbs added, or nil
+func verbForms() map[string]string {
+	if !viper.GetBool("imperative_mood") {
+		return nil
+	}
+	return commitmsg.VerbForms(viper.GetStringMapString("imperative_verbs"))
+}
+
+// contextWindows reads context_windows, a map of model name prefix to tokens
+func contextWindows() map[string]int {
+	var windows map[string]int
+	if err := viper.UnmarshalKey("context_windows", &windows); err != nil {
+		fmt.Printf("⚠️  Warning: Ignoring invalid context_windows config: %v\n", err)
+		return nil
+	}
+	return windows
+}
+
+// truncationStrategy reads truncation_strategy, warning about unknown values
+func truncationStrategy() ai.TruncationStrategy {
+	strategy := ai.TruncationStrategy(strings.ToLower(viper.GetString("truncation_strategy")))
+	if strategy == "" {
+		return ai.TruncateHead
+	}
+	for _, s := range ai.TruncationStrategies {
+		if s == strategy {
+			return strategy
+		}
+	}
+	fmt.Printf("⚠️  Warning: Unknown truncation_strategy %q, using head\n", strategy)
+	return ai.TruncateHead
+}
+
+// gitmojiMap returns the default gitmoji mapping with gitmoji_map overrides applied
+func gitmojiMap() map[string]string {
+	mapping := make(map[string]string)
+	for t, emoji := range commitmsg.DefaultGitmojiMap {
+		mapping[t] = emoji
+	}
+	for t, emoji := range viper.GetStringMapString("gitmoji_map") {
+		mapping[strings.ToLower(t)] = emoji
+	}
+	return mapping
+}
+
+// jiraBranchRules reads jira_branch_rules, a list of
+// {pattern, project, issue_type, labels}
+func jiraBranchRules() []jira.BranchRule {
+	var rules []jira.BranchRule
+	if err := viper.UnmarshalKey("jira_branch_rules", &rules); err != nil {
+		fmt.Printf("⚠️  Warning: Ignoring invalid jira_branch_rules config: %v\n", err)
+		return nil
+	}
+	return rules
+}
+
+// modelTiers reads the model_tiers config, a list of {max_lines, model}
+func modelTiers() []ai.ModelTier {
+	var raw []struct {
+		MaxLines int    `mapstructure:"max_lines"`
+		Model    string `mapstructure:"model"`
+	}
+	if err := viper.UnmarshalKey("model_tiers", &raw); err != nil {
+		fmt.Printf("⚠️  Warning: Ignoring invalid model_tiers config: %v\n", err)
+		return nil
+	}
+
+	tiers := make([]ai.ModelTier, 0, len(raw))
+	for _, t := range raw {
+		tiers = append(tiers, ai.ModelTier{MaxLines: t.MaxLines, Model: t.Model})
+	}
+	return tiers
+}
+
+// newJiraClient builds a Jira client from the loaded configuration
+func newJiraClient() *jira.Client {
+	cfg := jiraConfig()
+	cfg.Transitions = stateTransitionCache{}
+	return jira.New(cfg)
+}
+
+// stateTransitionCache keeps Jira transition IDs in the state file, so
+// transitions don't need a lookup request on every run
+type stateTransitionCache struct{}
+
+func (stateTransitionCache) TransitionID(key string) (string, bool) {
+	st, err := state.Load()
+	if err != nil {
+		return "", false
+	}
+	id, ok := st.JiraTransitions[key]
+	return id, ok
+}
+
+func (stateTransitionCache) SetTransitionID(key, id string) {
+	updateTransitionCache(func(transitions map[string]string) { transitions[key] = id })
+}
+
+func (stateTransitionCache) ForgetTransitionID(key string) {
+	updateTransitionCache(func(transitions map[string]string) { delete(transitions, key) })
+}
+
+// updateTransitionCache applies update to the cached transition IDs and
+// saves the state file; failures only lose the cache entry
+func updateTransitionCache(update func(map[string]string)) {
+	spendMu.Lock()
+	defer spendMu.Unlock()
+
+	st, err := state.Load()
+	if err != nil {
+		return
+	}
+	if st.JiraTransitions == nil {
+		st.JiraTransitions = map[string]string{}
+	}
+	update(st.JiraTransitions)
+	st.Save()
+}
+
+// jiraConfig returns the Jira settings from the loaded configuration, filling
+// any that aren't set from an existing jira-cli setup
+func jiraConfig() jira.Config {
+	cfg := jira.Config{
+		BaseURL:  viper.GetString("jira_url"),
+		Email:    viper.GetString("jira_email"),
+		APIToken: viper.GetString("jira_token"),
+		Project:  viper.GetString("jira_project"),
+
+		BranchRules:     jiraBranchRules(),
+		AcceptanceField: viper.GetString("jira_acceptance_field"),
+		KeySegment:      viper.GetInt("jira_key_segment"),
+	}
+	if cfg.BaseURL != "" && cfg.Email != "" && cfg.APIToken != "" && cfg.Project != "" {
+		return cfg
+	}
+
+	discovered, err := jira.DiscoverCLIConfig()
+	if err != nil {
+		fmt.Printf("⚠️  Warning: Ignoring jira-cli config: %v\n", err)
+	}
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = discovered.BaseURL
+	}
+	if cfg.Email == "" {
+		cfg.Email = discovered.Email
+	}
+	if cfg.APIToken == "" {
+		cfg.APIToken = discovered.APIToken
+	}
+	if cfg.Project == "" {
+		cfg.Project = discovered.Project
+	}
+	return cfg
+}
+
@@ -0,0 +1,280 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/namin2/gh-assistant/internal/ai"
+	"github.com/namin2/gh-assistant/internal/commitmsg"
+	"github.com/namin2/gh-assistant/internal/jira"
+	"github.com/namin2/gh-assistant/internal/patch"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// configKey describes a setting the tool reads from its configuration
+type configKey struct {
+	name string
+	// secret values are masked when displayed
+	secret bool
+	// fallback resolves the value when it isn't set in the env or config file,
+	// returning the value and a description of where it came from
+	fallback func() (interface{}, string)
+}
+
+// configKeys lists every known setting in display order
+var configKeys = []configKey{
+	{name: "provider", fallback: func() (interface{}, string) {
+		return string(resolveProvider()), "default (inferred from API key env)"
+	}},
+	{name: "openai_api_key", secret: true},
+	{name: "anthropic_api_key", secret: true},
+	{name: "gemini_api_key", secret: true},
+	{name: "azure_api_key", secret: true},
+	{name: "mistral_api_key", secret: true},
+	{name: "openrouter_api_key", secret: true},
+	{name: "api_key", secret: true},
+	{name: "model", fallback: func() (interface{}, string) {
+		return ai.New(ai.Config{Provider: resolveProvider()}).Model(), "default"
+	}},
+	{name: "model_tiers"},
+	{name: "anthropic_version", fallback: staticDefault("2023-06-01")},
+	{name: "anthropic_beta"},
+	{name: "openai_base_url", fallback: staticDefault(ai.DefaultOpenAIBaseURL)},
+	{name: "openai_headers"},
+	{name: "openrouter_fallbacks"},
+	{name: "ollama_host", fallback: staticDefault(ai.DefaultOllamaHost)},
+	{name: "azure_endpoint"},
+	{name: "azure_deployment"},
+	{name: "azure_api_version", fallback: staticDefault("2024-06-01")},
+	{name: "aws_access_key_id", secret: true},
+	{name: "aws_secret_access_key", secret: true},
+	{name: "aws_session_token", secret: true},
+	{name: "aws_region", fallback: func() (interface{}, string) {
+		if region := os.Getenv("AWS_DEFAULT_REGION"); region != "" {
+			return region, "env AWS_DEFAULT_REGION"
+		}
+		return "us-east-1", "default"
+	}},
+	{name: "max_tokens"},
+	{name: "temperature"},
+	{name: "top_p"},
+	{name: "retry_max_attempts", fallback: staticDefault(ai.DefaultMaxAttempts)},
+	{name: "offline_fallback", fallback: staticDefault("abort")},
+	{name: "auto_confirm", fallback: staticDefault(false)},
+	{name: "ignore_whitespace_hunks", fallback: staticDefault(false)},
+	{name: "concurrency", fallback: staticDefault(1)},
+	{name: "batch_fail_on_error", fallback: staticDefault(true)},
+	{name: "stream_output", fallback: staticDefault(true)},
+	{name: "truncation_strategy", fallback: staticDefault("head")},
+	{name: "context_windows"},
+	{name: "exclude_paths", fallback: staticDefault(patch.DefaultExcludes)},
+	{name: "mode_change_message", fallback: staticDefault("template")},
+	{name: "commit_style", fallback: staticDefault("conventional")},
+	{name: "gitmoji_map"},
+	{name: "gitmoji_enforce", fallback: staticDefault(false)},
+	{name: "commit_types", fallback: staticDefault(commitmsg.DefaultTypes)},
+	{name: "commit_scopes"},
+	{name: "require_scope", fallback: staticDefault(false)},
+	{name: "max_subject_length", fallback: staticDefault(commitmsg.DefaultMaxSubjectLength)},
+	{name: "max_body_line_length"},
+	{name: "body_bullet", fallback: staticDefault("-")},
+	{name: "commit_body", fallback: staticDefault(false)},
+	{name: "language"},
+	{name: "imperative_mood", fallback: staticDefault(false)},
+	{name: "imperative_verbs"},
+	{name: "cost_budget"},
+	{name: "cost_budget_period", fallback: staticDefault("monthly")},
+	{name: "commit_trailers"},
+	{name: "output_template"},
+	{name: "plain_summary", fallback: staticDefault(false)},
+	{name: "plain_summary_trailer", fallback: staticDefault("What-changed")},
+	{name: "forbidden_words"},
+	{name: "required_sections"},
+	{name: "priority_extensions"},
+	{name: "commit_prompt_template"},
+	{name: "verbatim_max_length", fallback: staticDefault(defaultVerbatimLength)},
+	{name: "split_generated", fallback: staticDefault(false)},
+	{name: "push_to_upstream", fallback: staticDefault(true)},
+	{name: "pr_jira_criteria", fallback: staticDefault(false)},
+	{name: "pr_review_checklist", fallback: staticDefault(false)},
+	{name: "jira_acceptance_field"},
+	{name: "generated_paths", fallback: staticDefault(patch.DefaultGenerated)},
+	{name: "branch_ticket_mode", fallback: staticDefault("off")},
+	{name: "base_branch"},
+	{name: "guard_ci", fallback: staticDefault(false)},
+	{name: "working_hours"},
+	{name: "working_days"},
+	{name: "github_token", secret: true, fallback: envFallback("GH_TOKEN")},
+	{name: "jira_url", fallback: jiraCLIFallback(func(c jira.Config) string { return c.BaseURL })},
+	{name: "jira_email", fallback: jiraCLIFallback(func(c jira.Config) string { return c.Email })},
+	{name: "jira_token", secret: true, fallback: envFallback("JIRA_API_TOKEN")},
+	{name: "jira_project", fallback: jiraCLIFallback(func(c jira.Config) string { return c.Project })},
+	{name: "jira_key_segment", fallback: staticDefault(0)},
+	{name: "jira_check_base", fallback: staticDefault(false)},
+	{name: "jira_ai_description", fallback: staticDefault(true)},
+	{name: "jira_seed_commit", fallback: staticDefault("last")},
+	{name: "jira_list_commits", fallback: staticDefault(false)},
+	{name: "jira_branch_label", fallback: staticDefault(true)},
+	{name: "jira_branch_rules"},
+}
+
+func staticDefault(v interface{}) func() (interface{}, string) {
+	return func() (interface{}, string) { return v, "default" }
+}
+
+// jiraCLIFallback resolves a Jira setting from the jira-cli config file
+func jiraCLIFallback(field func(jira.Config) string) func() (interface{}, string) {
+	return func() (interface{}, string) {
+		discovered, err := jira.DiscoverCLIConfig()
+		if err != nil || field(discovered) == "" {
+			return nil, ""
+		}
+		path, _ := jira.CLIConfigPath()
+		return field(discovered), "jira-cli " + path
+	}
+}
+
+// envFallback resolves a key from the first of the environment variables that is set
+func envFallback(names ...string) func() (interface{}, string) {
+	return func() (interface{}, string) {
+		for _, env := range names {
+			if v := os.Getenv(env); v != "" {
+				return v, "env " + env
+			}
+		}
+		return nil, ""
+	}
+}
+
+var configDumpCmd = &cobra.Command{
+	Use:   "dump",
+	Short: "Print the effective configuration and where each value comes from",
+	Long: `Prints the fully resolved configuration as YAML, with secrets redacted.
+Each value is annotated with its source: environment, config file, or default.`,
+	RunE: runConfigDump,
+}
+
+func init() {
+	configCmd.AddCommand(configDumpCmd)
+}
+
+func runConfigDump(cmd *cobra.Command, args []string) error {
+	doc := &yaml.Node{Kind: yaml.MappingNode}
+
+	for _, key := range configKeys {
+		value, source := resolveConfigKey(key)
+		if value == nil {
+			continue
+		}
+		if key.secret {
+			value = maskSecret(fmt.Sprint(value))
+		}
+
+		keyNode := &yaml.Node{Kind: yaml.ScalarNode, Value: key.name}
+		valueNode := &yaml.Node{}
+		if err := valueNode.Encode(value); err != nil {
+			return fmt.Errorf("failed to encode %s: %w", key.name, err)
+		}
+		if valueNode.Kind == yaml.ScalarNode {
+			valueNode.LineComment = source
+		} else {
+			keyNode.LineComment = source
+		}
+		doc.Content = append(doc.Content, keyNode, valueNode)
+	}
+
+	out, err := yaml.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("failed to serialize config: %w", err)
+	}
+	fmt.Print(string(out))
+	return nil
+}
+
+// resolveConfigKey returns the effective value of a key and its source
+func resolveConfigKey(key configKey) (interface{}, string) {
+	env := strings.ToUpper(key.name)
+	if os.Getenv(env) != "" {
+		return viper.Get(key.name), "env " + env
+	}
+	if viper.InConfig(key.name) {
+		return viper.Get(key.name), "file " + viper.ConfigFileUsed()
+	}
+	if key.fallback != nil {
+		return key.fallback()
+	}
+	return nil, ""
+}
+
+// maskSecret hides all but the first and last four characters of a secret
+func maskSecret(secret string) string {
+	if len(secret) > 8 {
+		return secret[:4] + "..." + secret[len(secret)-4:]
+	}
+	return "****"
+}
+
+// warnUnknownConfigKeys warns about top-level keys in the config file that
+// no setting uses, which are usually typos like jira_porject. They are only
+// warned about, so config files written for newer versions still load.
+func warnUnknownConfigKeys() {
+	known := make(map[string]bool, len(configKeys))
+	for _, key := range configKeys {
+		known[key.name] = true
+	}
+
+	seen := make(map[string]bool)
+	var unknown []string
+	for _, key := range viper.AllKeys() {
+		top, _, _ := strings.Cut(key, ".")
+		if !known[top] && !seen[top] && viper.InConfig(top) {
+			seen[top] = true
+			unknown = append(unknown, top)
+		}
+	}
+	sort.Strings(unknown)
+
+	for _, key := range unknown {
+		warning := fmt.Sprintf("⚠️  Warning: Unknown config key %q in %s", key, viper.ConfigFileUsed())
+		if suggestion := closestConfigKey(key); suggestion != "" {
+			warning += fmt.Sprintf(" (did you mean %q?)", suggestion)
+		}
+		fmt.Fprintln(os.Stderr, warning)
+	}
+}
+
+// closestConfigKey returns the known key within two edits of key, or ""
+func closestConfigKey(key string) string {
+	best, bestDistance := "", 3
+	for _, known := range configKeys {
+		if d := editDistance(key, known.name); d < bestDistance {
+			best, bestDistance = known.name, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// commitDateLayouts are the absolute --date formats, tried in order. Layouts
+// without an offset are read in the local time zone.
+var commitDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// relativeDatePattern matches git's relative dates such as "2 days ago" or
+// "3.hours.ago"
+var relativeDatePattern = regexp.MustCompile(`^(\d+)[ .](second|minute|hour|day|week|month|year)s?[ .]ago$`)
+
+// parseCommitDate turns a --date value into the time it names, relative to
+// now. It accepts the layouts in commitDateLayouts, "now", "yesterday" and
+// "<n> <unit>s ago", and rejects dates in the future.
+func parseCommitDate(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	lower := strings.ToLower(value)
+
+	var date time.Time
+	switch {
+	case lower == "now":
+		date = now
+	case lower == "yesterday":
+		date = now.AddDate(0, 0, -1)
+	case relativeDatePattern.MatchString(lower):
+		m := relativeDatePattern.FindStringSubmatch(lower)
+		n, err := strconv.Atoi(m[1])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid date %q: %w", value, err)
+		}
+		date = subtractUnits(now, n, m[2])
+	default:
+		parsed := false
+		for _, layout := range commitDateLayouts {
+			if t, err := time.ParseInLocation(layout, value, now.Location()); err == nil {
+				date, parsed = t, true
+				break
+			}
+		}
+		if !parsed {
+			return time.Time{}, fmt.Errorf("invalid date %q (use RFC3339 like 2024-05-01T14:30:00+02:00, YYYY-MM-DD [HH:MM[:SS]], \"yesterday\" or \"<n> <unit>s ago\")", value)
+		}
+	}
+
+	if date.After(now) {
+		return time.Time{}, fmt.Errorf("date %q is in the future", value)
+	}
+	return date, nil
+}
+
+// subtractUnits returns t moved n units (second, minute, ..., year) back
+func subtractUnits(t time.Time, n int, unit string) time.Time {
+	switch unit {
+	case "second":
+		return t.Add(-time.Duration(n) * time.Second)
+	case "minute":
+		return t.Add(-time.Duration(n) * time.Minute)
+	case "hour":
+		return t.Add(-time.Duration(n) * time.Hour)
+	case "day":
+		return t.AddDate(0, 0, -n)
+	case "week":
+		return t.AddDate(0, 0, -7*n)
+	case "month":
+		return t.AddDate(0, -n, 0)
+	default:
+		return t.AddDate(-n, 0, 0)
+	}
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCommitDate(t *testing.T) {
+	zone := time.FixedZone("CEST", 2*60*60)
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, zone)
+
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"2024-05-01T14:30:00+02:00", time.Date(2024, 5, 1, 14, 30, 0, 0, zone)},
+		{"2024-05-01T12:30:00Z", time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)},
+		{"2024-05-01 14:30:00 -0500", time.Date(2024, 5, 1, 14, 30, 0, 0, time.FixedZone("", -5*60*60))},
+		{"2024-05-01 14:30:15", time.Date(2024, 5, 1, 14, 30, 15, 0, zone)},
+		{"2024-05-01 14:30", time.Date(2024, 5, 1, 14, 30, 0, 0, zone)},
+		{"2024-05-01", time.Date(2024, 5, 1, 0, 0, 0, 0, zone)},
+		{"  2024-05-01  ", time.Date(2024, 5, 1, 0, 0, 0, 0, zone)},
+		{"now", now},
+		{"Yesterday", now.AddDate(0, 0, -1)},
+		{"30 seconds ago", now.Add(-30 * time.Second)},
+		{"1 minute ago", now.Add(-time.Minute)},
+		{"3.hours.ago", now.Add(-3 * time.Hour)},
+		{"2 days ago", now.AddDate(0, 0, -2)},
+		{"2 Weeks Ago", now.AddDate(0, 0, -14)},
+		{"1 month ago", now.AddDate(0, -1, 0)},
+		{"5 years ago", now.AddDate(-5, 0, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseCommitDate(tt.value, now)
+			if err != nil {
+				t.Fatalf("parseCommitDate(%q) error = %v", tt.value, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("parseCommitDate(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCommitDateRejects(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		err   string
+	}{
+		{"", "invalid date"},
+		{"last tuesday", "invalid date"},
+		{"2 fortnights ago", "invalid date"},
+		{"-2 days ago", "invalid date"},
+		{"2 days", "invalid date"},
+		{"in 2 days", "invalid date"},
+		{"05/01/2024", "invalid date"},
+		{"2024-13-01", "invalid date"},
+		{"2024-05-01T14:30:00", "invalid date"},
+		{"99999999999999999999 days ago", "invalid date"},
+		{"2024-05-11", "in the future"},
+		{"2024-05-10T12:00:01Z", "in the future"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseCommitDate(tt.value, now)
+			if err == nil {
+				t.Fatalf("parseCommitDate(%q) = %v, want an error", tt.value, got)
+			}
+			if !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("parseCommitDate(%q) error = %q, want it to mention %q", tt.value, err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/namin2/gh-assistant/internal/git"
+	"github.com/spf13/viper"
+)
+
+// previewPush prints what push would do after the commit: the push itself,
+// new tags, the pull request and the Jira ticket. It changes nothing and
+// makes no GitHub or Jira requests.
+func previewPush(g *git.Git, message string, isFirstPush, isMainBranch bool) {
+	branch, _ := g.GetCurrentBranch()
+	remote, _ := g.GetRemote()
+
+	fmt.Println()
+	upstreamRemote, upstream, ok := g.Upstream()
+	usesUpstream := !viper.IsSet("push_to_upstream") || viper.GetBool("push_to_upstream")
+	if isFirstPush {
+		fmt.Printf("🧪 Would push %s to %s and set it as the upstream branch\n", branch, remote)
+	} else if ok && usesUpstream && (upstream != branch || upstreamRemote != remote) {
+		fmt.Printf("🧪 Would push %s to %s/%s (its upstream)\n", branch, upstreamRemote, upstream)
+	} else {
+		fmt.Printf("🧪 Would push %s to %s\n", branch, remote)
+	}
+
+	if pushTags {
+		tags, err := g.UnpushedTags()
+		switch {
+		case err != nil:
+			fmt.Printf("⚠️  Warning: Could not list tags: %v\n", err)
+		case len(tags) == 0:
+			fmt.Println("🧪 Would push no tags (none are new)")
+		default:
+			fmt.Printf("🧪 Would push %d new tag(s): %s\n", len(tags), strings.Join(tags, ", "))
+		}
+	}
+
+	if openPR || draftPR {
+		previewPullRequest(g, message, draftPR)
+	}
+
+	if isFirstPush && !isMainBranch && linkedIssue == "" {
+		previewBranchIssue(g, branch, message)
+	}
+
+	fmt.Println()
+	fmt.Println("🧪 Dry run: nothing was committed or pushed")
+}
+
+// previewAutosquash prints the --fixup or --squash commit push would create
+// and returns its message
+func previewAutosquash(g *git.Git) (string, error) {
+	kind, target := "fixup!", fixupCommit
+	if target == "" {
+		kind, target = "squash!", squashCommit
+	}
+	commits, err := g.GetCommits(target + "^!")
+	if err != nil {
+		return "", fmt.Errorf("failed to find commit %s: %w", target, err)
+	}
+	if len(commits) == 0 {
+		return "", fmt.Errorf("commit %s not found", target)
+	}
+
+	message := kind + " " + commits[0].Subject
+	fmt.Printf("🧪 Would commit: %s\n", message)
+	return message, nil
+}
+
+// previewPullRequest prints the pull request push would open
+func previewPullRequest(g *git.Git, message string, draft bool) {
+	repo, pr, err := newPullRequest(g, message, draft)
+	if err != nil {
+		fmt.Printf("⚠️  Warning: Would fail to open pull request: %v\n", err)
+		return
+	}
+
+	kind := "pull request"
+	if draft {
+		kind = "draft pull request"
+	}
+	fmt.Printf("🧪 Would open a %s in %s/%s:\n", kind, repo.Owner, repo.Name)
+	fmt.Printf("   Title: %s\n", pr.Title)
+	fmt.Printf("   Base:  %s <- %s\n", pr.Base, pr.Head)
+	if prCriteria {
+		fmt.Printf("   Body:  AI summary of the changes since %s, checked against the Jira issue's acceptance criteria\n", pr.Base)
+	} else {
+		fmt.Printf("   Body:  AI summary of the changes since %s\n", pr.Base)
+	}
+	if prChecklist {
+		fmt.Println("          followed by an AI-written review checklist")
+	}
+	if githubToken() == "" {
+		fmt.Println("⚠️  Warning: No GitHub token is set, so opening it would fail")
+	}
+}
+
+// previewBranchIssue prints the Jira ticket push would create for the branch
+func previewBranchIssue(g *git.Git, branch, message string) {
+	jiraClient := newJiraClient()
+	if !jiraClient.IsConfigured() {
+		return
+	}
+
+	rule := jiraClient.RuleFor(branch)
+	var labels []string
+	if label := branchLabel(branch); label != "" {
+		labels = append(labels, label)
+	}
+	labels = append(labels, rule.Labels...)
+
+	fmt.Println("🧪 Would create a Jira ticket, unless one already exists for the branch:")
+	fmt.Printf("   Summary: %s\n", subjectLine(message))
+	fmt.Printf("   Project: %s\n", rule.Project)
+	fmt.Printf("   Type:    %s\n", rule.IssueType)
+	if len(labels) > 0 {
+		fmt.Printf("   Labels:  %s\n", strings.Join(labels, ", "))
+	}
+	if !viper.IsSet("jira_ai_description") || viper.GetBool("jira_ai_description") {
+		fmt.Println("   Description: AI summary of the branch's changes")
+	}
+	fmt.Println("   Then move it to In Progress")
+}
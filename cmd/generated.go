@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/namin2/gh-assistant/internal/git"
+	"github.com/namin2/gh-assistant/internal/patch"
+	"github.com/spf13/viper"
+)
+
+// maxListedGenerated bounds the file list in a regenerate commit's body
+const maxListedGenerated = 20
+
+// generatedSplit holds staged generated-file changes taken out of the index
+// so the hand-written changes can be committed on their own
+type generatedSplit struct {
+	paths     []string
+	patch     string
+	committed bool
+}
+
+// generatedPatterns reads generated_paths, defaulting to common generator output
+func generatedPatterns() []string {
+	if viper.IsSet("generated_paths") {
+		return viper.GetStringSlice("generated_paths")
+	}
+	return patch.DefaultGenerated
+}
+
+// splitGenerated unstages the staged files matching generated_paths when
+// hand-written changes are staged too, keeping their staged patch to commit
+// afterwards. It returns nil when there is nothing to split.
+func splitGenerated(g *git.Git) (*generatedSplit, error) {
+	files, err := g.GetStagedFiles()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list staged files: %w", err)
+	}
+
+	patterns := generatedPatterns()
+	var generated []string
+	for _, file := range files {
+		if patch.IsExcluded(file, patterns) {
+			generated = append(generated, file)
+		}
+	}
+	if len(generated) == 0 || len(generated) == len(files) {
+		return nil, nil
+	}
+
+	if dryRun {
+		fmt.Printf("🧪 Would commit %d generated file(s) separately as: %s\n",
+			len(generated), strings.SplitN(generatedMessage(generated), "\n", 2)[0])
+		return nil, nil
+	}
+
+	staged, err := g.StagedPatch(generated...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save generated changes: %w", err)
+	}
+	if err := g.Unstage(generated...); err != nil {
+		return nil, fmt.Errorf("failed to unstage generated files: %w", err)
+	}
+	fmt.Printf("🏭 Committing %d generated file(s) separately\n", len(generated))
+	return &generatedSplit{paths: generated, patch: staged}, nil
+}
+
+// commit restages the generated changes and commits them with a
+// chore: regenerate message
+func (s *generatedSplit) commit(g *git.Git) error {
+	if err := g.ApplyToIndex(s.patch); err != nil {
+		return fmt.Errorf("failed to restage generated files: %w", err)
+	}
+	s.committed = true
+
+	message, err := applyCommitTrailers(g, generatedMessage(s.paths), linkedIssue)
+	if err != nil {
+		return fmt.Errorf("failed to add commit trailers: %w", err)
+	}
+	return commitStaged(g, message)
+}
+
+// restore puts the generated changes back in the index when push stopped
+// before committing them
+func (s *generatedSplit) restore(g *git.Git) {
+	if s == nil || s.committed {
+		return
+	}
+	if err := g.ApplyToIndex(s.patch); err != nil {
+		fmt.Printf("⚠️  Warning: couldn't restage generated files (%s): %v\n", strings.Join(s.paths, ", "), err)
+	}
+}
+
+// generatedMessage describes regenerated files without asking the AI, which
+// has little to say about generator output
+func generatedMessage(paths []string) string {
+	subject := fmt.Sprintf("chore: regenerate %d generated files", len(paths))
+	if len(paths) == 1 {
+		subject = "chore: regenerate " + path.Base(paths[0])
+	} else if dir := commonDir(paths); dir != "" {
+		subject = fmt.Sprintf("chore: regenerate %d files in %s", len(paths), dir)
+	}
+
+	bullet := bodyBullet()
+	if bullet == "" {
+		bullet = "-"
+	}
+	var body strings.Builder
+	for i, p := range paths {
+		if i == maxListedGenerated {
+			fmt.Fprintf(&body, "\n%s ... and %d more", bullet, len(paths)-i)
+			break
+		}
+		if i > 0 {
+			body.WriteString("\n")
+		}
+		fmt.Fprintf(&body, "%s %s", bullet, p)
+	}
+	return subject + "\n\n" + body.String()
+}
+
+// commonDir returns the deepest directory containing every path, or "" when
+// they only share the repository root
+func commonDir(paths []string) string {
+	dir := path.Dir(paths[0])
+	for _, p := range paths[1:] {
+		for dir != "." && dir != "/" && !strings.HasPrefix(p, dir+"/") {
+			dir = path.Dir(dir)
+		}
+	}
+	if dir == "." || dir == "/" {
+		return ""
+	}
+	return dir
+}
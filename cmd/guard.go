@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// pushGuardReasons returns why pushing right now needs extra confirmation.
+// Both checks are opt-in: guard_ci, and working_hours/working_days.
+func pushGuardReasons(now time.Time) ([]string, error) {
+	var reasons []string
+
+	if viper.GetBool("guard_ci") && isCI() {
+		reasons = append(reasons, "running in a CI environment")
+	}
+
+	if hours := viper.GetString("working_hours"); hours != "" {
+		inside, err := withinWorkingHours(hours, now)
+		if err != nil {
+			return nil, err
+		}
+		if !inside {
+			reasons = append(reasons, fmt.Sprintf("outside working hours (%s)", hours))
+		}
+	}
+
+	if days := viper.GetStringSlice("working_days"); len(days) > 0 && !isWorkingDay(days, now) {
+		reasons = append(reasons, fmt.Sprintf("not a working day (%s)", now.Weekday()))
+	}
+
+	return reasons, nil
+}
+
+// checkPushGuard requires an explicit confirmation when a push guard applies.
+// Without a terminal to ask on, it refuses unless --force-time is given.
+func checkPushGuard() error {
+	reasons, err := pushGuardReasons(time.Now())
+	if err != nil {
+		return err
+	}
+	if len(reasons) == 0 || forceTime {
+		return nil
+	}
+
+	reason := strings.Join(reasons, ", ")
+	if !isInteractive() {
+		return fmt.Errorf("push blocked: %s (use --force-time to override)", reason)
+	}
+
+	fmt.Printf("⏰ Heads up: %s.\n", reason)
+	if !confirm("Push anyway?", false) {
+		return fmt.Errorf("push aborted: %s", reason)
+	}
+	return nil
+}
+
+// isCI reports whether the process runs in a CI environment
+func isCI() bool {
+	switch strings.ToLower(os.Getenv("CI")) {
+	case "true", "1", "yes":
+		return true
+	}
+	return false
+}
+
+// withinWorkingHours reports whether now falls in a "HH:MM-HH:MM" range.
+// Ranges that end before they start wrap past midnight.
+func withinWorkingHours(hours string, now time.Time) (bool, error) {
+	parts := strings.SplitN(hours, "-", 2)
+	if len(parts) != 2 {
+		return false, fmt.Errorf("invalid working_hours %q (use HH:MM-HH:MM)", hours)
+	}
+
+	start, err := time.Parse("15:04", strings.TrimSpace(parts[0]))
+	if err != nil {
+		return false, fmt.Errorf("invalid working_hours start %q: %w", parts[0], err)
+	}
+	end, err := time.Parse("15:04", strings.TrimSpace(parts[1]))
+	if err != nil {
+		return false, fmt.Errorf("invalid working_hours end %q: %w", parts[1], err)
+	}
+
+	minutes := now.Hour()*60 + now.Minute()
+	from := start.Hour()*60 + start.Minute()
+	to := end.Hour()*60 + end.Minute()
+
+	if from <= to {
+		return minutes >= from && minutes < to, nil
+	}
+	return minutes >= from || minutes < to, nil
+}
+
+// isWorkingDay reports whether now's weekday is in days ("mon", "Tuesday", ...)
+func isWorkingDay(days []string, now time.Time) bool {
+	today := strings.ToLower(now.Weekday().String())
+	for _, d := range days {
+		d = strings.ToLower(strings.TrimSpace(d))
+		if len(d) >= 3 && strings.HasPrefix(today, d) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+var jiraCmd = &cobra.Command{
+	Use:   "jira",
+	Short: "Work with Jira issues",
+}
+
+var jiraSearchCmd = &cobra.Command{
+	Use:   "search <jql>",
+	Short: "List the Jira issues matching a JQL query",
+	Long: `Runs a JQL query and lists the matching issues with their key, status and
+summary (at most 50).
+
+Examples:
+  gh-assistant jira search "project = PROJ AND status = 'In Progress'"
+  gh-assistant jira search "assignee = currentUser() ORDER BY updated DESC"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runJiraSearch,
+}
+
+func init() {
+	rootCmd.AddCommand(jiraCmd)
+	jiraCmd.AddCommand(jiraSearchCmd)
+}
+
+func runJiraSearch(cmd *cobra.Command, args []string) error {
+	jiraClient := newJiraClient()
+	if !jiraClient.IsConfigured() {
+		return fmt.Errorf("jira is not configured (see 'gh-assistant config --help')")
+	}
+
+	issues, err := jiraClient.Search(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to search Jira: %w", err)
+	}
+	if len(issues) == 0 {
+		fmt.Println("🔍 No matching issues")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, issue := range issues {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", issue.Key, issue.Fields.Status.Name, issue.Fields.Summary)
+	}
+	return w.Flush()
+}
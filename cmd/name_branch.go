@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/namin2/gh-assistant/internal/ai"
+	"github.com/namin2/gh-assistant/internal/git"
+	"github.com/spf13/cobra"
+)
+
+var (
+	createBranch  bool
+	nameBranchYes bool
+)
+
+var nameBranchCmd = &cobra.Command{
+	Use:   "name-branch",
+	Short: "Propose a branch name for your changes",
+	Long: `Sends the staged diff (or the working tree diff if nothing is staged) to
+the AI and proposes a conventional branch name such as feat/login-oauth.
+
+Examples:
+  gh-assistant name-branch
+  gh-assistant name-branch --create      # Create and switch to the branch
+  gh-assistant name-branch --create -y   # Without asking first`,
+	Args: cobra.NoArgs,
+	RunE: runNameBranch,
+}
+
+func init() {
+	rootCmd.AddCommand(nameBranchCmd)
+	nameBranchCmd.Flags().BoolVarP(&createBranch, "create", "c", false, "Create the proposed branch and switch to it, keeping your changes")
+	nameBranchCmd.Flags().BoolVarP(&nameBranchYes, "yes", "y", false, "Create the branch without asking for confirmation")
+}
+
+func runNameBranch(cmd *cobra.Command, args []string) error {
+	if _, err := git.CheckInstalled(); err != nil {
+		return err
+	}
+	nameBranchYes = assumeYes(cmd, nameBranchYes)
+
+	g := git.New("")
+	if !g.IsRepo() {
+		return fmt.Errorf("not a git repository")
+	}
+
+	diff, err := g.GetStagedDiff()
+	if err != nil {
+		return fmt.Errorf("failed to get staged diff: %w", err)
+	}
+	if diff == "" {
+		diff, err = g.GetUnstagedDiff()
+		if err != nil {
+			return fmt.Errorf("failed to get diff: %w", err)
+		}
+	}
+	if diff == "" {
+		return fmt.Errorf("no changes to name a branch after")
+	}
+
+	apiKey, err := requireAPIKey()
+	if err != nil {
+		return err
+	}
+
+	req := ai.CommitRequest{Diff: diff}
+	excludeGenerated(&req)
+
+	fmt.Println("🤖 Proposing a branch name...")
+	aiClient := newAIClient(g, resolveProvider(), apiKey)
+	ctx, stop := aiContext()
+	name, err := aiClient.GenerateBranchName(ctx, req.Diff)
+	stop()
+	recordSpend(aiClient)
+	if err != nil {
+		return fmt.Errorf("failed to generate branch name: %w", err)
+	}
+
+	fmt.Printf("🌿 %s\n", name)
+	if !createBranch {
+		fmt.Printf("💡 Create it with: git checkout -b %s\n", name)
+		return nil
+	}
+
+	if !nameBranchYes && !confirm(fmt.Sprintf("Create branch %s?", name), true) {
+		fmt.Println("❌ Aborted")
+		return nil
+	}
+	if err := g.CreateBranch(name); err != nil {
+		return fmt.Errorf("failed to create branch: %w", err)
+	}
+	fmt.Printf("✅ Switched to new branch %s\n", name)
+	return nil
+}
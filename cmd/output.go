@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/namin2/gh-assistant/internal/ai"
+	"github.com/namin2/gh-assistant/internal/git"
+	"github.com/spf13/viper"
+)
+
+// pushSummary is the data available to output_template
+type pushSummary struct {
+	Message string
+	Hash    string
+	Branch  string
+	JiraKey string
+	JiraURL string
+}
+
+// printPushSummary renders output_template after a successful push. Nothing
+// is printed when it isn't set, leaving the usual status lines as the output.
+func printPushSummary(g *git.Git, message, jiraKey string) {
+	text := viper.GetString("output_template")
+	if text == "" {
+		return
+	}
+
+	tmpl, err := template.New("output_template").Parse(text)
+	if err != nil {
+		fmt.Printf("⚠️  Warning: Invalid output_template: %v\n", err)
+		return
+	}
+
+	summary := pushSummary{Message: message, JiraKey: jiraKey}
+	summary.Hash, _ = g.HeadCommit()
+	summary.Branch, _ = g.GetCurrentBranch()
+	if jiraKey != "" {
+		summary.JiraURL = newJiraClient().GetIssueURL(jiraKey)
+	}
+
+	var out strings.Builder
+	if err := tmpl.Execute(&out, summary); err != nil {
+		fmt.Printf("⚠️  Warning: Invalid output_template: %v\n", err)
+		return
+	}
+	fmt.Println(strings.TrimRight(out.String(), "\n"))
+}
+
+// streamCommitMessage generates a commit message, printing it as the model
+// writes it when streaming is on and stdout is a terminal. The message is
+// shown again for review once complete, after style rules are applied.
+func streamCommitMessage(ctx context.Context, client *ai.Client, req ai.CommitRequest) (string, error) {
+	if !streamOutput || !isTerminalOutput() {
+		return client.GenerateCommitMessage(ctx, req)
+	}
+
+	streamed := false
+	message, err := client.StreamCommitMessage(ctx, req, func(text string) {
+		if !streamed {
+			fmt.Print("   ")
+			streamed = true
+		}
+		fmt.Print(strings.ReplaceAll(text, "\n", "\n   "))
+	})
+	if streamed {
+		fmt.Println()
+	}
+	return message, err
+}
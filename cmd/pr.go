@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/namin2/gh-assistant/internal/git"
+	"github.com/namin2/gh-assistant/internal/github"
+	"github.com/spf13/viper"
+)
+
+// githubToken returns github_token (or GITHUB_TOKEN), falling back to the
+// GH_TOKEN used by the gh CLI
+func githubToken() string {
+	if token := viper.GetString("github_token"); token != "" {
+		return token
+	}
+	return os.Getenv("GH_TOKEN")
+}
+
+// createPullRequest opens a pull request from the current branch into the base
+// branch, titled with the commit subject and described with an AI summary of
+// the branch. Failures are reported as warnings since the push already succeeded.
+func createPullRequest(g *git.Git, message string, draft bool) {
+	fmt.Println()
+	if draft {
+		fmt.Println("📬 Opening draft pull request...")
+	} else {
+		fmt.Println("📬 Opening pull request...")
+	}
+
+	pr, err := openPullRequest(g, message, draft)
+	if err != nil {
+		fmt.Printf("⚠️  Warning: Failed to open pull request: %v\n", err)
+		return
+	}
+	fmt.Printf("✅ Pull request #%d opened\n", pr.Number)
+	fmt.Printf("🔗 %s\n", pr.HTMLURL)
+}
+
+func openPullRequest(g *git.Git, message string, draft bool) (*github.PullRequest, error) {
+	token := githubToken()
+	if token == "" {
+		return nil, fmt.Errorf("no GitHub token; set github_token in the config file or GITHUB_TOKEN")
+	}
+
+	repo, pr, err := newPullRequest(g, message, draft)
+	if err != nil {
+		return nil, err
+	}
+	pr.Body = summarizeBranch(g, "pull request", issueRequirements(g))
+	if prChecklist {
+		if checklist := reviewChecklist(g); checklist != "" {
+			pr.Body = strings.TrimSpace(pr.Body + "\n\n" + checklist)
+		}
+	}
+
+	client := github.New(github.Config{APIURL: repo.APIURL, Token: token})
+	return client.CreatePullRequest(repo, pr)
+}
+
+// reviewChecklist asks the AI what reviewers should verify in the branch's
+// changes, formatted as a Markdown task list, or returns "" if it can't
+func reviewChecklist(g *git.Git) string {
+	apiKey, err := requireAPIKey()
+	if err != nil {
+		return ""
+	}
+
+	base, err := resolveBaseBranch(g)
+	if err != nil {
+		fmt.Printf("⚠️  Warning: Skipping review checklist: %v\n", err)
+		return ""
+	}
+	diff, err := g.GetBranchDiff(base)
+	if err != nil || diff == "" {
+		return ""
+	}
+
+	fmt.Println("🤖 Writing a review checklist...")
+	aiClient := newAIClient(g, resolveProvider(), apiKey)
+	ctx, stop := aiContext()
+	items, err := aiClient.GenerateReviewChecklist(ctx, diff)
+	stop()
+	recordSpend(aiClient)
+	if err != nil {
+		fmt.Printf("⚠️  Warning: Could not generate review checklist: %v\n", err)
+		return ""
+	}
+
+	checklist := "Review checklist:\n"
+	for _, item := range items {
+		checklist += "\n- [ ] " + item
+	}
+	return checklist
+}
+
+// maxRequirementsLength bounds the Jira requirements sent with the pull
+// request summary prompt
+const maxRequirementsLength = 2000
+
+// issueRequirements fetches the description and acceptance criteria of the
+// linked issue (--issue, or the key in the branch name) with --pr-criteria,
+// condensed for the prompt. It returns "" when there are none.
+func issueRequirements(g *git.Git) string {
+	if !prCriteria {
+		return ""
+	}
+	jiraClient := newJiraClient()
+	if !jiraClient.IsConfigured() {
+		fmt.Println("⚠️  Warning: --pr-criteria needs Jira to be configured, skipping acceptance criteria")
+		return ""
+	}
+
+	issueKey := linkedIssue
+	if issueKey == "" {
+		branch, _ := g.GetCurrentBranch()
+		issueKey = jiraClient.BranchIssueKey(branch)
+	}
+	if issueKey == "" {
+		fmt.Println("⚠️  Warning: No Jira issue is linked to this branch, skipping acceptance criteria")
+		return ""
+	}
+
+	fmt.Printf("🎫 Fetching acceptance criteria from %s...\n", issueKey)
+	requirements, err := jiraClient.GetRequirements(issueKey)
+	if err != nil {
+		fmt.Printf("⚠️  Warning: Could not fetch Jira issue %s: %v\n", issueKey, err)
+		return ""
+	}
+	return condenseRequirements(requirements)
+}
+
+// condenseRequirements drops blank lines and indentation and cuts the text
+// to maxRequirementsLength
+func condenseRequirements(text string) string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	condensed := strings.Join(lines, "\n")
+	if runes := []rune(condensed); len(runes) > maxRequirementsLength {
+		condensed = string(runes[:maxRequirementsLength]) + "…"
+	}
+	return condensed
+}
+
+// newPullRequest works out the repository and the title, head and base of
+// the pull request for the current branch, without the body
+func newPullRequest(g *git.Git, message string, draft bool) (github.Repo, github.NewPullRequest, error) {
+	webURL, err := g.RemoteWebURL()
+	if err != nil {
+		return github.Repo{}, github.NewPullRequest{}, err
+	}
+	repo, err := github.ParseRepo(webURL)
+	if err != nil {
+		return github.Repo{}, github.NewPullRequest{}, err
+	}
+
+	head, err := g.GetCurrentBranch()
+	if err != nil {
+		return github.Repo{}, github.NewPullRequest{}, err
+	}
+	base, err := resolveBaseBranch(g)
+	if err != nil {
+		return github.Repo{}, github.NewPullRequest{}, fmt.Errorf("could not determine the base branch: %w", err)
+	}
+	if remote, err := g.GetRemote(); err == nil {
+		base = strings.TrimPrefix(base, remote+"/")
+	}
+	if base == head {
+		return github.Repo{}, github.NewPullRequest{}, fmt.Errorf("branch %s is the base branch", head)
+	}
+
+	title := subjectLine(message)
+	if title == "" {
+		last, err := g.GetLastCommitMessage()
+		if err != nil {
+			return github.Repo{}, github.NewPullRequest{}, err
+		}
+		title = subjectLine(last)
+	}
+
+	return repo, github.NewPullRequest{Title: title, Head: head, Base: base, Draft: draft}, nil
+}
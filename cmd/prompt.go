@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/term"
+)
+
+// stdin is shared by all prompts so buffered input isn't lost between them
+var stdin = bufio.NewReader(os.Stdin)
+
+// isInteractive reports whether stdin is a terminal
+func isInteractive() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// isTerminalOutput reports whether stdout is a terminal
+func isTerminalOutput() bool {
+	info, err := os.Stdout.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// assumeYes returns the command's -y flag when it's given, otherwise
+// auto_confirm (from GH_ASSISTANT_YES or the config file)
+func assumeYes(cmd *cobra.Command, flag bool) bool {
+	if cmd.Flags().Changed("yes") {
+		return flag
+	}
+	return viper.GetBool("auto_confirm")
+}
+
+// confirm asks a yes/no question. An empty answer selects defaultYes.
+func confirm(question string, defaultYes bool) bool {
+	hint := "[y/N]"
+	def := 'n'
+	if defaultYes {
+		hint = "[Y/n]"
+		def = 'y'
+	}
+	return promptChoice(fmt.Sprintf("%s %s: ", question, hint), "yn", def) == 'y'
+}
+
+// promptChoice asks a question answered by one of the single-letter choices.
+// On a terminal a single keypress is enough; otherwise a line is read and its
+// first letter used. Enter selects def. It returns 0 for an unrecognized answer.
+func promptChoice(question, choices string, def rune) rune {
+	fmt.Print(question)
+
+	if stdin.Buffered() == 0 && isInteractive() {
+		if key, ok := readKey(); ok {
+			fmt.Println(string(key))
+			switch {
+			case key == '\r' || key == '\n':
+				return def
+			case strings.ContainsRune(choices, unicode.ToLower(key)):
+				return unicode.ToLower(key)
+			default:
+				return 0
+			}
+		}
+	}
+
+	input, _ := stdin.ReadString('\n')
+	input = strings.TrimSpace(strings.ToLower(input))
+	if input == "" {
+		return def
+	}
+
+	first := []rune(input)[0]
+	if !strings.ContainsRune(choices, first) {
+		return 0
+	}
+	return first
+}
+
+// ctrlC is the byte Ctrl-C sends when the terminal is in raw mode
+const ctrlC = 3
+
+// readKey reads a single keypress by putting the terminal in raw mode for
+// the read. It returns false if stdin isn't a terminal or its mode can't be
+// changed, so the caller falls back to line input.
+func readKey() (rune, bool) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return 0, false
+	}
+	saved, err := term.MakeRaw(fd)
+	if err != nil {
+		return 0, false
+	}
+
+	buf := make([]byte, 1)
+	_, err = os.Stdin.Read(buf)
+	term.Restore(fd, saved)
+	if err != nil {
+		return 0, false
+	}
+	// Raw mode delivers Ctrl-C as a key instead of a signal; exit the way
+	// the interrupt would have
+	if buf[0] == ctrlC {
+		fmt.Println("^C")
+		os.Exit(130)
+	}
+	return rune(buf[0]), true
+}
+
+// selectItems shows a numbered checklist with every item selected and lets the
+// user toggle items by number (e.g. "2 4-6") until Enter is pressed on an
+// empty line. "a" selects all items and "n" none. It returns the selected items.
+func selectItems(title string, items []string) []string {
+	selected := make([]bool, len(items))
+	for i := range selected {
+		selected[i] = true
+	}
+
+	for {
+		fmt.Println()
+		fmt.Println(title)
+		for i, item := range items {
+			mark := " "
+			if selected[i] {
+				mark = "x"
+			}
+			fmt.Printf("   [%s] %d. %s\n", mark, i+1, item)
+		}
+		fmt.Print("Toggle by number (e.g. 2 4-6), a=all, n=none, Enter to continue: ")
+
+		input, err := stdin.ReadString('\n')
+		input = strings.TrimSpace(strings.ToLower(input))
+		if input == "" {
+			break
+		}
+
+		switch input {
+		case "a", "n":
+			for i := range selected {
+				selected[i] = input == "a"
+			}
+		default:
+			for _, field := range strings.Fields(strings.ReplaceAll(input, ",", " ")) {
+				from, to, ok := parseRange(field, len(items))
+				if !ok {
+					fmt.Printf("⚠️  Ignoring %q\n", field)
+					continue
+				}
+				for i := from; i <= to; i++ {
+					selected[i-1] = !selected[i-1]
+				}
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+
+	var chosen []string
+	for i, item := range items {
+		if selected[i] {
+			chosen = append(chosen, item)
+		}
+	}
+	return chosen
+}
+
+// parseRange parses "3" or "2-5" into an inclusive range within 1..max
+func parseRange(field string, max int) (from, to int, ok bool) {
+	lo, hi, isRange := strings.Cut(field, "-")
+	from, err := strconv.Atoi(lo)
+	if err != nil {
+		return 0, 0, false
+	}
+	to = from
+	if isRange {
+		if to, err = strconv.Atoi(hi); err != nil {
+			return 0, 0, false
+		}
+	}
+	if from < 1 || to > max || from > to {
+		return 0, 0, false
+	}
+	return from, to, true
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"testing"
+)
+
+// useStdin makes prompts read input, with os.Stdin set to file, for the
+// test's duration
+func useStdin(t *testing.T, file *os.File, input string) {
+	t.Helper()
+	savedFile, savedReader := os.Stdin, stdin
+	os.Stdin, stdin = file, bufio.NewReader(strings.NewReader(input))
+	t.Cleanup(func() { os.Stdin, stdin = savedFile, savedReader })
+}
+
+func TestReadKeyNeedsTerminal(t *testing.T) {
+	// /dev/null is a character device but not a terminal
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Skip(err)
+	}
+	defer devNull.Close()
+	useStdin(t, devNull, "")
+
+	if key, ok := readKey(); ok {
+		t.Errorf("readKey() = %q, true without a terminal", key)
+	}
+}
+
+func TestPromptChoiceFallsBackToLineInput(t *testing.T) {
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Skip(err)
+	}
+	defer devNull.Close()
+
+	tests := []struct {
+		input string
+		want  rune
+	}{
+		{"y\n", 'y'},
+		{"No\n", 'n'},
+		{"\n", 'n'},
+		{"", 'n'},
+		{"x\n", 0},
+	}
+	for _, tt := range tests {
+		useStdin(t, devNull, tt.input)
+		if got := promptChoice("Continue? [y/N] ", "yn", 'n'); got != tt.want {
+			t.Errorf("promptChoice() with input %q = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
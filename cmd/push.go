@@ -1,14 +1,22 @@
 package cmd
 
 import (
-	"bufio"
+	"context"
 	"fmt"
+	"io"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/namin2/gh-assistant/internal/ai"
+	"github.com/namin2/gh-assistant/internal/anonymize"
+	"github.com/namin2/gh-assistant/internal/commitmsg"
 	"github.com/namin2/gh-assistant/internal/git"
 	"github.com/namin2/gh-assistant/internal/jira"
+	"github.com/namin2/gh-assistant/internal/patch"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -16,8 +24,51 @@ import (
 var (
 	autoConfirm bool
 	stageAll    bool
+	suggestions int
+	candidates  int
+	forceTime   bool
+	linkedIssue string
+
+	amendMessageOnly bool
+	newMessage       string
+
+	openPR      bool
+	draftPR     bool
+	prCriteria  bool
+	prChecklist bool
+
+	breaking      bool
+	anonymizeDiff bool
+	strict        bool
+	pushTags      bool
+	selectFiles   bool
+
+	includeGenerated bool
+	quiet            bool
+	recursiveDir     string
+	concurrency      int
+	jsonOutput       bool
+	fixupCommit      string
+	squashCommit     string
+	dateFlag         string
+	dryRun           bool
+	ignoreWhitespace bool
+	pushProvider     string
+	stagePaths       []string
+
+	separateGenerated bool
+	subjectOverride   string
+	followup          bool
+	streamOutput      bool
+	commitBody        bool
 )
 
+// commitDate is the parsed --date, or zero to commit with the current time
+var commitDate time.Time
+
+// batchJSON receives the --json summary of push --recursive, or is nil
+var batchJSON io.Writer
+
 var pushCmd = &cobra.Command{
 	Use:   "push",
 	Short: "Generate AI commit message and push",
@@ -27,7 +78,23 @@ and pushes to the remote repository.
 Examples:
   gh-assistant push           # Commit staged changes with AI message and push
   gh-assistant push -a        # Stage all changes, commit with AI message and push
-  gh-assistant push -y        # Skip confirmation prompt`,
+  gh-assistant push --stage a.go b.go  # Stage just these files, then commit and push
+  gh-assistant push -y        # Skip confirmation prompt
+  gh-assistant push --suggestions 3  # Pick from 3 ranked suggestions
+  gh-assistant push --candidates 3   # Pick from 3 distinct full messages
+  gh-assistant push --subject "fix: retry flaky uploads"  # Write the subject, AI writes the body
+  gh-assistant push --issue PROJ-123 # Base the message on a Jira issue
+  gh-assistant push --amend-message-only  # Reword the last unpushed commit
+  gh-assistant push --draft-pr       # Open a draft pull request after pushing
+  gh-assistant push --breaking       # Add a BREAKING CHANGE footer with migration notes
+  gh-assistant push --tags           # Push new tags after the branch
+  gh-assistant push --select         # Pick which staged files go into the commit
+  gh-assistant push --fixup HEAD~2   # Commit as fixup! of a commit for rebase --autosquash
+  gh-assistant push --followup       # Describe the change as a follow-up to the last commit
+  gh-assistant push --date "2 days ago"  # Backdate the commit's author date
+  gh-assistant push --dry-run --pr   # Preview the commit, push, PR and Jira ticket
+  gh-assistant push -q               # Only print a tab-separated usage line
+  gh-assistant push --recursive ~/src # Push every repository under ~/src with staged changes`,
 	RunE: runPush,
 }
 
@@ -35,34 +102,158 @@ func init() {
 	rootCmd.AddCommand(pushCmd)
 	pushCmd.Flags().BoolVarP(&autoConfirm, "yes", "y", false, "Auto-confirm the generated commit message")
 	pushCmd.Flags().BoolVarP(&stageAll, "all", "a", false, "Stage all changes before committing")
+	pushCmd.Flags().StringArrayVar(&stagePaths, "stage", nil, "Stage these paths before committing; more can follow as arguments")
+	pushCmd.Flags().IntVar(&suggestions, "suggestions", 0, "Ask the model for N ranked suggestions to choose from")
+	pushCmd.Flags().IntVar(&candidates, "candidates", 0, "Ask the model for N distinct commit messages, with bodies, to choose from")
+	pushCmd.Flags().BoolVar(&followup, "followup", false, "Tell the AI this change follows up on the previous commit, giving it that commit's subject")
+	pushCmd.Flags().StringVar(&subjectOverride, "subject", "", "Use this subject line as is and have the AI write only the body")
+	pushCmd.Flags().BoolVar(&forceTime, "force-time", false, "Push even when a CI or working-hours guard applies")
+	pushCmd.Flags().StringVar(&linkedIssue, "issue", "", "Jira issue key whose summary guides the message (added as a Refs: footer)")
+	pushCmd.Flags().BoolVar(&amendMessageOnly, "amend-message-only", false, "Reword the last unpushed commit without changing its contents, then exit")
+	pushCmd.Flags().StringVarP(&newMessage, "message", "m", "", "Message to use with --amend-message-only instead of generating one")
+	pushCmd.Flags().BoolVar(&openPR, "pr", false, "Open a GitHub pull request after pushing")
+	pushCmd.Flags().BoolVar(&draftPR, "draft-pr", false, "Open a GitHub draft pull request after pushing")
+	pushCmd.Flags().BoolVar(&prChecklist, "pr-checklist", false, "Append an AI-written checklist of what reviewers should verify to the pull request body")
+	pushCmd.Flags().BoolVar(&prCriteria, "pr-criteria", false, "Fetch the Jira issue's description and acceptance criteria so the pull request body explains how the change meets them")
+	pushCmd.Flags().BoolVar(&breaking, "breaking", false, "Mark the commit as a breaking change with a BREAKING CHANGE footer")
+	pushCmd.Flags().BoolVar(&anonymizeDiff, "anonymize", false, "Replace identifiers with placeholders before sending the diff to the AI")
+	pushCmd.Flags().BoolVar(&selectFiles, "select", false, "Choose which staged files to commit; the rest are unstaged")
+	pushCmd.Flags().BoolVar(&includeGenerated, "include-generated", false, "Send changes under excluded paths (vendor/, node_modules/, ...) to the AI")
+	pushCmd.Flags().BoolVar(&pushTags, "tags", false, "Also push local tags that aren't on the remote yet (asks first)")
+	pushCmd.Flags().BoolVar(&strict, "strict", false, "Refuse to commit a message that breaks the configured rules (see validate-message)")
+	pushCmd.Flags().BoolVarP(&quiet, "quiet", "q", false, "Auto-confirm and print only a tab-separated token and cost line")
+	pushCmd.Flags().StringVar(&recursiveDir, "recursive", "", "Run the push flow in every git repository under this directory that has changes")
+	pushCmd.Flags().BoolVar(&jsonOutput, "json", false, "With --recursive, auto-confirm and print only a JSON summary of each repository's outcome")
+	pushCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Generate the message and show every commit, push, tag, pull request and Jira step without doing any of them")
+	pushCmd.Flags().StringVar(&pushProvider, "provider", "", "AI provider for this run, using its own API key (openai, anthropic, ollama, gemini, azure, bedrock, mistral, openrouter, mock)")
+	pushCmd.Flags().BoolVar(&ignoreWhitespace, "ignore-whitespace-hunks", false, "Leave whitespace-only changes out of the diff sent to the AI (the commit still includes them)")
+	pushCmd.Flags().BoolVar(&separateGenerated, "split-generated", false, "Commit staged files matching generated_paths separately as \"chore: regenerate ...\", after the hand-written changes")
+	pushCmd.Flags().StringVar(&dateFlag, "date", "", "Author date of the commit: RFC3339, YYYY-MM-DD [HH:MM[:SS]], \"yesterday\" or \"<n> <unit>s ago\"")
+	pushCmd.Flags().StringVar(&fixupCommit, "fixup", "", "Commit the staged changes as a fixup! commit of this commit, without generating a message")
+	pushCmd.Flags().StringVar(&squashCommit, "squash", "", "Commit the staged changes as a squash! commit of this commit, without generating a message")
+	pushCmd.Flags().BoolVar(&commitBody, "body", false, "Add a body listing the main changes as bullets under the subject, wrapped at max_body_line_length (default 72)")
+	pushCmd.Flags().BoolVar(&streamOutput, "stream", true, "Print the commit message as the model writes it (OpenAI-compatible and Anthropic providers, in a terminal)")
+	pushCmd.Flags().IntVar(&concurrency, "concurrency", 0, "With --recursive and -y, push this many repositories at once, bounding concurrent AI requests (default 1)")
 }
 
 func runPush(cmd *cobra.Command, args []string) error {
-	// Check configuration
-	apiKey := viper.GetString("api_key")
-	if apiKey == "" {
-		apiKey = os.Getenv("OPENAI_API_KEY")
-		if apiKey == "" {
-			apiKey = os.Getenv("ANTHROPIC_API_KEY")
+	if _, err := git.CheckInstalled(); err != nil {
+		return err
+	}
+	autoConfirm = assumeYes(cmd, autoConfirm)
+	if pushProvider != "" {
+		if !validProvider(ai.Provider(pushProvider)) {
+			return fmt.Errorf("invalid provider: %s (use %s)", pushProvider, providerNames())
+		}
+		providerOverride = ai.Provider(pushProvider)
+	}
+	if !cmd.Flags().Changed("ignore-whitespace-hunks") {
+		ignoreWhitespace = viper.GetBool("ignore_whitespace_hunks")
+	}
+	if !cmd.Flags().Changed("pr-checklist") {
+		prChecklist = viper.GetBool("pr_review_checklist")
+	}
+	if !cmd.Flags().Changed("pr-criteria") {
+		prCriteria = viper.GetBool("pr_jira_criteria")
+	}
+	if !cmd.Flags().Changed("split-generated") {
+		separateGenerated = viper.GetBool("split_generated")
+	}
+	if cmd.Flags().Changed("body") {
+		bodyOverride = &commitBody
+	}
+	if !cmd.Flags().Changed("stream") {
+		streamOutput = !viper.IsSet("stream_output") || viper.GetBool("stream_output")
+	}
+
+	if quiet {
+		autoConfirm = true
+		out, restore, err := silenceStdout()
+		if err != nil {
+			return err
 		}
+		defer func() {
+			restore()
+			fmt.Fprintln(out, usageLine(resolveProvider()))
+		}()
 	}
 
-	if apiKey == "" {
-		return fmt.Errorf(`API key not configured. Set it up using one of:
-  1. Run: gh-assistant config --api-key YOUR_KEY
-  2. Set environment variable: export OPENAI_API_KEY=your_key
-  3. Set environment variable: export ANTHROPIC_API_KEY=your_key`)
+	if jsonOutput {
+		if recursiveDir == "" {
+			return fmt.Errorf("--json can only be used with --recursive")
+		}
+		autoConfirm = true
+		out, restore, err := silenceStdout()
+		if err != nil {
+			return err
+		}
+		defer restore()
+		batchJSON = out
 	}
 
-	// Determine provider
-	provider := ai.Provider(viper.GetString("provider"))
-	if provider == "" {
-		if os.Getenv("ANTHROPIC_API_KEY") != "" {
-			provider = ai.ProviderAnthropic
-		} else {
-			provider = ai.ProviderOpenAI
+	if newMessage != "" && !amendMessageOnly {
+		return fmt.Errorf("--message can only be used with --amend-message-only")
+	}
+	if amendMessageOnly {
+		return runAmendMessageOnly()
+	}
+	if dateFlag != "" {
+		date, err := parseCommitDate(dateFlag, time.Now())
+		if err != nil {
+			return fmt.Errorf("--date: %w", err)
+		}
+		commitDate = date
+	}
+	if len(stagePaths) > 0 {
+		stagePaths = append(stagePaths, args...)
+	} else if len(args) > 0 {
+		return fmt.Errorf("unexpected arguments %s; use --stage to stage files", strings.Join(args, " "))
+	}
+	if len(stagePaths) > 0 && (stageAll || recursiveDir != "" || dryRun) {
+		return fmt.Errorf("--stage can't be used with --all, --recursive or --dry-run")
+	}
+	if dryRun && selectFiles {
+		return fmt.Errorf("--select can't be used with --dry-run, since it unstages files")
+	}
+	if fixupCommit != "" && squashCommit != "" {
+		return fmt.Errorf("--fixup and --squash can't be used together")
+	}
+	if (fixupCommit != "" || squashCommit != "") && recursiveDir != "" {
+		return fmt.Errorf("--fixup and --squash can't be used with --recursive")
+	}
+	if followup && (fixupCommit != "" || squashCommit != "") {
+		return fmt.Errorf("--followup can't be used with --fixup or --squash, which already refer to a commit")
+	}
+	if cmd.Flags().Changed("subject") {
+		subjectOverride = strings.TrimSpace(subjectOverride)
+		if subjectOverride == "" || strings.Contains(subjectOverride, "\n") {
+			return fmt.Errorf("--subject must be a single non-empty line")
+		}
+		if suggestions > 1 || candidates > 1 || fixupCommit != "" || squashCommit != "" {
+			return fmt.Errorf("--subject can't be used with --suggestions, --candidates, --fixup or --squash")
 		}
 	}
+	if suggestions > 1 && candidates > 1 {
+		return fmt.Errorf("--suggestions and --candidates can't be used together")
+	}
+
+	// Check configuration; fixup!/squash! messages come from git, so they
+	// don't need an API key
+	var apiKey string
+	if fixupCommit == "" && squashCommit == "" {
+		var err error
+		apiKey, err = requireAPIKey()
+		if err != nil {
+			return err
+		}
+	}
+
+	// Determine provider
+	provider := resolveProvider()
+
+	if recursiveDir != "" {
+		return runPushRecursive(recursiveDir, apiKey, provider)
+	}
 
 	// Initialize git
 	g := git.New("")
@@ -71,21 +262,69 @@ func runPush(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not a git repository")
 	}
 
+	return pushRepo(g, apiKey, provider)
+}
+
+// pushRepo runs the generate, commit and push flow in one repository
+func pushRepo(g *git.Git, apiKey string, provider ai.Provider) error {
 	fmt.Println("🔍 Analyzing your changes...")
+	if g.IsLinkedWorktree() {
+		if commonDir, err := g.CommonDir(); err == nil {
+			fmt.Printf("🌳 In a linked worktree of %s\n", filepath.Dir(commonDir))
+		}
+	}
 
-	// Stage all if requested
-	if stageAll {
+	// Stage all if requested; a dry run leaves the index alone and previews
+	// the tracked changes instead
+	if stageAll && dryRun {
+		fmt.Println("🧪 Would stage all changes (previewing tracked files only)")
+	} else if stageAll {
 		fmt.Println("📦 Staging all changes...")
 		if err := g.StageAll(); err != nil {
 			return fmt.Errorf("failed to stage changes: %w", err)
 		}
 	}
 
+	if len(stagePaths) > 0 {
+		if err := stageFiles(g, stagePaths); err != nil {
+			return err
+		}
+	}
+
 	// Check for staged changes
 	hasStaged, err := g.HasStagedChanges()
 	if err != nil {
 		return fmt.Errorf("failed to check staged changes: %w", err)
 	}
+	if stageAll && dryRun && !hasStaged {
+		hasStaged, _ = g.HasUnstagedChanges()
+	}
+
+	// A conflicted merge, cherry-pick or revert is completed by the commit;
+	// with nothing staged there's nothing sensible to do here
+	operation := g.InProgressOperation()
+	if operation != nil {
+		if !hasStaged {
+			return fmt.Errorf("a %s of %.7s is in progress with nothing staged; finish it with 'git %s --continue' or 'git %s --abort'",
+				operation.Kind, operation.Commit, operation.Kind, operation.Kind)
+		}
+		fmt.Printf("⚠️  A %s of %.7s is in progress; this commit will complete it\n", operation.Kind, operation.Commit)
+	}
+
+	if !hasStaged && (fixupCommit != "" || squashCommit != "") {
+		return fmt.Errorf("nothing is staged for the fixup!/squash! commit")
+	}
+
+	if hasStaged && selectFiles {
+		selected, err := selectStagedFiles(g)
+		if err != nil {
+			return err
+		}
+		if !selected {
+			fmt.Println("❌ No files selected, aborted")
+			return nil
+		}
+	}
 
 	// Check for existing unpushed commits
 	unpushedMessages, _ := g.GetUnpushedCommitMessages()
@@ -102,83 +341,200 @@ func runPush(cmd *cobra.Command, args []string) error {
 		fmt.Println()
 	}
 
-	if hasStaged {
+	if hasStaged && (fixupCommit != "" || squashCommit != "") {
+		// CASE 0: fixup!/squash! commit - git derives the message from the
+		// target commit for rebase --autosquash
+		message, err = commitAutosquash(g)
+		if err != nil {
+			return err
+		}
+	} else if hasStaged {
 		// CASE 1: Staged changes - generate AI commit message
 		fmt.Println("📝 Found staged changes to commit")
 
-		diff, err := g.GetStagedDiff()
+		var split *generatedSplit
+		if separateGenerated {
+			split, err = splitGenerated(g)
+			if err != nil {
+				return err
+			}
+			defer split.restore(g)
+		}
+
+		diff, err := promptDiff(g)
 		if err != nil {
 			return fmt.Errorf("failed to get staged diff: %w", err)
 		}
 
 		changedFiles, _ := g.GetChangedFiles()
+		branch, _ := g.GetCurrentBranch()
+		req := ai.CommitRequest{Diff: diff, Files: changedFiles, Subject: subjectOverride, Branch: branch}
 
-		// Initialize AI client
-		aiClient := ai.New(ai.Config{
-			Provider: provider,
-			APIKey:   apiKey,
-			Model:    viper.GetString("model"),
-		})
+		// Describe submodule pointer updates instead of sending the raw
+		// "Subproject commit" lines, which the model tends to misread
+		if submodules, _ := g.GetSubmoduleChanges(); len(submodules) > 0 {
+			req.Diff = patch.Filter(req.Diff, func(f patch.File) bool { return !f.IsSubmodule() })
+			req.Notes = append(req.Notes, submodules...)
+		}
 
-		fmt.Println("🤖 Generating commit message...")
+		// Initialize AI client
+		aiClient := newAIClient(g, provider, apiKey)
 
 		// Generate commit message
-		message, err = aiClient.GenerateCommitMessage(diff, changedFiles)
-		if err != nil {
-			return fmt.Errorf("failed to generate commit message: %w", err)
+		// Use the linked Jira issue's summary as context
+		if linkedIssue != "" {
+			note, err := linkedIssueNote(linkedIssue)
+			if err != nil {
+				return err
+			}
+			req.Notes = append(req.Notes, note)
 		}
 
-		// Display the generated message
-		fmt.Println()
-		fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-		fmt.Println("📋 Generated commit message:")
-		fmt.Println()
-		fmt.Printf("   %s\n", message)
-		fmt.Println()
-		fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-		fmt.Println()
+		if operation != nil {
+			req.Notes = append(req.Notes, operationNote(operation))
+		}
+		if followup {
+			note, err := followupNote(g)
+			if err != nil {
+				return err
+			}
+			req.Notes = append(req.Notes, note)
+		}
+		modeChanges := describeModeChanges(&req)
+		excludeGenerated(&req)
+		checkBreaking(&req)
 
-		// Confirm with user
-		if !autoConfirm {
-			fmt.Print("Proceed with this message? [Y/n/e(dit)]: ")
-			reader := bufio.NewReader(os.Stdin)
-			input, _ := reader.ReadString('\n')
-			input = strings.TrimSpace(strings.ToLower(input))
-
-			switch input {
-			case "n", "no":
-				fmt.Println("❌ Aborted")
-				return nil
-			case "e", "edit":
-				fmt.Println("Enter your commit message (press Enter twice to finish):")
-				var lines []string
-				for {
-					line, _ := reader.ReadString('\n')
-					line = strings.TrimRight(line, "\n\r")
-					if line == "" && len(lines) > 0 {
-						break
-					}
-					if line != "" {
-						lines = append(lines, line)
-					}
+		// Anonymize last so every part of the request is covered; names are
+		// restored in the generated messages
+		restore := func(message string) string { return message }
+		if anonymizeDiff {
+			fmt.Println("🕶️  Anonymizing identifiers before sending...")
+			req, restore = anonymizeRequest(req)
+		}
+
+		if len(modelTiers()) > 0 {
+			model, reason := aiClient.ModelFor(req)
+			fmt.Printf("📦 Using model %s (%s)\n", model, reason)
+		}
+
+		generate := func() (string, error) {
+			fmt.Println("🤖 Generating commit message...")
+			ctx, stop := aiContext()
+			defer stop()
+			if suggestions > 1 {
+				ranked, err := aiClient.GenerateRankedSuggestions(ctx, req, suggestions)
+				recordSpend(aiClient)
+				if err != nil {
+					return "", err
+				}
+				for i := range ranked {
+					ranked[i].Message = restore(ranked[i].Message)
+				}
+				return pickSuggestion(ranked), nil
+			}
+			if candidates > 1 {
+				messages, err := aiClient.GenerateCandidates(ctx, req, candidates)
+				recordSpend(aiClient)
+				if err != nil {
+					return "", err
 				}
-				if len(lines) > 0 {
-					message = strings.Join(lines, "\n")
+				for i := range messages {
+					messages[i] = restore(messages[i])
 				}
-			case "", "y", "yes":
-				// Continue with the message
-			default:
-				fmt.Println("❌ Invalid input, aborted")
-				return nil
+				return pickCandidate(messages), nil
+			}
+			var message string
+			var err error
+			if anonymizeDiff {
+				// The streamed text would show the placeholders
+				message, err = aiClient.GenerateCommitMessage(ctx, req)
+			} else {
+				message, err = streamCommitMessage(ctx, aiClient, req)
+			}
+			message = restore(message)
+			recordSpend(aiClient)
+			if err == nil && strict && req.Subject == "" {
+				message, err = regenerateImperative(ctx, aiClient, req, message, restore)
+			}
+			if err == nil {
+				message, err = enforceForbidden(ctx, aiClient, req, message, restore)
+			}
+			if err == nil {
+				message, err = requireSections(ctx, aiClient, req, message, restore)
+			}
+			if err == nil {
+				message, err = rejectVerbatim(ctx, aiClient, req, diff, message, restore)
+			}
+			if err == nil && req.Breaking && commitmsg.BreakingFooter(message) == "" {
+				fmt.Println("⚠️  No BREAKING CHANGE footer was generated; add migration notes with e(dit)")
 			}
+			if err == nil && operation != nil && operation.Kind == "revert" {
+				message = markRevert(message, operation.Commit)
+			}
+			return message, err
+		}
+
+		if req.Diff == "" && len(modeChanges) > 0 && viper.GetString("mode_change_message") != "ai" {
+			fmt.Println("🔐 Only file permissions changed, no AI needed")
+			message = modeChangeMessage(modeChanges)
+		} else {
+			message, err = generate()
+			if err != nil {
+				message, err = offlineFallback(err, req, restore)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to generate commit message: %w", err)
+			}
+		}
+
+		// Confirm with user, regenerating as many times as requested
+		var ok bool
+		switchProvider := func() error {
+			next, apiKey := nextProvider(provider)
+			if next == "" {
+				return fmt.Errorf("no other provider has an API key (set one of %s)", apiKeyVariables())
+			}
+			provider = next
+			aiClient = newAlternateClient(g, provider, apiKey)
+			fmt.Printf("🔀 Switched to %s (%s)\n", provider, aiClient.Model())
+			return nil
+		}
+
+		message, ok, err = reviewMessage(message, generate, switchProvider)
+		if err != nil || !ok {
+			return err
+		}
+		message = addPlainSummary(g, aiClient, req.Diff, message, restore)
+
+		// Normalize any trailers typed into the message, then append configured ones
+		message, err = g.NormalizeTrailers(message)
+		if err != nil {
+			return fmt.Errorf("failed to normalize commit trailers: %w", err)
+		}
+		message, err = applyCommitTrailers(g, message, linkedIssue)
+		if err != nil {
+			return fmt.Errorf("failed to add commit trailers: %w", err)
+		}
+
+		if err := checkStrict(message); err != nil {
+			return err
 		}
 
 		// Create the commit
-		fmt.Println("💾 Creating commit...")
-		if err := g.Commit(message); err != nil {
-			return fmt.Errorf("failed to commit: %w", err)
+		if dryRun {
+			fmt.Println("🧪 Would commit:")
+			fmt.Println(message)
+			if !commitDate.IsZero() {
+				fmt.Printf("📅 Author date: %s\n", commitDate.Format(time.RFC1123Z))
+			}
+		} else if err := commitStaged(g, message); err != nil {
+			return err
+		}
+		if split != nil {
+			if err := split.commit(g); err != nil {
+				return err
+			}
 		}
-		fmt.Printf("✅ Committed: %s\n", message)
 
 	} else {
 		// CASE 2: No staged changes - just push existing commits
@@ -197,24 +553,15 @@ func runPush(cmd *cobra.Command, args []string) error {
 		fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 		fmt.Println()
 
-		if !autoConfirm {
-			fmt.Print("Push these commits? [Y/n]: ")
-			reader := bufio.NewReader(os.Stdin)
-			input, _ := reader.ReadString('\n')
-			input = strings.TrimSpace(strings.ToLower(input))
-
-			if input == "n" || input == "no" {
-				fmt.Println("❌ Aborted")
-				return nil
-			}
+		if !autoConfirm && !dryRun && !confirm("Push these commits?", true) {
+			fmt.Println("❌ Aborted")
+			return nil
 		}
 
-		// Use last commit message for Jira (if applicable)
-		if hasUnpushed {
-			parts := strings.SplitN(unpushedMessages[0], " - ", 2)
-			if len(parts) == 2 {
-				message = parts[1]
-			}
+		// Use an existing commit's subject for Jira (if applicable)
+		message, err = jiraSeedMessage(g)
+		if err != nil {
+			return err
 		}
 	}
 
@@ -222,45 +569,1006 @@ func runPush(cmd *cobra.Command, args []string) error {
 	isFirstPush, _ := g.IsFirstPushToBranch()
 	isMainBranch := g.IsMainBranch()
 
+	if dryRun {
+		previewPush(g, message, isFirstPush, isMainBranch)
+		return nil
+	}
+
+	if err := checkPushGuard(); err != nil {
+		return err
+	}
+
+	// Someone may already have pushed a branch with this name
+	var lease string
+	if isFirstPush {
+		lease, err = checkRemoteBranch(g)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Push
 	fmt.Println("🚀 Pushing to remote...")
-	err = g.Push()
+	if lease != "" {
+		err = g.PushForceWithLease(lease)
+	} else if err = pushBranch(g); err != nil {
+		// Try with set-upstream, unless there is one already: then the push
+		// failed for another reason and -u would push to the local name
+		if _, _, ok := g.Upstream(); !ok {
+			err = g.PushSetUpstream()
+		}
+	}
 	if err != nil {
-		// Try with set-upstream
-		err = g.PushSetUpstream()
+		return fmt.Errorf("failed to push: %w", err)
+	}
+
+	fmt.Println("✅ Successfully pushed!")
+
+	if pushTags {
+		if err := pushNewTags(g); err != nil {
+			return err
+		}
+	}
+
+	if openPR || draftPR {
+		createPullRequest(g, message, draftPR)
+	}
+
+	// Create Jira ticket on first push to a new branch (not main/master),
+	// unless the work is already linked to an existing issue
+	jiraKey := linkedIssue
+	if isFirstPush && !isMainBranch && linkedIssue == "" {
+		jiraKey = createBranchIssue(g, message)
+	}
+
+	printPushSummary(g, message, jiraKey)
+	return nil
+}
+
+// pushBranch pushes to the upstream's branch name, or with
+// push_to_upstream: false to the branch of the local name
+func pushBranch(g *git.Git) error {
+	if viper.IsSet("push_to_upstream") && !viper.GetBool("push_to_upstream") {
+		return g.PushSameName()
+	}
+	return g.Push()
+}
+
+// stageFiles stages the --stage paths after checking that each exists or
+// is tracked (so deletions can be staged too)
+func stageFiles(g *git.Git, paths []string) error {
+	for _, path := range paths {
+		if _, err := os.Stat(path); err != nil && !g.IsTracked(path) {
+			return fmt.Errorf("cannot stage %s: no such file or directory", path)
+		}
+	}
+
+	fmt.Printf("📦 Staging %s...\n", strings.Join(paths, ", "))
+	if err := g.Stage(paths...); err != nil {
+		return fmt.Errorf("failed to stage changes: %w", err)
+	}
+	return nil
+}
+
+// promptDiff returns the diff the message is generated from: the staged
+// changes, or all tracked changes for a dry run with --all. With
+// --ignore-whitespace-hunks, whitespace-only changes are left out unless
+// nothing else changed. The commit itself is unaffected.
+func promptDiff(g *git.Git) (string, error) {
+	full, spaceless := g.GetStagedDiff, g.GetStagedDiffIgnoringSpace
+	if stageAll && dryRun {
+		full, spaceless = g.GetAllDiff, g.GetAllDiffIgnoringSpace
+	}
+	if !ignoreWhitespace {
+		return full()
+	}
+
+	diff, err := spaceless()
+	if err != nil {
+		return "", err
+	}
+	if diff == "" {
+		fmt.Println("⚠️  Only whitespace changed, describing the full diff")
+		return full()
+	}
+	fmt.Println("🧹 Leaving whitespace-only changes out of the diff sent to the AI")
+	return diff, nil
+}
+
+// checkRemoteBranch warns before a first push when the remote already has a
+// branch with the same name, and in a terminal lets the user rename the local
+// branch, force push or push anyway. Without prompting it pushes anyway: git
+// rejects the push unless it fast-forwards the remote branch. It returns the
+// remote commit to force push over (with a lease), or "" for a normal push.
+func checkRemoteBranch(g *git.Git) (string, error) {
+	for {
+		branch, err := g.GetCurrentBranch()
+		if err != nil {
+			return "", err
+		}
+		remoteCommit, err := g.RemoteBranchCommit(branch)
 		if err != nil {
-			return fmt.Errorf("failed to push: %w", err)
+			fmt.Printf("⚠️  Warning: Could not check whether %s exists on the remote: %v\n", branch, err)
+			return "", nil
+		}
+		if remoteCommit == "" {
+			return "", nil
+		}
+
+		fmt.Println()
+		fmt.Printf("⚠️  Branch %s already exists on the remote (at %.7s), but isn't tracked locally\n", branch, remoteCommit)
+		if autoConfirm || !isInteractive() {
+			fmt.Println("   Pushing anyway; git rejects the push unless it fast-forwards the remote branch")
+			return "", nil
+		}
+
+		switch promptChoice("[r]ename local branch, [f]orce push over it, [p]ush anyway, [a]bort? [r/f/p/A]: ", "rfpa", 'a') {
+		case 'r':
+			fmt.Print("New branch name: ")
+			input, _ := stdin.ReadString('\n')
+			name := strings.TrimSpace(input)
+			if name == "" {
+				continue
+			}
+			if err := g.RenameBranch(name); err != nil {
+				fmt.Printf("⚠️  %v\n", err)
+				continue
+			}
+			fmt.Printf("🌿 Renamed branch to %s\n", name)
+		case 'f':
+			return remoteCommit, nil
+		case 'p':
+			return "", nil
+		default:
+			return "", fmt.Errorf("push aborted: remote branch %s already exists", branch)
 		}
 	}
+}
 
-	fmt.Println("✅ Successfully pushed!")
+// commitStaged commits the staged changes, with the --date author date if given
+func commitStaged(g *git.Git, message string) error {
+	fmt.Println("💾 Creating commit...")
+	var err error
+	if commitDate.IsZero() {
+		err = g.Commit(message)
+	} else {
+		fmt.Printf("📅 Author date: %s\n", commitDate.Format(time.RFC1123Z))
+		err = g.CommitAt(message, commitDate)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+	fmt.Printf("✅ Committed: %s\n", message)
+	return nil
+}
 
-	// Create Jira ticket on first push to a new branch (not main/master)
-	if isFirstPush && !isMainBranch {
-		jiraClient := jira.New(jira.Config{
-			BaseURL:  viper.GetString("jira_url"),
-			Email:    viper.GetString("jira_email"),
-			APIToken: viper.GetString("jira_token"),
-			Project:  viper.GetString("jira_project"),
-		})
+// commitAutosquash creates the --fixup or --squash commit and returns its message
+func commitAutosquash(g *git.Git) (string, error) {
+	if dryRun {
+		return previewAutosquash(g)
+	}
+	if fixupCommit != "" {
+		fmt.Printf("💾 Creating fixup! commit for %s...\n", fixupCommit)
+		if err := g.CommitFixup(fixupCommit); err != nil {
+			return "", fmt.Errorf("failed to commit: %w", err)
+		}
+	} else {
+		fmt.Printf("💾 Creating squash! commit for %s...\n", squashCommit)
+		if err := g.CommitSquash(squashCommit); err != nil {
+			return "", fmt.Errorf("failed to commit: %w", err)
+		}
+	}
 
-		if jiraClient.IsConfigured() {
-			fmt.Println()
-			fmt.Println("🎫 Creating Jira ticket...")
+	message, err := g.GetLastCommitMessage()
+	if err != nil {
+		return "", fmt.Errorf("failed to get commit message: %w", err)
+	}
+	fmt.Printf("✅ Committed: %s\n", message)
+	fmt.Println("💡 Fold it in with: git rebase -i --autosquash")
+	return message, nil
+}
 
-			title, err := jiraClient.CreateIssueWithTitle(message)
+// createBranchIssue creates the Jira ticket for a newly pushed branch, or
+// finds the one an earlier push created, and returns its key ("" if none)
+func createBranchIssue(g *git.Git, message string) string {
+	jiraClient := newJiraClient()
+	if !jiraClient.IsConfigured() || !confirmBranchUpToDate(g) {
+		return ""
+	}
+
+	fmt.Println()
+	branch, _ := g.GetCurrentBranch()
+	label := branchLabel(branch)
+	if existing := existingBranchIssue(jiraClient, label, branch); existing != nil {
+		fmt.Printf("🎫 Jira ticket already exists for this branch: %s - %s\n", existing.Key, existing.Fields.Summary)
+		fmt.Printf("🔗 %s\n", jiraClient.GetIssueURL(existing.Key))
+		return existing.Key
+	}
+
+	fmt.Println("🎫 Creating Jira ticket...")
+
+	opts := jira.CreateOptions{Description: jiraDescription(g), Branch: branch}
+	if label != "" {
+		opts.Labels = []string{label}
+	}
+	title, err := jiraClient.CreateIssueWithTitle(subjectLine(message), opts)
+	if err != nil {
+		fmt.Printf("⚠️  Warning: Failed to create Jira ticket: %v\n", err)
+		return ""
+	}
+
+	// Extract issue key from title (format: "KEY-123 - message")
+	issueKey := jira.ExtractIssueKey(title, jiraClient.ProjectForBranch(branch))
+	fmt.Printf("✅ Jira ticket created: %s\n", title)
+	fmt.Printf("🔗 %s\n", jiraClient.GetIssueURL(issueKey))
+	return issueKey
+}
+
+// branchLabel returns the label marking the branch's Jira ticket, or "" if
+// jira_branch_label is disabled
+func branchLabel(branch string) string {
+	if branch == "" || (viper.IsSet("jira_branch_label") && !viper.GetBool("jira_branch_label")) {
+		return ""
+	}
+	return jira.BranchLabel(branch)
+}
+
+// existingBranchIssue looks for a ticket already created for this branch, e.g.
+// by an earlier push whose create request timed out after succeeding
+func existingBranchIssue(jiraClient *jira.Client, label, branch string) *jira.Issue {
+	if label == "" {
+		return nil
+	}
+	issue, err := jiraClient.FindIssueByLabel(label, jiraClient.ProjectForBranch(branch))
+	if err != nil {
+		fmt.Printf("⚠️  Warning: Could not check for an existing Jira ticket: %v\n", err)
+		return nil
+	}
+	return issue
+}
+
+// excludePatterns returns exclude_paths, or patch.DefaultExcludes if it isn't set
+func excludePatterns() []string {
+	if viper.IsSet("exclude_paths") {
+		return viper.GetStringSlice("exclude_paths")
+	}
+	return patch.DefaultExcludes
+}
+
+// excludeGenerated leaves files under excluded paths out of the request unless
+// --include-generated is given, noting that they changed
+func excludeGenerated(req *ai.CommitRequest) {
+	if includeGenerated {
+		return
+	}
+	patterns := excludePatterns()
+
+	var excluded []string
+	req.Diff = patch.Filter(req.Diff, func(f patch.File) bool {
+		if patch.IsExcluded(f.Path, patterns) {
+			excluded = append(excluded, f.Path)
+			return false
+		}
+		return true
+	})
+	if len(excluded) == 0 {
+		return
+	}
+
+	var files []string
+	for _, file := range req.Files {
+		if !patch.IsExcluded(file, patterns) {
+			files = append(files, file)
+		}
+	}
+	req.Files = files
+
+	fmt.Printf("🙈 Leaving %d generated or vendored file(s) out of the AI context (--include-generated to send them)\n", len(excluded))
+	req.Notes = append(req.Notes, fmt.Sprintf("%d generated or vendored file(s) also changed and are not shown, e.g. %s",
+		len(excluded), excluded[0]))
+}
+
+// checkBreaking marks the request as a breaking change when --breaking is given
+// or the diff looks like it breaks the public API
+func checkBreaking(req *ai.CommitRequest) {
+	req.BreakingChanges = patch.DetectBreakingChanges(req.Diff)
+	if len(req.BreakingChanges) > 0 {
+		fmt.Println("💥 Possible breaking changes detected:")
+		for _, change := range req.BreakingChanges {
+			fmt.Printf("   • %s\n", change)
+		}
+	}
+	req.Breaking = breaking || len(req.BreakingChanges) > 0
+}
+
+// anonymizeRequest replaces identifiers throughout the request with consistent
+// placeholders, returning the anonymized request and a function that restores
+// the original names in generated text
+func anonymizeRequest(req ai.CommitRequest) (ai.CommitRequest, func(string) string) {
+	a := anonymize.New()
+	req.Diff = a.Anonymize(req.Diff)
+	req.Files = a.AnonymizeAll(req.Files)
+	req.Notes = a.AnonymizeAll(req.Notes)
+	req.BreakingChanges = a.AnonymizeAll(req.BreakingChanges)
+	req.Subject = a.Anonymize(req.Subject)
+	return req, a.Restore
+}
+
+// nextProvider returns the provider after current in ai.Providers that has an
+// API key, along with the key, or "" if there is none
+func nextProvider(current ai.Provider) (ai.Provider, string) {
+	start := 0
+	for i, p := range ai.Providers {
+		if p == current {
+			start = i
+		}
+	}
+	for i := 1; i < len(ai.Providers); i++ {
+		p := ai.Providers[(start+i)%len(ai.Providers)]
+		if apiKey := providerAPIKey(p); apiKey != "" {
+			return p, apiKey
+		}
+	}
+	return "", ""
+}
+
+// checkStrict validates the final message against the configured rules when
+// --strict is given
+func checkStrict(message string) error {
+	if !strict {
+		return nil
+	}
+	if problems := commitmsg.Validate(message, validationRules()); len(problems) > 0 {
+		printValidationProblems(problems)
+		return fmt.Errorf("commit message rejected by --strict")
+	}
+	return nil
+}
+
+// describeModeChanges replaces permission-only file diffs with notes, since
+// the model tends to invent content changes for bare "old mode"/"new mode"
+// lines, and returns the changes
+func describeModeChanges(req *ai.CommitRequest) []patch.ModeChange {
+	var changes []patch.ModeChange
+	req.Diff = patch.Filter(req.Diff, func(f patch.File) bool {
+		change, ok := f.ModeOnly()
+		if ok {
+			changes = append(changes, change)
+		}
+		return !ok
+	})
+	for _, c := range changes {
+		req.Notes = append(req.Notes, fmt.Sprintf("%s: only the file mode changed, from %s to %s; the content is unchanged",
+			c.Path, c.OldMode, c.NewMode))
+	}
+	return changes
+}
+
+// modeChangeMessage writes the message for a commit that only changes file
+// permissions, e.g. "chore: make script.sh executable"
+func modeChangeMessage(changes []patch.ModeChange) string {
+	executable := 0
+	for _, c := range changes {
+		if c.Executable() {
+			executable++
+		}
+	}
+
+	what := changes[0].Path
+	if len(changes) > 1 {
+		what = fmt.Sprintf("%d files", len(changes))
+	}
+	var subject string
+	switch executable {
+	case len(changes):
+		subject = fmt.Sprintf("chore: make %s executable", what)
+	case 0:
+		subject = fmt.Sprintf("chore: make %s non-executable", what)
+	default:
+		subject = fmt.Sprintf("chore: change file permissions of %s", what)
+	}
+	if len(changes) == 1 {
+		return subject
+	}
+
+	var body []string
+	for _, c := range changes {
+		body = append(body, fmt.Sprintf("- %s (%s → %s)", c.Path, c.OldMode, c.NewMode))
+	}
+	return commitmsg.Join(subject, strings.Join(body, "\n"))
+}
+
+// offlineFallback handles a failed generation: when the provider couldn't be
+// reached at all, offline_fallback chooses between typing the message
+// (manual), a message guessed from the diff (heuristic) or failing (abort,
+// the default). Other errors are returned unchanged.
+func offlineFallback(genErr error, req ai.CommitRequest, restore func(string) string) (string, error) {
+	if !ai.IsUnreachable(genErr) {
+		return "", genErr
+	}
+
+	switch viper.GetString("offline_fallback") {
+	case "manual":
+		fmt.Printf("📡 The AI service is unreachable: %v\n", genErr)
+		if message := editMessage(""); message != "" {
+			return message, nil
+		}
+		return "", fmt.Errorf("no commit message entered")
+	case "heuristic":
+		fmt.Printf("📡 The AI service is unreachable, using a message based on the changed files\n")
+		message := ai.HeuristicMessage(req)
+		if req.Subject != "" {
+			_, body := commitmsg.Split(message)
+			message = commitmsg.Join(req.Subject, body)
+		}
+		return restore(message), nil
+	default:
+		return "", genErr
+	}
+}
+
+// followupNote describes the previous commit for --followup, so the message
+// can present this change as a follow-up to it
+func followupNote(g *git.Git) (string, error) {
+	hash, err := g.HeadCommit()
+	if err != nil {
+		return "", fmt.Errorf("--followup needs a previous commit: %w", err)
+	}
+	message, err := g.GetLastCommitMessage()
+	if err != nil {
+		return "", fmt.Errorf("failed to read the previous commit: %w", err)
+	}
+	subject := subjectLine(message)
+	fmt.Printf("🔗 Following up on %.7s %s\n", hash, subject)
+	return fmt.Sprintf("This change follows up on the previous commit %.7s %q (e.g. fixes or completes it). "+
+		"Say so in the message, e.g. \"follow-up to %s\", while still describing what this change does.", hash, subject, subject), nil
+}
+
+// operationNote describes an in-progress merge, cherry-pick or revert for the
+// prompt, so the message explains the operation rather than only the diff
+func operationNote(op *git.Operation) string {
+	switch op.Kind {
+	case "cherry-pick":
+		return fmt.Sprintf("This is a cherry-pick of %.7s (%q); keep its intent and type", op.Commit, op.Subject)
+	case "revert":
+		return fmt.Sprintf("This is a revert of %.7s (%q); use the revert type and say what is being undone", op.Commit, op.Subject)
+	default:
+		return fmt.Sprintf("This commit concludes a merge of %.7s (%q), including any conflict resolution", op.Commit, op.Subject)
+	}
+}
+
+// markRevert starts the body with git's "This reverts commit <sha>." line
+// unless the message already mentions the commit
+func markRevert(message, sha string) string {
+	if strings.Contains(message, sha) {
+		return message
+	}
+	subject, body := commitmsg.Split(message)
+	line := fmt.Sprintf("This reverts commit %s.", sha)
+	if body == "" {
+		return commitmsg.Join(subject, line)
+	}
+	return commitmsg.Join(subject, line+"\n\n"+body)
+}
+
+// regenerateImperative asks for a new message once when imperative_mood is
+// enabled and the description still doesn't start with an imperative verb
+func regenerateImperative(ctx context.Context, aiClient *ai.Client, req ai.CommitRequest, message string, restore func(string) string) (string, error) {
+	forms := verbForms()
+	if forms == nil {
+		return message, nil
+	}
+	word, _, found := commitmsg.NonImperativeVerb(message, forms)
+	if !found {
+		return message, nil
+	}
+
+	fmt.Printf("🔁 Description starts with %q, regenerating in the imperative mood...\n", word)
+	req.Notes = append(req.Notes[:len(req.Notes):len(req.Notes)],
+		fmt.Sprintf("Start the description with an imperative verb (\"add\", not \"added\" or \"adding\"); %q is not one", word))
+	message, err := aiClient.GenerateCommitMessage(ctx, req)
+	recordSpend(aiClient)
+	return restore(message), err
+}
+
+// enforceForbidden checks the message for forbidden_words. With --strict it
+// regenerates once (checkStrict rejects words that remain); otherwise the
+// words are masked with asterisks.
+func enforceForbidden(ctx context.Context, aiClient *ai.Client, req ai.CommitRequest, message string, restore func(string) string) (string, error) {
+	words := viper.GetStringSlice("forbidden_words")
+	found := commitmsg.FindForbidden(message, words)
+	if len(found) == 0 {
+		return message, nil
+	}
+
+	if !strict {
+		fmt.Printf("⚠️  Warning: masking forbidden words in the message: %s\n", strings.Join(found, ", "))
+		return commitmsg.MaskForbidden(message, words), nil
+	}
+
+	fmt.Printf("🔁 Message uses forbidden words (%s), regenerating...\n", strings.Join(found, ", "))
+	req.Notes = append(req.Notes[:len(req.Notes):len(req.Notes)],
+		fmt.Sprintf("The previous message used %s; do not use these words or names", strings.Join(found, ", ")))
+	message, err := aiClient.GenerateCommitMessage(ctx, req)
+	recordSpend(aiClient)
+	return restore(message), err
+}
+
+// requireSections regenerates the message once when it lacks one of the
+// required_sections. A message still missing some is kept with a warning;
+// --strict rejects it (see checkStrict).
+func requireSections(ctx context.Context, aiClient *ai.Client, req ai.CommitRequest, message string, restore func(string) string) (string, error) {
+	sections := viper.GetStringSlice("required_sections")
+	missing := commitmsg.MissingSections(message, sections)
+	if len(missing) == 0 {
+		return message, nil
+	}
+
+	fmt.Printf("🔁 Message is missing required sections (%s), regenerating...\n", strings.Join(missing, ", "))
+	req.Notes = append(req.Notes[:len(req.Notes):len(req.Notes)],
+		fmt.Sprintf("The previous message lacked the %s section(s); every required section must start a body line with its name and a colon", strings.Join(missing, ", ")))
+	message, err := aiClient.GenerateCommitMessage(ctx, req)
+	recordSpend(aiClient)
+	if err != nil {
+		return "", err
+	}
+	message = restore(message)
+	if missing := commitmsg.MissingSections(message, sections); len(missing) > 0 {
+		fmt.Printf("⚠️  Warning: The message is still missing required sections: %s; add them with e(dit)\n", strings.Join(missing, ", "))
+	}
+	return message, nil
+}
+
+// defaultVerbatimLength is the default verbatim_max_length
+const defaultVerbatimLength = 40
+
+// verbatimMaxLength reads verbatim_max_length, the length from which a diff
+// line copied into the message counts as a leak; 0 disables the check
+func verbatimMaxLength() int {
+	if viper.IsSet("verbatim_max_length") {
+		return viper.GetInt("verbatim_max_length")
+	}
+	return defaultVerbatimLength
+}
+
+// rejectVerbatim regenerates the message once if it copies a long line of
+// the diff, which may hold a secret, and fails if the new one does too. The
+// original diff is checked, so lines hidden by anonymization are caught.
+func rejectVerbatim(ctx context.Context, aiClient *ai.Client, req ai.CommitRequest, diff, message string, restore func(string) string) (string, error) {
+	maxLength := verbatimMaxLength()
+	line := patch.VerbatimLine(diff, message, maxLength)
+	if line == "" {
+		return message, nil
+	}
+
+	fmt.Println("🔁 Message copies a line of the diff verbatim, regenerating...")
+	req.Notes = append(req.Notes[:len(req.Notes):len(req.Notes)],
+		"Don't copy lines of the diff into the message; describe the change in your own words")
+	message, err := aiClient.GenerateCommitMessage(ctx, req)
+	recordSpend(aiClient)
+	if err != nil {
+		return "", err
+	}
+	message = restore(message)
+	if patch.VerbatimLine(diff, message, maxLength) != "" {
+		return "", fmt.Errorf("the generated message copies a line of %d+ characters from the diff verbatim (raise verbatim_max_length, or set it to 0 to allow this)", maxLength)
+	}
+	return message, nil
+}
+
+// pushNewTags lists the local tags missing from the remote and pushes them
+// after confirmation
+func pushNewTags(g *git.Git) error {
+	tags, err := g.UnpushedTags()
+	if err != nil {
+		return fmt.Errorf("failed to list tags: %w", err)
+	}
+	if len(tags) == 0 {
+		fmt.Println("🏷️  No new tags to push")
+		return nil
+	}
+
+	fmt.Println()
+	fmt.Printf("🏷️  %d new tag(s): %s\n", len(tags), strings.Join(tags, ", "))
+	if !autoConfirm && !confirm("Push these tags?", true) {
+		fmt.Println("⏭️  Skipped pushing tags")
+		return nil
+	}
+
+	if err := g.PushTags(); err != nil {
+		return fmt.Errorf("failed to push tags: %w", err)
+	}
+	fmt.Println("✅ Tags pushed!")
+	return nil
+}
+
+// selectStagedFiles lets the user pick the staged files to commit and unstages
+// the others. It returns false if nothing is left staged.
+func selectStagedFiles(g *git.Git) (bool, error) {
+	if !isInteractive() {
+		return false, fmt.Errorf("--select needs an interactive terminal")
+	}
+
+	staged, err := g.GetStagedFiles()
+	if err != nil {
+		return false, fmt.Errorf("failed to list staged files: %w", err)
+	}
+
+	chosen := selectItems("📂 Staged files to include in this commit:", staged)
+	var rest []string
+	for _, file := range staged {
+		if !containsString(chosen, file) {
+			rest = append(rest, file)
+		}
+	}
+
+	if len(rest) > 0 {
+		fmt.Printf("📤 Unstaging %d file(s), their changes stay in the working tree\n", len(rest))
+		if err := g.Unstage(rest...); err != nil {
+			return false, fmt.Errorf("failed to unstage files: %w", err)
+		}
+	}
+	return len(chosen) > 0, nil
+}
+
+// reviewMessage shows the message and asks whether to use it, letting the user
+// edit it or call generate for a new one. If switchProvider is non-nil, the
+// user can also switch to another provider before regenerating. It returns
+// false if the user aborts.
+func reviewMessage(message string, generate func() (string, error), switchProvider func() error) (string, bool, error) {
+	question, choices := "Proceed with this message? [Y/n/e(dit)/r(egenerate)]: ", "yner"
+	if switchProvider != nil {
+		question, choices = "Proceed with this message? [Y/n/e(dit)/r(egenerate)/p(rovider)]: ", "ynerp"
+	}
+
+	for {
+		showGeneratedMessage(message)
+		if autoConfirm {
+			return message, true, nil
+		}
+
+		switch promptChoice(question, choices, 'y') {
+		case 'p':
+			if err := switchProvider(); err != nil {
+				fmt.Printf("⚠️  %v\n", err)
+				continue
+			}
+			var err error
+			message, err = generate()
 			if err != nil {
-				fmt.Printf("⚠️  Warning: Failed to create Jira ticket: %v\n", err)
-			} else {
-				// Extract issue key from title (format: "KEY-123 - message")
-				parts := strings.SplitN(title, " - ", 2)
-				issueKey := parts[0]
-				fmt.Printf("✅ Jira ticket created: %s\n", title)
-				fmt.Printf("🔗 %s\n", jiraClient.GetIssueURL(issueKey))
+				return "", false, fmt.Errorf("failed to generate commit message: %w", err)
+			}
+		case 'n':
+			fmt.Println("❌ Aborted")
+			return "", false, nil
+		case 'e':
+			return editMessage(message), true, nil
+		case 'r':
+			var err error
+			message, err = generate()
+			if err != nil {
+				return "", false, fmt.Errorf("failed to generate commit message: %w", err)
 			}
+		case 'y':
+			return message, true, nil
+		default:
+			fmt.Println("❌ Invalid input, aborted")
+			return "", false, nil
 		}
 	}
+}
 
-	return nil
+// pickSuggestion lists ranked suggestions and lets the user choose one.
+// With auto-confirm or invalid input, the top-ranked suggestion is used.
+func pickSuggestion(ranked []ai.Suggestion) string {
+	if len(ranked) == 1 || autoConfirm {
+		return ranked[0].Message
+	}
+
+	fmt.Println()
+	fmt.Println("💡 Suggestions (ranked by the model):")
+	for i, s := range ranked {
+		fmt.Printf("   %d. [%3.0f%%] %s\n", i+1, s.Confidence*100, s.Message)
+	}
+	fmt.Println()
+	return ranked[chooseNumber(len(ranked), "suggestion")].Message
+}
+
+// pickCandidate lists complete candidate messages and lets the user choose
+// one. With auto-confirm or invalid input, the first candidate is used.
+func pickCandidate(candidates []string) string {
+	if len(candidates) == 1 || autoConfirm {
+		return candidates[0]
+	}
+
+	fmt.Println()
+	fmt.Println("💡 Candidates:")
+	for i, message := range candidates {
+		fmt.Println()
+		lines := strings.Split(message, "\n")
+		fmt.Printf("   %d. %s\n", i+1, lines[0])
+		for _, line := range lines[1:] {
+			fmt.Printf("      %s\n", line)
+		}
+	}
+	fmt.Println()
+	return candidates[chooseNumber(len(candidates), "candidate")]
+}
+
+// chooseNumber asks for a choice between 1 and n, returning its index. An
+// empty or invalid answer selects the first item.
+func chooseNumber(n int, item string) int {
+	fmt.Printf("Choose a message [1-%d] (Enter for 1): ", n)
+
+	input, _ := stdin.ReadString('\n')
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return 0
+	}
+
+	choice, err := strconv.Atoi(input)
+	if err != nil || choice < 1 || choice > n {
+		fmt.Printf("⚠️  Invalid choice, using %s 1\n", item)
+		return 0
+	}
+	return choice - 1
+}
+
+// subjectLine returns the first line of a commit message
+func subjectLine(message string) string {
+	return strings.TrimSpace(strings.SplitN(message, "\n", 2)[0])
+}
+
+// jiraSeedMessage returns the subject of the commit that seeds the Jira ticket
+// when nothing new is committed: the newest commit, or with
+// jira_seed_commit: first, the oldest commit on the branch since its base
+func jiraSeedMessage(g *git.Git) (string, error) {
+	switch seed := viper.GetString("jira_seed_commit"); seed {
+	case "first":
+		if base, err := resolveBaseBranch(g); err == nil {
+			commits, err := g.GetCommits(base + "..HEAD")
+			if err == nil && len(commits) > 0 {
+				return commits[len(commits)-1].Subject, nil
+			}
+		}
+		fmt.Println("⚠️  Warning: Could not find the branch's first commit, using the last one")
+	case "", "last":
+	default:
+		fmt.Printf("⚠️  Warning: Unknown jira_seed_commit %q, using the last commit\n", seed)
+	}
+
+	last, err := g.GetLastCommitMessage()
+	if err != nil {
+		return "", fmt.Errorf("failed to get last commit message: %w", err)
+	}
+	return subjectLine(last), nil
+}
+
+// resolveBaseBranch returns the configured base_branch or the remote's default branch
+func resolveBaseBranch(g *git.Git) (string, error) {
+	if base := viper.GetString("base_branch"); base != "" {
+		return base, nil
+	}
+	return g.DefaultBaseBranch()
+}
+
+// confirmBranchUpToDate warns when jira_check_base is enabled and the branch is
+// behind its base, so a stale branch can be rebased before a ticket is created.
+// It returns false if the user chooses to skip ticket creation.
+func confirmBranchUpToDate(g *git.Git) bool {
+	if !viper.GetBool("jira_check_base") {
+		return true
+	}
+
+	base, err := resolveBaseBranch(g)
+	if err != nil {
+		fmt.Printf("⚠️  Warning: Could not check base branch: %v\n", err)
+		return true
+	}
+
+	if err := g.FetchBranch(base); err != nil {
+		fmt.Printf("⚠️  Warning: Could not fetch %s, using last known state\n", base)
+	}
+
+	_, behind, err := g.AheadBehind(base)
+	if err != nil {
+		fmt.Printf("⚠️  Warning: Could not compare with %s: %v\n", base, err)
+		return true
+	}
+	if behind == 0 {
+		return true
+	}
+
+	fmt.Println()
+	fmt.Printf("⚠️  Branch is %d commit(s) behind %s. Consider rebasing first.\n", behind, base)
+	if autoConfirm {
+		return true
+	}
+
+	if !confirm("Create the Jira ticket anyway?", true) {
+		fmt.Println("⏭️  Skipped Jira ticket creation")
+		return false
+	}
+	return true
+}
+
+// showGeneratedMessage prints the generated message between separators
+func showGeneratedMessage(message string) {
+	fmt.Println()
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Println("📋 Generated commit message:")
+	fmt.Println()
+	for _, line := range strings.Split(message, "\n") {
+		fmt.Printf("   %s\n", line)
+	}
+	fmt.Println()
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Println()
+}
+
+// editMessage reads a replacement message from the terminal, keeping the
+// current message if nothing is entered
+func editMessage(message string) string {
+	if isInteractive() {
+		if edited, ok := editInEditor(message); ok {
+			return edited
+		}
+	}
+	fmt.Println("Enter your commit message (press Enter twice to finish):")
+	var lines []string
+	for {
+		line, err := stdin.ReadString('\n')
+		line = strings.TrimRight(line, "\n\r")
+		if (line == "" && len(lines) > 0) || (err != nil && line == "") {
+			break
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) > 0 {
+		return strings.Join(lines, "\n")
+	}
+	return message
+}
+
+// editInEditor opens the message in git's configured editor and returns it
+// with comment lines (core.commentChar) removed. It reports false when no
+// editor is configured or it couldn't be run, so the caller can fall back to
+// reading the message from the terminal.
+func editInEditor(message string) (string, bool) {
+	g := git.New("")
+	editor, err := g.Editor()
+	if err != nil || editor == "" {
+		return "", false
+	}
+	commentChar := g.CommentChar()
+
+	f, err := os.CreateTemp("", "gh-assistant-*.txt")
+	if err != nil {
+		return "", false
+	}
+	defer os.Remove(f.Name())
+	fmt.Fprintf(f, "%s\n\n%s Edit the commit message above. Lines starting with %q are ignored,\n%s and an empty message keeps the original.\n",
+		message, commentChar, commentChar, commentChar)
+	f.Close()
+
+	// The editor setting may include arguments, so let the shell split it
+	cmd := exec.Command("sh", "-c", editor+` "$@"`, editor, f.Name())
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		fmt.Printf("⚠️  Warning: editor failed: %v\n", err)
+		return "", false
+	}
+
+	data, err := os.ReadFile(f.Name())
+	if err != nil {
+		return "", false
+	}
+	edited := strings.TrimSpace(stripCommentLines(string(data), commentChar))
+	if edited == "" {
+		return message, true
+	}
+	return edited, true
+}
+
+// linkedIssueNote fetches a Jira issue and describes it for the prompt
+func linkedIssueNote(issueKey string) (string, error) {
+	jiraClient := newJiraClient()
+	if !jiraClient.IsConfigured() {
+		return "", fmt.Errorf("--issue requires Jira to be configured (see 'gh-assistant config --help')")
+	}
+
+	fmt.Printf("🎫 Fetching Jira issue %s...\n", issueKey)
+	issue, err := jiraClient.GetIssue(issueKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch Jira issue %s: %w", issueKey, err)
+	}
+
+	return fmt.Sprintf("This change implements Jira issue %s: %q. Use it to describe the purpose of the change.",
+		issue.Key, issue.Fields.Summary), nil
+}
+
+// jiraDescription builds the ticket description from an AI summary of the
+// branch's changes (unless disabled with jira_ai_description: false) and, with
+// jira_list_commits, the subjects of all the commits being pushed. It returns
+// "" (no description) if neither is available.
+func jiraDescription(g *git.Git) string {
+	var parts []string
+	if !viper.IsSet("jira_ai_description") || viper.GetBool("jira_ai_description") {
+		if summary := branchSummary(g, "ticket"); summary != "" {
+			parts = append(parts, summary)
+		}
+	}
+	if viper.GetBool("jira_list_commits") {
+		if list := branchCommitList(g); list != "" {
+			parts = append(parts, list)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// branchCommitList lists the subjects of the commits on the branch since its
+// base, oldest first. A new branch has no upstream yet, so this covers every
+// commit in the push.
+func branchCommitList(g *git.Git) string {
+	base, err := resolveBaseBranch(g)
+	if err != nil {
+		fmt.Printf("⚠️  Warning: Skipping commit list: %v\n", err)
+		return ""
+	}
+	commits, err := g.GetCommits(base + "..HEAD")
+	if err != nil || len(commits) == 0 {
+		return ""
+	}
+
+	lines := []string{"Commits:"}
+	for i := len(commits) - 1; i >= 0; i-- {
+		lines = append(lines, "- "+commits[i].Subject)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// branchSummary asks the AI for a summary of the changes since the base
+// branch, for use as the description of a ticket or pull request (what).
+// It returns "" if the summary can't be generated.
+func branchSummary(g *git.Git, what string) string {
+	return summarizeBranch(g, what, "")
+}
+
+// summarizeBranch is branchSummary, also explaining how the changes meet
+// requirements when they are given
+func summarizeBranch(g *git.Git, what, requirements string) string {
+	apiKey, err := requireAPIKey()
+	if err != nil {
+		return ""
+	}
+
+	base, err := resolveBaseBranch(g)
+	if err != nil {
+		fmt.Printf("⚠️  Warning: Skipping %s description: %v\n", what, err)
+		return ""
+	}
+	diff, err := g.GetBranchDiff(base)
+	if err != nil || diff == "" {
+		return ""
+	}
+
+	fmt.Printf("🤖 Summarizing branch changes for the %s...\n", what)
+	aiClient := newAIClient(g, resolveProvider(), apiKey)
+	ctx, stop := aiContext()
+	var summary string
+	if requirements != "" {
+		summary, err = aiClient.GeneratePullRequestSummary(ctx, diff, requirements)
+	} else {
+		summary, err = aiClient.GenerateChangeSummary(ctx, diff)
+	}
+	stop()
+	recordSpend(aiClient)
+	if err != nil {
+		fmt.Printf("⚠️  Warning: Could not generate %s description: %v\n", what, err)
+		return ""
+	}
+	return summary
 }
 
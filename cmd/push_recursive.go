@@ -0,0 +1,231 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/namin2/gh-assistant/internal/ai"
+	"github.com/namin2/gh-assistant/internal/git"
+	"github.com/spf13/viper"
+)
+
+// Outcomes of the push flow in one repository
+const (
+	outcomePushed    = "pushed"
+	outcomeSkipped   = "skipped"
+	outcomeNoChanges = "no-changes"
+	outcomeFailed    = "failed"
+)
+
+// repoOutcome is the result of the push flow in one repository
+type repoOutcome struct {
+	Repo   string `json:"repo"`
+	Status string `json:"status"`
+	// Subject is the pushed commit's subject line
+	Subject string `json:"subject,omitempty"`
+	// Error is the reason the flow failed
+	Error string `json:"error,omitempty"`
+}
+
+// result describes the outcome for the summary table
+func (o repoOutcome) result() string {
+	switch o.Status {
+	case outcomePushed:
+		return "✅ " + o.Subject
+	case outcomeFailed:
+		return "❌ " + o.Error
+	case outcomeNoChanges:
+		return "⏭️  no changes"
+	default:
+		return "⏭️  skipped"
+	}
+}
+
+// runPushRecursive runs the push flow in every repository under dir that has
+// staged changes (or any changes with --all) and ends with a summary table
+func runPushRecursive(dir, apiKey string, provider ai.Provider) error {
+	repos, err := git.FindRepos(dir)
+	if err != nil {
+		return fmt.Errorf("failed to search %s for repositories: %w", dir, err)
+	}
+	if len(repos) == 0 {
+		return fmt.Errorf("no git repositories found under %s", dir)
+	}
+
+	outcomes := make([]repoOutcome, len(repos))
+	var pending []int
+	for i, repo := range repos {
+		name, _ := filepath.Rel(dir, repo)
+		outcomes[i] = repoOutcome{Repo: name, Status: outcomeNoChanges}
+		if hasChangesToPush(git.New(repo)) {
+			pending = append(pending, i)
+		}
+	}
+
+	n := concurrencyLimit()
+	if n > 1 && len(pending) > 1 {
+		if err := pushConcurrently(repos, pending, outcomes, n, apiKey, provider); err != nil {
+			return err
+		}
+	} else {
+		for _, i := range pending {
+			fmt.Println()
+			fmt.Printf("━━━ 📁 %s ━━━\n", outcomes[i].Repo)
+			outcomes[i] = pushOne(repos[i], outcomes[i].Repo, apiKey, provider)
+			if outcomes[i].Status == outcomeFailed {
+				fmt.Println(outcomes[i].result())
+			}
+		}
+	}
+
+	failed := 0
+	for _, o := range outcomes {
+		if o.Status == outcomeFailed {
+			failed++
+		}
+	}
+	if batchJSON != nil {
+		if err := json.NewEncoder(batchJSON).Encode(batchSummary{Repositories: outcomes, Failed: failed}); err != nil {
+			return fmt.Errorf("failed to write summary: %w", err)
+		}
+	} else {
+		printBatchSummary(outcomes)
+	}
+
+	if failed > 0 && failOnError() {
+		return fmt.Errorf("%d of %d repositories failed", failed, len(repos))
+	}
+	return nil
+}
+
+// batchSummary is the --json output of push --recursive
+type batchSummary struct {
+	Repositories []repoOutcome `json:"repositories"`
+	Failed       int           `json:"failed"`
+}
+
+// printBatchSummary prints a table with one line per repository
+func printBatchSummary(outcomes []repoOutcome) {
+	fmt.Println()
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, o := range outcomes {
+		fmt.Fprintf(w, "%s\t%s\n", o.Repo, o.result())
+	}
+	w.Flush()
+}
+
+// failOnError reports whether a failed repository makes the batch exit
+// non-zero (batch_fail_on_error, default true)
+func failOnError() bool {
+	return !viper.IsSet("batch_fail_on_error") || viper.GetBool("batch_fail_on_error")
+}
+
+// pushConcurrently runs the push flow in the pending repositories at the same
+// time, with at most n AI requests in flight. Prompts can't be shown for
+// several repositories at once, so it needs -y, and the usual status lines
+// are replaced by one line per finished repository.
+func pushConcurrently(repos []string, pending []int, outcomes []repoOutcome, n int, apiKey string, provider ai.Provider) error {
+	if !autoConfirm {
+		return fmt.Errorf("--concurrency above 1 needs -y, since prompts can't be shown for several repositories at once")
+	}
+	// Ask about the push guard once rather than once per repository
+	if err := checkPushGuard(); err != nil {
+		return err
+	}
+	forceTime = true
+
+	aiLimiter = ai.NewLimiter(n)
+	defer func() { aiLimiter = nil }()
+
+	fmt.Printf("🚀 Pushing %d repositories, at most %d AI requests at a time...\n", len(pending), n)
+	out := io.Writer(os.Stdout)
+	if !quiet && batchJSON == nil {
+		var restore func()
+		var err error
+		out, restore, err = silenceStdout()
+		if err != nil {
+			return err
+		}
+		defer restore()
+	}
+
+	// Worktrees of one repository share its refs, config and objects, so
+	// they are committed and pushed one at a time
+	repoLocks := make(map[string]*sync.Mutex)
+	locks := make([]*sync.Mutex, len(repos))
+	for _, i := range pending {
+		dir, err := git.New(repos[i]).CommonDir()
+		if err != nil {
+			dir = repos[i]
+		}
+		if repoLocks[dir] == nil {
+			repoLocks[dir] = &sync.Mutex{}
+		}
+		locks[i] = repoLocks[dir]
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, i := range pending {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			locks[i].Lock()
+			outcome := pushOne(repos[i], outcomes[i].Repo, apiKey, provider)
+			locks[i].Unlock()
+
+			mu.Lock()
+			defer mu.Unlock()
+			outcomes[i] = outcome
+			fmt.Fprintf(out, "%s: %s\n", outcome.Repo, outcome.result())
+		}(i)
+	}
+	wg.Wait()
+	return nil
+}
+
+// pushOne runs the push flow in a repository and describes the outcome
+func pushOne(repo, name, apiKey string, provider ai.Provider) repoOutcome {
+	g := git.New(repo)
+	before, _ := g.HeadCommit()
+	if err := pushRepo(g, apiKey, provider); err != nil {
+		return repoOutcome{Repo: name, Status: outcomeFailed, Error: strings.TrimSpace(err.Error())}
+	}
+
+	after, _ := g.HeadCommit()
+	if after == before {
+		return repoOutcome{Repo: name, Status: outcomeSkipped}
+	}
+	message, _ := g.GetLastCommitMessage()
+	return repoOutcome{Repo: name, Status: outcomePushed, Subject: subjectLine(message)}
+}
+
+// concurrencyLimit returns --concurrency, or the concurrency setting when the
+// flag isn't given
+func concurrencyLimit() int {
+	if concurrency > 0 {
+		return concurrency
+	}
+	return viper.GetInt("concurrency")
+}
+
+// hasChangesToPush reports whether the push flow has anything to commit in
+// the repository: staged changes, or any changes when --all is given
+func hasChangesToPush(g *git.Git) bool {
+	if staged, _ := g.HasStagedChanges(); staged {
+		return true
+	}
+	if !stageAll {
+		return false
+	}
+	// --all also stages untracked files, which only status shows
+	status, _ := g.GetStatus()
+	return status != ""
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/namin2/gh-assistant/internal/ai"
+	"github.com/spf13/viper"
+)
+
+// setupGitEnv isolates git from the user's configuration
+func setupGitEnv(t *testing.T) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+}
+
+// runGit runs git in dir and returns its trimmed output
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// newPushableRepo creates a repository at dir tracking a bare remote, with
+// a change staged
+func newPushableRepo(t *testing.T, dir string) (remote string) {
+	t.Helper()
+	remote = dir + ".git"
+	runGit(t, filepath.Dir(dir), "init", "-q", "--bare", remote)
+	runGit(t, filepath.Dir(dir), "init", "-q", "-b", "main", dir)
+	writeFile(t, filepath.Join(dir, "README.md"), "hello\n")
+	runGit(t, dir, "add", ".")
+	runGit(t, dir, "commit", "-q", "-m", "chore: initial commit")
+	runGit(t, dir, "remote", "add", "origin", remote)
+	runGit(t, dir, "push", "-q", "-u", "origin", "main")
+
+	writeFile(t, filepath.Join(dir, "README.md"), "hello\nworld\n")
+	runGit(t, dir, "add", ".")
+	return remote
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPushRecursiveBoundsAIRequestsInFlight(t *testing.T) {
+	setupGitEnv(t)
+	const limit, repos = 2, 5
+
+	var mu sync.Mutex
+	inFlight, peak := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		peak = max(peak, inFlight)
+		over := inFlight > limit
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+		}()
+
+		if over {
+			http.Error(w, `{"error": {"message": "too many requests in flight"}}`, http.StatusTooManyRequests)
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"choices": [{"message": {"content": "docs: add world"}}]}`))
+	}))
+	defer server.Close()
+
+	viper.Set("openai_base_url", server.URL)
+	viper.Set("retry_max_attempts", 1)
+	autoConfirm, concurrency = true, limit
+	t.Cleanup(func() {
+		viper.Set("openai_base_url", "")
+		viper.Set("retry_max_attempts", 0)
+		autoConfirm, concurrency, forceTime = false, 0, false
+	})
+
+	root := t.TempDir()
+	var remotes []string
+	for i := 0; i < repos; i++ {
+		remotes = append(remotes, newPushableRepo(t, filepath.Join(root, string(rune('a'+i)))))
+	}
+
+	if err := runPushRecursive(root, "test", ai.ProviderOpenAI); err != nil {
+		t.Fatalf("runPushRecursive() error = %v", err)
+	}
+
+	for _, remote := range remotes {
+		if got := runGit(t, remote, "log", "-1", "--format=%s", "main"); got != "docs: add world" {
+			t.Errorf("%s: pushed %q, want %q", filepath.Base(remote), got, "docs: add world")
+		}
+	}
+	if peak != limit {
+		t.Errorf("peak AI requests in flight = %d, want %d", peak, limit)
+	}
+}
+
+func TestPushRecursiveUsesEachRepositorysPromptTemplate(t *testing.T) {
+	setupGitEnv(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Messages []struct {
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		// Answer with the first line of the prompt, which names the template
+		message := "chore: no template"
+		if len(req.Messages) > 0 {
+			message = strings.SplitN(req.Messages[len(req.Messages)-1].Content, "\n", 2)[0]
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"choices": []interface{}{map[string]interface{}{"message": map[string]string{"content": message}}},
+		})
+	}))
+	defer server.Close()
+
+	viper.Set("openai_base_url", server.URL)
+	viper.Set("commit_prompt_template", ".github/commit-prompt.tmpl")
+	autoConfirm, forceTime = true, true
+	t.Cleanup(func() {
+		viper.Set("openai_base_url", "")
+		viper.Set("commit_prompt_template", "")
+		autoConfirm, forceTime = false, false
+	})
+
+	root := t.TempDir()
+	remotes := map[string]string{}
+	for _, name := range []string{"api", "web"} {
+		dir := filepath.Join(root, name)
+		remotes[name] = newPushableRepo(t, dir)
+		if err := os.MkdirAll(filepath.Join(dir, ".github"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		writeFile(t, filepath.Join(dir, ".github", "commit-prompt.tmpl"), "docs: use the "+name+" template\n{{.Diff}}")
+	}
+
+	if err := runPushRecursive(root, "test", ai.ProviderOpenAI); err != nil {
+		t.Fatalf("runPushRecursive() error = %v", err)
+	}
+
+	for name, remote := range remotes {
+		want := "docs: use the " + name + " template"
+		if got := runGit(t, remote, "log", "-1", "--format=%s", "main"); got != want {
+			t.Errorf("%s: pushed %q, want %q", name, got, want)
+		}
+	}
+}
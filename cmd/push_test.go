@@ -0,0 +1,335 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/namin2/gh-assistant/internal/ai"
+	"github.com/namin2/gh-assistant/internal/git"
+	"github.com/spf13/viper"
+)
+
+// scriptProvider's backend answers with the messages given to
+// newScriptedClient, in turn
+const scriptProvider ai.Provider = "script"
+
+// script holds the answers and received prompts of a scripted client
+type script struct {
+	mu      sync.Mutex
+	answers []string
+	prompts []string
+}
+
+var scripts sync.Map // *ai.Client -> *script
+
+func init() {
+	ai.RegisterBackend(scriptProvider, func(c *ai.Client) ai.Backend {
+		return ai.BackendFunc(func(ctx context.Context, prompt string) (string, error) {
+			v, _ := scripts.Load(c)
+			s := v.(*script)
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.prompts = append(s.prompts, prompt)
+			answer := s.answers[0]
+			if len(s.answers) > 1 {
+				s.answers = s.answers[1:]
+			}
+			return answer, nil
+		})
+	})
+}
+
+// newScriptedClient returns a client answering requests with the answers in
+// turn, repeating the last, and the script recording its prompts
+func newScriptedClient(t *testing.T, answers ...string) (*ai.Client, *script) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	c := ai.New(ai.Config{Provider: scriptProvider, Model: "gpt-4o"})
+	s := &script{answers: answers}
+	scripts.Store(c, s)
+	t.Cleanup(func() { scripts.Delete(c) })
+	return c, s
+}
+
+// useMessageServer points the OpenAI provider at a server answering every
+// request with message, and auto-confirms prompts, for the test's duration
+func useMessageServer(t *testing.T, message string) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"choices": [{"message": {"content": "` + message + `"}}]}`))
+	}))
+	viper.Set("openai_base_url", server.URL)
+	autoConfirm, forceTime = true, true
+	t.Cleanup(func() {
+		server.Close()
+		viper.Set("openai_base_url", "")
+		autoConfirm, forceTime = false, false
+	})
+}
+
+func TestPushToExistingRemoteBranch(t *testing.T) {
+	tests := []struct {
+		name      string
+		diverged  bool
+		wantError bool
+	}{
+		{"fast-forward", false, false},
+		{"diverged", true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupGitEnv(t)
+			useMessageServer(t, "docs: add world")
+
+			dir := filepath.Join(t.TempDir(), "repo")
+			remote := newPushableRepo(t, dir)
+			runGit(t, dir, "checkout", "-q", "-b", "topic")
+			existing := runGit(t, dir, "rev-parse", "HEAD")
+			if tt.diverged {
+				existing = runGit(t, dir, "commit-tree", "-p", "HEAD", "-m", "chore: pushed elsewhere", "HEAD^{tree}")
+			}
+			runGit(t, dir, "push", "-q", "origin", existing+":refs/heads/topic")
+
+			err := pushRepo(git.New(dir), "test", ai.ProviderOpenAI)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("pushRepo() error = %v, want error %v", err, tt.wantError)
+			}
+
+			want := "docs: add world"
+			if tt.wantError {
+				want = "chore: pushed elsewhere"
+			}
+			if got := runGit(t, remote, "log", "-1", "--format=%s", "topic"); got != want {
+				t.Errorf("remote topic is at %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestEnforceForbidden(t *testing.T) {
+	tests := []struct {
+		name     string
+		strict   bool
+		message  string
+		answers  []string
+		want     string
+		requests int
+	}{
+		{
+			name:    "clean message",
+			message: "fix: handle empty input",
+			want:    "fix: handle empty input",
+		},
+		{
+			name:    "masked without --strict",
+			message: "fix: Hotfix the JIRA sync",
+			want:    "fix: ****** the **** sync",
+		},
+		{
+			name:     "regenerated with --strict",
+			strict:   true,
+			message:  "fix: hotfix the sync",
+			answers:  []string{"fix: repair the sync"},
+			want:     "fix: repair the sync",
+			requests: 1,
+		},
+		{
+			// checkStrict rejects the words that remain
+			name:     "regenerated once with --strict",
+			strict:   true,
+			message:  "fix: hotfix the sync",
+			answers:  []string{"fix: another hotfix"},
+			want:     "fix: another hotfix",
+			requests: 1,
+		},
+		{
+			name:    "longer words aren't forbidden",
+			message: "fix: apply hotfixes",
+			want:    "fix: apply hotfixes",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Set("forbidden_words", []string{"hotfix", "JIRA"})
+			strict = tt.strict
+			t.Cleanup(func() {
+				viper.Set("forbidden_words", nil)
+				strict = false
+			})
+			client, s := newScriptedClient(t, append(tt.answers, "unexpected request")...)
+
+			req := ai.CommitRequest{Diff: "+x"}
+			got, err := enforceForbidden(context.Background(), client, req, tt.message, func(s string) string { return s })
+			if err != nil {
+				t.Fatalf("enforceForbidden() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("enforceForbidden() = %q, want %q", got, tt.want)
+			}
+			if len(s.prompts) != tt.requests {
+				t.Fatalf("enforceForbidden() made %d requests, want %d", len(s.prompts), tt.requests)
+			}
+			if tt.requests > 0 && !strings.Contains(s.prompts[0], "The previous message used hotfix") {
+				t.Errorf("regeneration prompt doesn't name the forbidden word:\n%s", s.prompts[0])
+			}
+		})
+	}
+}
+
+func TestRequireSections(t *testing.T) {
+	const complete = "feat: add login\n\nMotivation: users asked for it.\n\nTesting: manual."
+	tests := []struct {
+		name     string
+		message  string
+		answers  []string
+		want     string
+		requests int
+	}{
+		{
+			name:    "sections present",
+			message: complete,
+			want:    complete,
+		},
+		{
+			name:    "sections in any case",
+			message: "feat: add login\n\nmotivation: users asked for it.\n  TESTING: manual.",
+			want:    "feat: add login\n\nmotivation: users asked for it.\n  TESTING: manual.",
+		},
+		{
+			name:     "missing section regenerated",
+			message:  "feat: add login\n\nMotivation: users asked for it.",
+			answers:  []string{complete},
+			want:     complete,
+			requests: 1,
+		},
+		{
+			name:     "still missing after regenerating",
+			message:  "feat: add login",
+			answers:  []string{"feat: add login\n\nTesting: manual."},
+			want:     "feat: add login\n\nTesting: manual.",
+			requests: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Set("required_sections", []string{"Motivation", "Testing:"})
+			t.Cleanup(func() { viper.Set("required_sections", nil) })
+			client, s := newScriptedClient(t, append(tt.answers, "unexpected request")...)
+
+			req := ai.CommitRequest{Diff: "+x"}
+			got, err := requireSections(context.Background(), client, req, tt.message, func(s string) string { return s })
+			if err != nil {
+				t.Fatalf("requireSections() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("requireSections() = %q, want %q", got, tt.want)
+			}
+			if len(s.prompts) != tt.requests {
+				t.Fatalf("requireSections() made %d requests, want %d", len(s.prompts), tt.requests)
+			}
+			if tt.requests > 0 && !strings.Contains(s.prompts[0], "lacked the ") {
+				t.Errorf("regeneration prompt doesn't name the missing sections:\n%s", s.prompts[0])
+			}
+		})
+	}
+}
+
+func TestPushToUpstreamOfAnotherName(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   interface{}
+		pushedTo string
+	}{
+		{"default", nil, "bugfix-123"},
+		{"push_to_upstream: true", true, "bugfix-123"},
+		{"push_to_upstream: false", false, "fix"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupGitEnv(t)
+			useMessageServer(t, "docs: add world")
+			if tt.config != nil {
+				viper.Set("push_to_upstream", tt.config)
+				t.Cleanup(func() { viper.Set("push_to_upstream", nil) })
+			}
+
+			dir := filepath.Join(t.TempDir(), "repo")
+			remote := newPushableRepo(t, dir)
+			runGit(t, dir, "checkout", "-q", "-b", "fix")
+			runGit(t, dir, "push", "-q", "-u", "origin", "fix:bugfix-123")
+
+			if err := pushRepo(git.New(dir), "test", ai.ProviderOpenAI); err != nil {
+				t.Fatalf("pushRepo() error = %v", err)
+			}
+
+			if got := runGit(t, remote, "log", "-1", "--format=%s", tt.pushedTo); got != "docs: add world" {
+				t.Errorf("remote %s is at %q, want %q", tt.pushedTo, got, "docs: add world")
+			}
+		})
+	}
+}
+
+func TestPushInLinkedWorktree(t *testing.T) {
+	setupGitEnv(t)
+	useMessageServer(t, "docs: add world")
+
+	main := filepath.Join(t.TempDir(), "repo")
+	remote := newPushableRepo(t, main)
+	runGit(t, main, "reset", "-q", "--hard")
+	wt := filepath.Join(t.TempDir(), "wt")
+	runGit(t, main, "worktree", "add", "-q", "-b", "topic", wt)
+	runGit(t, wt, "push", "-q", "-u", "origin", "topic")
+	writeFile(t, filepath.Join(wt, "README.md"), "hello\nworld\n")
+	runGit(t, wt, "add", ".")
+
+	if err := pushRepo(git.New(wt), "test", ai.ProviderOpenAI); err != nil {
+		t.Fatalf("pushRepo() error = %v", err)
+	}
+
+	if got := runGit(t, remote, "log", "-1", "--format=%s", "topic"); got != "docs: add world" {
+		t.Errorf("remote topic is at %q, want %q", got, "docs: add world")
+	}
+	if got := runGit(t, remote, "log", "-1", "--format=%s", "main"); got != "chore: initial commit" {
+		t.Errorf("remote main is at %q, want it unchanged", got)
+	}
+	if got := runGit(t, main, "status", "--porcelain"); got != "" {
+		t.Errorf("main worktree has changes after pushing from the linked one:\n%s", got)
+	}
+}
+
+func TestPushWithMockFallback(t *testing.T) {
+	setupGitEnv(t)
+	t.Setenv("OPENAI_API_KEY", "")
+	viper.Set("provider", "openai")
+	mockFallback, autoConfirm, forceTime = true, true, true
+	t.Cleanup(func() {
+		viper.Set("provider", "")
+		mockFallback, autoConfirm, forceTime = false, false, false
+		providerOverride = ""
+	})
+
+	dir := filepath.Join(t.TempDir(), "repo")
+	remote := newPushableRepo(t, dir)
+
+	apiKey, err := requireAPIKey()
+	if err != nil {
+		t.Fatalf("requireAPIKey() error = %v", err)
+	}
+	if provider := resolveProvider(); provider != ai.ProviderMock {
+		t.Fatalf("resolveProvider() = %s without an API key, want mock", provider)
+	}
+	if err := pushRepo(git.New(dir), apiKey, resolveProvider()); err != nil {
+		t.Fatalf("pushRepo() error = %v", err)
+	}
+
+	want := "docs: update README.md\n\n1 file(s) changed, 1 insertion(s)(+), 0 deletion(s)(-)"
+	if got := runGit(t, remote, "log", "-1", "--format=%B", "main"); got != want {
+		t.Errorf("remote main is at %q, want %q", got, want)
+	}
+}
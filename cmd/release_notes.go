@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/namin2/gh-assistant/internal/commitmsg"
+	"github.com/namin2/gh-assistant/internal/git"
+	"github.com/spf13/cobra"
+)
+
+var rawReleaseNotes bool
+
+var releaseNotesCmd = &cobra.Command{
+	Use:   "release-notes <range>",
+	Short: "Generate user-facing release notes for a range of commits",
+	Long: `Collects the commits in a revision range, groups them by conventional commit
+type, and uses AI to write release notes with Features, Fixes and Breaking
+Changes sections.
+
+Examples:
+  gh-assistant release-notes v1.0..v1.1
+  gh-assistant release-notes v1.0..HEAD --raw   # Grouped commits, no AI`,
+	Args: cobra.ExactArgs(1),
+	RunE: runReleaseNotes,
+}
+
+func init() {
+	rootCmd.AddCommand(releaseNotesCmd)
+	releaseNotesCmd.Flags().BoolVar(&rawReleaseNotes, "raw", false, "Print the grouped commits without AI summarization")
+}
+
+func runReleaseNotes(cmd *cobra.Command, args []string) error {
+	if _, err := git.CheckInstalled(); err != nil {
+		return err
+	}
+
+	g := git.New("")
+	if !g.IsRepo() {
+		return fmt.Errorf("not a git repository")
+	}
+
+	revRange := args[0]
+	commits, err := g.GetCommits(revRange)
+	if err != nil {
+		return fmt.Errorf("failed to read commits: %w", err)
+	}
+	if len(commits) == 0 {
+		return fmt.Errorf("no commits in range %s", revRange)
+	}
+
+	changelog := groupCommits(commits)
+	if rawReleaseNotes {
+		fmt.Print(changelog)
+		return nil
+	}
+
+	apiKey, err := requireAPIKey()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("🤖 Writing release notes for %d commit(s)...\n\n", len(commits))
+
+	aiClient := newAIClient(g, resolveProvider(), apiKey)
+	ctx, stop := aiContext()
+	notes, err := aiClient.GenerateReleaseNotes(ctx, releaseVersion(revRange), changelog)
+	stop()
+	recordSpend(aiClient)
+	if err != nil {
+		return fmt.Errorf("failed to generate release notes: %w", err)
+	}
+
+	fmt.Println(notes)
+	return nil
+}
+
+// releaseGroups orders changelog sections; commits match the first group whose
+// types include theirs, and anything unmatched falls into "Other Changes"
+var releaseGroups = []struct {
+	title string
+	types []string
+}{
+	{"Features", []string{"feat"}},
+	{"Fixes", []string{"fix"}},
+	{"Performance", []string{"perf"}},
+	{"Other Changes", nil},
+}
+
+// groupCommits renders commits as a changelog grouped by conventional commit type,
+// with breaking changes listed first
+func groupCommits(commits []git.Commit) string {
+	var breaking []string
+	grouped := make(map[string][]string)
+
+	for _, c := range commits {
+		line := c.Subject
+		title := "Other Changes"
+
+		if header, ok := commitmsg.ParseHeader(c.Subject); ok {
+			line = header.Description
+			if header.Scope != "" {
+				line = header.Scope + ": " + line
+			}
+			for _, group := range releaseGroups {
+				if containsString(group.types, strings.ToLower(header.Type)) {
+					title = group.title
+					break
+				}
+			}
+		}
+
+		if commitmsg.IsBreaking(commitmsg.Join(c.Subject, c.Body)) {
+			breaking = append(breaking, line)
+		}
+		grouped[title] = append(grouped[title], line)
+	}
+
+	var b strings.Builder
+	writeSection := func(title string, lines []string) {
+		if len(lines) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "## %s\n", title)
+		for _, line := range lines {
+			fmt.Fprintf(&b, "- %s\n", line)
+		}
+		b.WriteString("\n")
+	}
+
+	writeSection("Breaking Changes", breaking)
+	for _, group := range releaseGroups {
+		writeSection(group.title, grouped[group.title])
+	}
+	return b.String()
+}
+
+// releaseVersion returns the target version of a range like "v1.0..v1.1"
+func releaseVersion(revRange string) string {
+	if i := strings.LastIndex(revRange, ".."); i >= 0 && i+2 < len(revRange) {
+		return strings.TrimPrefix(revRange[i+2:], ".")
+	}
+	return revRange
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
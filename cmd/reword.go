@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/namin2/gh-assistant/internal/ai"
+	"github.com/namin2/gh-assistant/internal/commitmsg"
+	"github.com/namin2/gh-assistant/internal/git"
+)
+
+// runAmendMessageOnly rewords the last commit with the --message text or a
+// message regenerated from its diff. The commit's contents are left untouched
+// and commits that were already pushed are refused.
+func runAmendMessageOnly() error {
+	g := git.New("")
+	if !g.IsRepo() {
+		return fmt.Errorf("not a git repository")
+	}
+
+	if op := g.InProgressOperation(); op != nil {
+		return fmt.Errorf("a %s is in progress; finish it with 'git %s --continue' before rewording", op.Kind, op.Kind)
+	}
+
+	pushed, err := g.IsHeadPushed()
+	if err != nil {
+		return fmt.Errorf("failed to check whether HEAD was pushed: %w", err)
+	}
+	if pushed {
+		return fmt.Errorf("the last commit has already been pushed; rewording it would rewrite published history")
+	}
+
+	current, err := g.GetLastCommitMessage()
+	if err != nil {
+		return fmt.Errorf("failed to get last commit message: %w", err)
+	}
+	fmt.Printf("✏️  Rewording: %s\n", subjectLine(current))
+
+	message := newMessage
+	if message != "" && breaking {
+		message = commitmsg.MarkBreaking(message)
+	}
+	generate := func() (string, error) {
+		return "", fmt.Errorf("cannot regenerate a message given with --message")
+	}
+	// explain adds the plain_summary trailer to generated messages
+	var explain func(string) string
+
+	if message == "" {
+		apiKey, err := requireAPIKey()
+		if err != nil {
+			return err
+		}
+
+		diff, err := g.GetCommitDiff("HEAD")
+		if err != nil {
+			return fmt.Errorf("failed to get last commit diff: %w", err)
+		}
+		branch, _ := g.GetCurrentBranch()
+		req := ai.CommitRequest{Diff: diff, Branch: branch}
+		describeModeChanges(&req)
+		excludeGenerated(&req)
+		checkBreaking(&req)
+		aiClient := newAIClient(g, resolveProvider(), apiKey)
+
+		generate = func() (string, error) {
+			fmt.Println("🤖 Generating commit message...")
+			ctx, stop := aiContext()
+			defer stop()
+			message, err := aiClient.GenerateCommitMessage(ctx, req)
+			recordSpend(aiClient)
+			if err == nil {
+				message, err = enforceForbidden(ctx, aiClient, req, message, func(s string) string { return s })
+			}
+			if err == nil {
+				message, err = requireSections(ctx, aiClient, req, message, func(s string) string { return s })
+			}
+			if err == nil {
+				message, err = rejectVerbatim(ctx, aiClient, req, diff, message, func(s string) string { return s })
+			}
+			return message, err
+		}
+		explain = func(message string) string {
+			return addPlainSummary(g, aiClient, req.Diff, message, func(s string) string { return s })
+		}
+
+		message, err = generate()
+		if err != nil {
+			message, err = offlineFallback(err, req, func(s string) string { return s })
+		}
+		if err != nil {
+			return fmt.Errorf("failed to generate commit message: %w", err)
+		}
+	}
+
+	message, ok, err := reviewMessage(message, generate, nil)
+	if err != nil || !ok {
+		return err
+	}
+	if explain != nil {
+		message = explain(message)
+	}
+
+	message, err = g.NormalizeTrailers(message)
+	if err != nil {
+		return fmt.Errorf("failed to normalize commit trailers: %w", err)
+	}
+	message, err = applyCommitTrailers(g, message, linkedIssue)
+	if err != nil {
+		return fmt.Errorf("failed to add commit trailers: %w", err)
+	}
+
+	if err := checkStrict(message); err != nil {
+		return err
+	}
+
+	if dryRun {
+		fmt.Printf("🧪 Dry run: would reword the last commit to:\n%s\n", message)
+		return nil
+	}
+	if err := g.RewordLastCommit(message); err != nil {
+		return fmt.Errorf("failed to reword commit: %w", err)
+	}
+	fmt.Printf("✅ Reworded: %s\n", subjectLine(message))
+	return nil
+}
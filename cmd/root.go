@@ -1,8 +1,10 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -10,6 +12,10 @@ import (
 
 var cfgFile string
 
+// mockFallback uses the mock provider when the provider in use has no
+// credentials, so CI and test runs work without an API key
+var mockFallback bool
+
 var rootCmd = &cobra.Command{
 	Use:   "gh-assistant",
 	Short: "AI-powered Git commit message generator",
@@ -28,9 +34,18 @@ func Execute() {
 	}
 }
 
+// aiContext returns the context for AI requests, canceled when Ctrl-C is
+// pressed so the command fails cleanly and its deferred cleanups run instead
+// of the process being killed mid-request. Calling stop restores the usual
+// Ctrl-C behavior, so prompts shown afterwards can still be interrupted.
+func aiContext() (ctx context.Context, stop context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt)
+}
+
 func init() {
 	cobra.OnInitialize(initConfig)
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.gh-assistant.yaml)")
+	rootCmd.PersistentFlags().BoolVar(&mockFallback, "mock-fallback", false, "Use the offline mock provider when no API key is configured")
 }
 
 func initConfig() {
@@ -46,9 +61,12 @@ func initConfig() {
 	}
 
 	viper.AutomaticEnv()
+	// GH_ASSISTANT_YES=1 turns on auto_confirm, e.g. once for a whole CI job
+	viper.BindEnv("auto_confirm", "GH_ASSISTANT_YES")
 
 	if err := viper.ReadInConfig(); err == nil {
 		fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
+		warnUnknownConfigKeys()
 	}
 }
 
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/namin2/gh-assistant/internal/ai"
+	"github.com/spf13/cobra"
+)
+
+var (
+	rotateAPIKey   string
+	rotateProvider string
+)
+
+var rotateKeyCmd = &cobra.Command{
+	Use:   "rotate-key",
+	Short: "Replace the stored API key after validating it",
+	Long: `Validates a new API key against the provider and only saves it if the
+provider accepts it. If validation fails, the existing key is kept.
+
+Examples:
+  gh-assistant config rotate-key --api-key sk-new-xxx
+  gh-assistant config rotate-key --api-key sk-ant-new-xxx --provider anthropic`,
+	RunE: runRotateKey,
+}
+
+func init() {
+	configCmd.AddCommand(rotateKeyCmd)
+	rotateKeyCmd.Flags().StringVar(&rotateAPIKey, "api-key", "", "The new API key")
+	rotateKeyCmd.Flags().StringVar(&rotateProvider, "provider", "", "Provider the key belongs to (defaults to the configured provider)")
+	rotateKeyCmd.MarkFlagRequired("api-key")
+}
+
+func runRotateKey(cmd *cobra.Command, args []string) error {
+	provider := resolveProvider()
+	if rotateProvider != "" {
+		provider = ai.Provider(rotateProvider)
+		if !validProvider(provider) {
+			return fmt.Errorf("invalid provider: %s (use %s)", rotateProvider, providerNames())
+		}
+	}
+
+	if !provider.UsesAPIKey() {
+		return fmt.Errorf("%s doesn't use an API key", provider)
+	}
+
+	config, err := loadConfigFile()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("🔐 Validating new %s API key...\n", provider)
+
+	client := newAIClient(nil, provider, rotateAPIKey)
+	ctx, stop := aiContext()
+	defer stop()
+	if err := client.Validate(ctx); err != nil {
+		return fmt.Errorf("new key was not saved, keeping the existing key: %w", err)
+	}
+
+	// Only the key changes; --provider picks whose key, not the provider in use
+	config[apiKeyName(provider)] = rotateAPIKey
+	fmt.Println("✅ New API key validated")
+
+	return saveConfigFile(config)
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+func TestRotateKeyKeepsTheProvider(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	configPath := filepath.Join(home, ".gh-assistant.yaml")
+	writeFile(t, configPath, "provider: anthropic\nanthropic_api_key: sk-ant-old\n")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer sk-new" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"data": [{"id": "gpt-4o"}]}`))
+	}))
+	defer server.Close()
+	viper.Set("provider", "anthropic")
+	viper.Set("openai_base_url", server.URL)
+	rotateAPIKey, rotateProvider = "sk-new", "openai"
+	t.Cleanup(func() {
+		viper.Set("provider", "")
+		viper.Set("openai_base_url", "")
+		rotateAPIKey, rotateProvider = "", ""
+	})
+
+	if err := runRotateKey(rotateKeyCmd, nil); err != nil {
+		t.Fatalf("runRotateKey() error = %v", err)
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var config map[string]string
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"provider":          "anthropic",
+		"anthropic_api_key": "sk-ant-old",
+		"openai_api_key":    "sk-new",
+	}
+	for key, value := range want {
+		if config[key] != value {
+			t.Errorf("%s = %q after rotating the openai key, want %q", key, config[key], value)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/namin2/gh-assistant/internal/ai"
+	"github.com/namin2/gh-assistant/internal/git"
+	"github.com/spf13/cobra"
+)
+
+var suggestCmd = &cobra.Command{
+	Use:   "suggest",
+	Short: "Suggest a commit message for all your uncommitted changes",
+	Long: `Generates a commit message for everything changed since HEAD, staged or not,
+and prints it. Nothing is staged or committed.
+
+Examples:
+  gh-assistant suggest`,
+	Args: cobra.NoArgs,
+	RunE: runSuggest,
+}
+
+func init() {
+	rootCmd.AddCommand(suggestCmd)
+}
+
+func runSuggest(cmd *cobra.Command, args []string) error {
+	if _, err := git.CheckInstalled(); err != nil {
+		return err
+	}
+
+	g := git.New("")
+	if !g.IsRepo() {
+		return fmt.Errorf("not a git repository")
+	}
+
+	diff, err := g.GetAllDiff()
+	if err != nil {
+		return fmt.Errorf("failed to get diff: %w", err)
+	}
+	if diff == "" {
+		return fmt.Errorf("no changes to suggest a message for (untracked files aren't included)")
+	}
+
+	apiKey, err := requireAPIKey()
+	if err != nil {
+		return err
+	}
+
+	changedFiles, _ := g.GetChangedFiles()
+	branch, _ := g.GetCurrentBranch()
+	req := ai.CommitRequest{Diff: diff, Files: changedFiles, Branch: branch}
+	describeModeChanges(&req)
+	excludeGenerated(&req)
+
+	fmt.Println("🤖 Generating commit message...")
+	aiClient := newAIClient(g, resolveProvider(), apiKey)
+	ctx, stop := aiContext()
+	message, err := aiClient.GenerateCommitMessage(ctx, req)
+	stop()
+	recordSpend(aiClient)
+	if err != nil {
+		return fmt.Errorf("failed to generate commit message: %w", err)
+	}
+
+	showGeneratedMessage(message)
+	fmt.Println("💡 To commit everything with a fresh message: gh-assistant push -a")
+	return nil
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/namin2/gh-assistant/internal/ai"
+	"github.com/namin2/gh-assistant/internal/git"
+	"github.com/spf13/cobra"
+)
+
+var (
+	summarySince        string
+	summarySinceLastTag bool
+)
+
+var summaryCmd = &cobra.Command{
+	Use:   "summary",
+	Short: "Summarize changes with AI",
+	Long: `Summarizes the changes between a ref and HEAD, or the staged changes when
+no ref is given.
+
+Examples:
+  gh-assistant summary                     # Staged changes
+  gh-assistant summary --since main
+  gh-assistant summary --since-last-tag    # What's changed since the last release`,
+	Args: cobra.NoArgs,
+	RunE: runSummary,
+}
+
+func init() {
+	rootCmd.AddCommand(summaryCmd)
+	summaryCmd.Flags().StringVar(&summarySince, "since", "", "Summarize the changes from this ref to HEAD")
+	summaryCmd.Flags().BoolVar(&summarySinceLastTag, "since-last-tag", false, "Summarize the changes since the most recent tag")
+}
+
+func runSummary(cmd *cobra.Command, args []string) error {
+	if _, err := git.CheckInstalled(); err != nil {
+		return err
+	}
+	if summarySince != "" && summarySinceLastTag {
+		return fmt.Errorf("--since and --since-last-tag can't be used together")
+	}
+
+	g := git.New("")
+	if !g.IsRepo() {
+		return fmt.Errorf("not a git repository")
+	}
+
+	since := summarySince
+	if summarySinceLastTag {
+		tag, err := g.GetLastTag()
+		if err != nil {
+			return fmt.Errorf("failed to find the last tag: %w", err)
+		}
+		if tag == "" {
+			return fmt.Errorf("no tag is reachable from HEAD")
+		}
+		since = tag
+	}
+
+	var diff string
+	var err error
+	if since != "" {
+		fmt.Printf("🔍 Collecting changes since %s...\n", since)
+		diff, err = g.GetBranchDiff(since)
+	} else {
+		fmt.Println("🔍 Collecting staged changes...")
+		diff, err = g.GetStagedDiff()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get diff: %w", err)
+	}
+	if diff == "" {
+		return fmt.Errorf("no changes to summarize")
+	}
+
+	apiKey, err := requireAPIKey()
+	if err != nil {
+		return err
+	}
+
+	req := ai.CommitRequest{Diff: diff}
+	excludeGenerated(&req)
+
+	fmt.Println("🤖 Summarizing changes...")
+	aiClient := newAIClient(g, resolveProvider(), apiKey)
+	ctx, stop := aiContext()
+	summary, err := aiClient.GenerateChangeSummary(ctx, req.Diff)
+	stop()
+	recordSpend(aiClient)
+	if err != nil {
+		return fmt.Errorf("failed to generate summary: %w", err)
+	}
+
+	fmt.Println()
+	fmt.Println(summary)
+	return nil
+}
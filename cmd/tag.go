@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/namin2/gh-assistant/internal/git"
+	"github.com/spf13/cobra"
+)
+
+var (
+	rawTagMessage bool
+	tagYes        bool
+)
+
+var tagCmd = &cobra.Command{
+	Use:   "tag <name>",
+	Short: "Create an annotated tag with AI-written release notes",
+	Long: `Creates an annotated tag at HEAD whose message is written by AI from the
+commits since the previous tag, the same way as release-notes.
+
+Examples:
+  gh-assistant tag v1.2.0
+  gh-assistant tag v1.2.0 --raw   # Grouped commit list, no AI`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTag,
+}
+
+func init() {
+	rootCmd.AddCommand(tagCmd)
+	tagCmd.Flags().BoolVar(&rawTagMessage, "raw", false, "Use the grouped commit list without AI summarization")
+	tagCmd.Flags().BoolVarP(&tagYes, "yes", "y", false, "Create the tag without asking for confirmation")
+}
+
+func runTag(cmd *cobra.Command, args []string) error {
+	if _, err := git.CheckInstalled(); err != nil {
+		return err
+	}
+	tagYes = assumeYes(cmd, tagYes)
+
+	g := git.New("")
+	if !g.IsRepo() {
+		return fmt.Errorf("not a git repository")
+	}
+
+	name := args[0]
+	previous, err := g.GetLastTag()
+	if err != nil {
+		return fmt.Errorf("failed to find the previous tag: %w", err)
+	}
+
+	revRange := "HEAD"
+	if previous != "" {
+		revRange = previous + "..HEAD"
+		fmt.Printf("🏷️  Collecting commits since %s...\n", previous)
+	} else {
+		fmt.Println("🏷️  No previous tag, collecting all commits...")
+	}
+
+	commits, err := g.GetCommits(revRange)
+	if err != nil {
+		return fmt.Errorf("failed to read commits: %w", err)
+	}
+	if len(commits) == 0 {
+		return fmt.Errorf("no commits since %s", previous)
+	}
+
+	notes := groupCommits(commits)
+	if !rawTagMessage {
+		apiKey, err := requireAPIKey()
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("🤖 Writing release notes for %d commit(s)...\n", len(commits))
+		aiClient := newAIClient(g, resolveProvider(), apiKey)
+		ctx, stop := aiContext()
+		notes, err = aiClient.GenerateReleaseNotes(ctx, name, notes)
+		stop()
+		recordSpend(aiClient)
+		if err != nil {
+			return fmt.Errorf("failed to generate release notes: %w", err)
+		}
+	}
+
+	message := name + "\n\n" + strings.TrimSpace(notes)
+	fmt.Println()
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Println(message)
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Println()
+
+	if !tagYes && !confirm(fmt.Sprintf("Create tag %s with this message?", name), true) {
+		fmt.Println("❌ Aborted")
+		return nil
+	}
+
+	if err := g.CreateAnnotatedTag(name, message); err != nil {
+		return fmt.Errorf("failed to create tag: %w", err)
+	}
+	fmt.Printf("✅ Created tag %s\n", name)
+	fmt.Println("💡 Push it with: gh-assistant push --tags")
+	return nil
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/namin2/gh-assistant/internal/ai"
+	"github.com/namin2/gh-assistant/internal/commitmsg"
+	"github.com/namin2/gh-assistant/internal/git"
+	"github.com/spf13/viper"
+)
+
+// configuredTrailers returns the commit_trailers config as "Key: value" strings.
+// The config may be a list ("Refs: {jira_key}") or a map (refs: "{jira_key}").
+// Placeholders {branch} and {jira_key} are expanded; trailers whose placeholders
+// can't be resolved are skipped rather than committed half-empty.
+func configuredTrailers(branch, jiraKey string) []string {
+	var raw []string
+	switch v := viper.Get("commit_trailers").(type) {
+	case []interface{}:
+		for _, item := range v {
+			raw = append(raw, fmt.Sprint(item))
+		}
+	case map[string]interface{}:
+		// Viper lower-cases map keys, so restore git's conventional casing
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			raw = append(raw, fmt.Sprintf("%s: %v", strings.ToUpper(k[:1])+k[1:], v[k]))
+		}
+	}
+
+	var trailers []string
+	for _, t := range raw {
+		if strings.Contains(t, "{jira_key}") && jiraKey == "" {
+			continue
+		}
+		if strings.Contains(t, "{branch}") && branch == "" {
+			continue
+		}
+		t = strings.ReplaceAll(t, "{branch}", branch)
+		t = strings.ReplaceAll(t, "{jira_key}", jiraKey)
+		trailers = append(trailers, t)
+	}
+	return trailers
+}
+
+// applyCommitTrailers appends the configured trailers to the message.
+// issueKey is the Jira issue the commit is linked to (from --issue); when
+// empty, the key is taken from the branch name. With branch_ticket_mode set,
+// the branch's key is used as the commit scope ("scope") or added as a
+// "Refs:" trailer ("footer"). A linked issue always gets a "Refs:" trailer.
+func applyCommitTrailers(g *git.Git, message, issueKey string) (string, error) {
+	branch, _ := g.GetCurrentBranch()
+	jiraKey := issueKey
+	if jiraKey == "" {
+		jiraKey = newJiraClient().BranchIssueKey(branch)
+	}
+
+	trailers := configuredTrailers(branch, jiraKey)
+	if issueKey != "" {
+		trailers = appendUnique(trailers, "Refs: "+issueKey)
+	}
+
+	if jiraKey != "" {
+		switch mode := viper.GetString("branch_ticket_mode"); mode {
+		case "scope":
+			message = commitmsg.SetScope(message, jiraKey)
+		case "footer":
+			trailers = appendUnique(trailers, "Refs: "+jiraKey)
+		case "", "off":
+		default:
+			return "", fmt.Errorf("invalid branch_ticket_mode: %s (use 'scope', 'footer' or 'off')", mode)
+		}
+	}
+
+	if len(trailers) == 0 {
+		return message, nil
+	}
+	return g.AddTrailers(message, trailers)
+}
+
+// defaultPlainSummaryTrailer is the trailer key used for plain_summary
+const defaultPlainSummaryTrailer = "What-changed"
+
+// addPlainSummary appends a one-line plain-English explanation of the change
+// for non-technical readers as a trailer when plain_summary is enabled. The
+// key is plain_summary_trailer, with spaces turned into hyphens since git
+// trailer keys can't contain them. Failures only warn; the message is still
+// usable without it.
+func addPlainSummary(g *git.Git, aiClient *ai.Client, diff, message string, restore func(string) string) string {
+	if !viper.GetBool("plain_summary") || diff == "" {
+		return message
+	}
+
+	fmt.Println("🗣️  Explaining the change in plain English...")
+	ctx, stop := aiContext()
+	summary, err := aiClient.GeneratePlainSummary(ctx, diff)
+	stop()
+	recordSpend(aiClient)
+	if err != nil {
+		fmt.Printf("⚠️  Warning: Skipping the plain-English summary: %v\n", err)
+		return message
+	}
+
+	key := strings.Join(strings.Fields(strings.TrimSuffix(viper.GetString("plain_summary_trailer"), ":")), "-")
+	if key == "" {
+		key = defaultPlainSummaryTrailer
+	}
+	withSummary, err := g.AddTrailers(message, []string{key + ": " + restore(summary)})
+	if err != nil {
+		fmt.Printf("⚠️  Warning: Skipping the plain-English summary: %v\n", err)
+		return message
+	}
+	return withSummary
+}
+
+func appendUnique(list []string, item string) []string {
+	if containsString(list, item) {
+		return list
+	}
+	return append(list, item)
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/namin2/gh-assistant/internal/ai"
+)
+
+// sessionUsage totals the AI usage of the current run
+var sessionUsage struct {
+	models   []string
+	usage    ai.Usage
+	costUSD  float64
+	unpriced bool
+}
+
+// recordedUsage remembers how much of each client's usage has been counted,
+// so clients reused for regeneration aren't counted twice
+var recordedUsage = map[*ai.Client]clientUsage{}
+
+// clientUsage is a client's token usage and spend at some point
+type clientUsage struct {
+	usage ai.Usage
+	spend ai.Spend
+}
+
+// takeUsage adds the client's usage since the last call to the session totals
+// and returns its estimated cost; false means the model's pricing is unknown
+func takeUsage(client *ai.Client) (float64, bool) {
+	total := clientUsage{usage: client.Usage(), spend: client.Spend()}
+	seen := recordedUsage[client]
+	recordedUsage[client] = total
+	delta := ai.Usage{
+		InputTokens:  total.usage.InputTokens - seen.usage.InputTokens,
+		OutputTokens: total.usage.OutputTokens - seen.usage.OutputTokens,
+	}
+	if delta.InputTokens == 0 && delta.OutputTokens == 0 {
+		return 0, true
+	}
+
+	// Model tiers send requests to other models than the configured one
+	for _, model := range total.spend.Models {
+		if !containsString(sessionUsage.models, model) {
+			sessionUsage.models = append(sessionUsage.models, model)
+		}
+	}
+	sessionUsage.usage.InputTokens += delta.InputTokens
+	sessionUsage.usage.OutputTokens += delta.OutputTokens
+
+	cost := total.spend.CostUSD - seen.spend.CostUSD
+	known := total.spend.UnpricedCalls == seen.spend.UnpricedCalls
+	sessionUsage.costUSD += cost
+	if !known {
+		sessionUsage.unpriced = true
+	}
+	return cost, known
+}
+
+// usageLine formats the session usage as a single tab-separated line of
+// key=value fields for scripts; cost is "unknown" if any model is unpriced
+func usageLine(provider ai.Provider) string {
+	cost := "unknown"
+	if !sessionUsage.unpriced {
+		cost = fmt.Sprintf("%.6f", sessionUsage.costUSD)
+	}
+	fields := []string{
+		"usage",
+		"provider=" + string(provider),
+		"model=" + strings.Join(sessionUsage.models, ","),
+		fmt.Sprintf("input_tokens=%d", sessionUsage.usage.InputTokens),
+		fmt.Sprintf("output_tokens=%d", sessionUsage.usage.OutputTokens),
+		"cost_usd=" + cost,
+	}
+	return strings.Join(fields, "\t")
+}
+
+// silenceStdout sends everything printed to stdout to the null device and
+// returns the original stdout along with a function that restores it
+func silenceStdout() (io.Writer, func(), error) {
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open %s: %w", os.DevNull, err)
+	}
+	original := os.Stdout
+	os.Stdout = devNull
+	return original, func() {
+		os.Stdout = original
+		devNull.Close()
+	}, nil
+}
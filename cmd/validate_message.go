@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/namin2/gh-assistant/internal/ai"
+	"github.com/namin2/gh-assistant/internal/commitmsg"
+	"github.com/namin2/gh-assistant/internal/git"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var validateMessageCmd = &cobra.Command{
+	Use:   "validate-message <file|->",
+	Short: "Check a commit message against the configured rules",
+	Long: `Validates a commit message against the conventional commit rules configured
+with commit_types, commit_scopes, require_scope, max_subject_length and
+max_body_line_length. With commit_style gitmoji, the subject must start with
+an emoji or :shortcode: from the official gitmoji list (https://gitmoji.dev)
+or gitmoji_map. Lines starting with "#" are ignored, as git does.
+Exits non-zero and lists the problems if the message is invalid.
+
+Examples:
+  echo "feat(api): add login" | gh-assistant validate-message -
+  gh-assistant validate-message .git/COMMIT_EDITMSG   # e.g. from a commit-msg hook`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE:         runValidateMessage,
+}
+
+func init() {
+	rootCmd.AddCommand(validateMessageCmd)
+}
+
+func runValidateMessage(cmd *cobra.Command, args []string) error {
+	var data []byte
+	var err error
+	if args[0] == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(args[0])
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read message: %w", err)
+	}
+
+	message := stripCommentLines(string(data), git.New("").CommentChar())
+	problems := commitmsg.Validate(message, validationRules())
+	if len(problems) > 0 {
+		printValidationProblems(problems)
+		return fmt.Errorf("commit message is invalid")
+	}
+
+	fmt.Println("✅ Commit message is valid")
+	return nil
+}
+
+// validationRules builds the message rules from the configuration
+func validationRules() commitmsg.Rules {
+	return commitmsg.Rules{
+		Types:             viper.GetStringSlice("commit_types"),
+		Scopes:            viper.GetStringSlice("commit_scopes"),
+		RequireScope:      viper.GetBool("require_scope"),
+		MaxSubjectLength:  viper.GetInt("max_subject_length"),
+		MaxBodyLineLength: viper.GetInt("max_body_line_length"),
+		AllowEmoji:        ai.Style(viper.GetString("commit_style")) == ai.StyleGitmoji,
+		Gitmoji:           ai.Style(viper.GetString("commit_style")) == ai.StyleGitmoji,
+		ExtraGitmojis:     configuredGitmojis(),
+		Imperative:        viper.GetBool("imperative_mood"),
+		VerbForms:         verbForms(),
+		ForbiddenWords:    viper.GetStringSlice("forbidden_words"),
+		RequiredSections:  viper.GetStringSlice("required_sections"),
+	}
+}
+
+// configuredGitmojis returns the emoji set in gitmoji_map, which validation
+// accepts in addition to the official list
+func configuredGitmojis() []string {
+	var emoji []string
+	for _, e := range viper.GetStringMapString("gitmoji_map") {
+		emoji = append(emoji, e)
+	}
+	return emoji
+}
+
+// printValidationProblems lists the reasons a message failed validation
+func printValidationProblems(problems []string) {
+	fmt.Println("❌ Commit message does not follow the configured rules:")
+	for _, p := range problems {
+		fmt.Printf("   • %s\n", p)
+	}
+}
+
+// scissorsMarker marks where git's verbose commit template starts, after the
+// comment character; everything below it is ignored
+const scissorsMarker = " ------------------------ >8 ------------------------"
+
+// stripCommentLines drops the comment lines, starting with commentChar
+// (core.commentChar), that git adds to commit message files
+func stripCommentLines(message, commentChar string) string {
+	var lines []string
+	for _, line := range strings.Split(message, "\n") {
+		if line == commentChar+scissorsMarker {
+			break
+		}
+		if !strings.HasPrefix(line, commentChar) {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -2,13 +2,17 @@ package ai
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"sort"
 	"strings"
 	"time"
+
+	"github.com/namin2/gh-assistant/internal/commitmsg"
 )
 
 // Provider represents an AI provider
@@ -17,14 +21,112 @@ type Provider string
 const (
 	ProviderOpenAI    Provider = "openai"
 	ProviderAnthropic Provider = "anthropic"
+	ProviderOllama    Provider = "ollama"
+	ProviderGemini    Provider = "gemini"
+	ProviderAzure     Provider = "azure"
+	ProviderBedrock   Provider = "bedrock"
+	ProviderMistral   Provider = "mistral"
+	// ProviderOpenRouter routes "vendor/model" IDs to many vendors with
+	// a single key
+	ProviderOpenRouter Provider = "openrouter"
+	// ProviderMock writes template messages from the diff stats without a
+	// model or API key, for CI and tests
+	ProviderMock Provider = "mock"
 )
 
+// Providers lists the supported providers, including those added with
+// RegisterBackend
+var Providers = []Provider{ProviderOpenAI, ProviderAnthropic, ProviderOllama, ProviderGemini, ProviderAzure, ProviderBedrock, ProviderMistral, ProviderOpenRouter, ProviderMock}
+
+// Local reports whether the provider runs on the user's machine, needing no
+// API key and costing nothing
+func (p Provider) Local() bool {
+	return p == ProviderOllama || p == ProviderMock
+}
+
+// UsesAPIKey reports whether the provider authenticates with an API key;
+// Bedrock uses AWS credentials instead
+func (p Provider) UsesAPIKey() bool {
+	return !p.Local() && p != ProviderBedrock
+}
+
+// modelPrefixes maps well-known model name prefixes to their provider
+var modelPrefixes = map[string]Provider{
+	"gpt-":   ProviderOpenAI,
+	"o1":     ProviderOpenAI,
+	"o3":     ProviderOpenAI,
+	"claude": ProviderAnthropic,
+	"gemini": ProviderGemini,
+
+	"mistral-":     ProviderMistral,
+	"open-mistral": ProviderMistral,
+	"ministral":    ProviderMistral,
+	"codestral":    ProviderMistral,
+
+	// Bedrock model IDs name the vendor, after the region of a cross-region
+	// inference profile
+	"anthropic.":    ProviderBedrock,
+	"amazon.titan":  ProviderBedrock,
+	"us.anthropic.": ProviderBedrock,
+	"eu.anthropic.": ProviderBedrock,
+}
+
+// ProviderForModel guesses the provider serving a model from its name. The
+// second return value is false for unfamiliar names.
+func ProviderForModel(model string) (Provider, bool) {
+	return lookupByPrefix(modelPrefixes, model)
+}
+
+// DefaultOpenAIBaseURL is the OpenAI API, replaced by openai_base_url for
+// compatible servers
+const DefaultOpenAIBaseURL = "https://api.openai.com/v1"
+
+// defaultAnthropicVersion is the anthropic-version header sent when none is configured
+const defaultAnthropicVersion = "2023-06-01"
+
 // Client handles AI API interactions
 type Client struct {
-	provider   Provider
-	apiKey     string
-	model      string
-	httpClient *http.Client
+	provider         Provider
+	apiKey           string
+	model            string
+	anthropicVersion string
+	anthropicBeta    string
+	openAIBaseURL    string
+	openAIHeaders    map[string]string
+	ollamaURL        string
+	fallbacks        []string
+	azureEndpoint    string
+	azureDeployment  string
+	azureAPIVersion  string
+	awsRegion        string
+	aws              AWSCredentials
+	maxTokens        int
+	completionTokens int
+	temperature      *float64
+	topP             *float64
+	backend          Backend
+	checkCost        func(c *Client, estimate float64, known bool) error
+	tiers            []ModelTier
+	style            Style
+	gitmojiMap       map[string]string
+	enforceGitmoji   bool
+	truncation       TruncationStrategy
+	contextWindows   map[string]int
+	verbForms        map[string]string
+	limiter          *Limiter
+	bullet           string
+	forbiddenWords   []string
+	sections         []string
+	priorities       []string
+	promptTemplate   string
+	bulletBody       bool
+	language         string
+	bodyWidth        int
+	httpClient       *http.Client
+	usage            Usage
+	spend            Spend
+	// onText receives streamed text while StreamCommitMessage runs
+	onText func(text string)
 }
 
 // Config holds AI client configuration
@@ -32,8 +134,105 @@ type Config struct {
 	Provider Provider
 	APIKey   string
 	Model    string
+	// AnthropicVersion overrides the anthropic-version header
+	AnthropicVersion string
+	// AnthropicBeta sets the anthropic-beta header (comma-separated feature names)
+	AnthropicBeta string
+	// OpenAIBaseURL points the OpenAI provider at a server speaking the
+	// Chat Completions protocol, e.g. LiteLLM, vLLM or LM Studio
+	// (default https://api.openai.com/v1)
+	OpenAIBaseURL string
+	// OpenAIHeaders are extra headers sent with OpenAI and Azure OpenAI
+	// requests, e.g. for an internal gateway
+	OpenAIHeaders map[string]string
+	// OpenRouterFallbacks are models OpenRouter tries in order when the
+	// request's model is unavailable
+	OpenRouterFallbacks []string
+	// OllamaHost is the Ollama server's address (default
+	// http://localhost:11434); a host without a scheme uses http and
+	// port 11434 unless given
+	OllamaHost string
+	// AzureEndpoint is the Azure OpenAI resource endpoint, e.g.
+	// https://my-resource.openai.azure.com
+	AzureEndpoint string
+	// AzureDeployment is the deployment requests are routed to (default
+	// the model name)
+	AzureDeployment string
+	// AzureAPIVersion is the api-version query parameter (default 2024-06-01)
+	AzureAPIVersion string
+	// AWSRegion is the Bedrock region (default us-east-1)
+	AWSRegion string
+	// AWS are the credentials signing Bedrock requests
+	AWS AWSCredentials
+	// MaxTokens overrides the computed completion budget when non-zero
+	MaxTokens int
+	// Temperature, when set, is the sampling temperature of every request;
+	// lower values make messages more deterministic. Unset leaves the
+	// provider's default.
+	Temperature *float64
+	// TopP, when set, is the nucleus sampling probability of every request
+	TopP *float64
+	// MaxAttempts bounds how many times a request failing with a rate limit,
+	// server error or network error is sent (default 3); 1 disables retries
+	MaxAttempts int
+	// OnRetry, when set, is called before a failed request is retried
+	OnRetry RetryFunc
+	// CheckCost is called before each request with the client making it and
+	// the request's estimated USD cost (known is false when the model's
+	// pricing is unknown). Returning an error cancels the request.
+	CheckCost func(c *Client, estimate float64, known bool) error
+	// ModelTiers choose the model by diff size, overriding Model for
+	// diffs that fit a tier
+	ModelTiers []ModelTier
+	// Style selects the commit message convention (default conventional)
+	Style Style
+	// GitmojiMap maps commit types to emoji for the gitmoji style
+	GitmojiMap map[string]string
+	// EnforceGitmoji rewrites the generated emoji to match GitmojiMap
+	EnforceGitmoji bool
+	// Truncation chooses what to keep of diffs too long for the prompt
+	// (default head)
+	Truncation TruncationStrategy
+	// ContextWindows maps model name prefixes to context window sizes in
+	// tokens, adding to or overriding the built-in table
+	ContextWindows map[string]int
+	// VerbForms, when set, rewrites a non-imperative first word of the
+	// description ("added") to its imperative form ("add")
+	VerbForms map[string]string
+	// Limiter, when set, bounds concurrent requests across the clients
+	// sharing it
+	Limiter *Limiter
+	// Bullet is the list marker used in message bodies, "-" (default) or "*"
+	Bullet string
+	// ForbiddenWords are words the model is told never to use
+	ForbiddenWords []string
+	// RequiredSections are body sections every message must have, each
+	// started by a "<name>:" line
+	RequiredSections []string
+	// PriorityExtensions are file extensions ("go", "ts") whose diffs the
+	// smart truncation strategy keeps first, in order
+	PriorityExtensions []string
+	// PromptTemplate is the path of a text/template file replacing the
+	// built-in commit message prompt, executed with PromptData
+	PromptTemplate string
+	// BulletBody asks for a body listing the main changes as bullets under
+	// the subject, wrapped at BodyWidth (default 72) columns
+	BulletBody bool
+	BodyWidth  int
+	// Language is the language generated messages and pull request
+	// descriptions are written in, e.g. "Japanese" or "pt-BR"; English when
+	// empty
+	Language string
 }
 
+// Style is a commit message convention
+type Style string
+
+const (
+	StyleConventional Style = "conventional"
+	StyleGitmoji      Style = "gitmoji"
+)
+
 // New creates a new AI client
 func New(cfg Config) *Client {
 	if cfg.Model == "" {
@@ -42,51 +241,285 @@ func New(cfg Config) *Client {
 			cfg.Model = "gpt-4o-mini"
 		case ProviderAnthropic:
 			cfg.Model = "claude-3-5-sonnet-20241022"
+		case ProviderOllama:
+			cfg.Model = "llama3.2"
+		case ProviderGemini:
+			cfg.Model = "gemini-1.5-flash"
+		case ProviderAzure:
+			// Deployments are usually named after their model, which
+			// prices the requests
+			cfg.Model = cfg.AzureDeployment
+		case ProviderBedrock:
+			cfg.Model = "anthropic.claude-3-5-sonnet-20240620-v1:0"
+		case ProviderMistral:
+			cfg.Model = "mistral-small-latest"
+		case ProviderOpenRouter:
+			cfg.Model = "openai/gpt-4o-mini"
+		case ProviderMock:
+			cfg.Model = mockModel
 		}
 	}
 
-	return &Client{
-		provider: cfg.Provider,
-		apiKey:   cfg.APIKey,
-		model:    cfg.Model,
+	if cfg.AnthropicVersion == "" {
+		cfg.AnthropicVersion = defaultAnthropicVersion
+	}
+	if cfg.OpenAIBaseURL == "" {
+		cfg.OpenAIBaseURL = DefaultOpenAIBaseURL
+	}
+	if cfg.AzureAPIVersion == "" {
+		cfg.AzureAPIVersion = defaultAzureAPIVersion
+	}
+	if cfg.AWSRegion == "" {
+		cfg.AWSRegion = defaultBedrockRegion
+	}
+	if cfg.Truncation == "" {
+		cfg.Truncation = TruncateHead
+	}
+	if cfg.Style == "" {
+		cfg.Style = StyleConventional
+	}
+	if cfg.Bullet == "" {
+		cfg.Bullet = "-"
+	}
+	if cfg.BodyWidth < 1 {
+		cfg.BodyWidth = commitmsg.DefaultBodyWidth
+	}
+	if cfg.MaxAttempts < 1 {
+		cfg.MaxAttempts = DefaultMaxAttempts
+	}
+	if cfg.Style == StyleGitmoji && len(cfg.GitmojiMap) == 0 {
+		cfg.GitmojiMap = commitmsg.DefaultGitmojiMap
+	}
+
+	c := &Client{
+		provider:         cfg.Provider,
+		apiKey:           cfg.APIKey,
+		model:            cfg.Model,
+		anthropicVersion: cfg.AnthropicVersion,
+		anthropicBeta:    cfg.AnthropicBeta,
+		openAIBaseURL:    strings.TrimRight(cfg.OpenAIBaseURL, "/"),
+		openAIHeaders:    cfg.OpenAIHeaders,
+		fallbacks:        cfg.OpenRouterFallbacks,
+		ollamaURL:        OllamaURL(cfg.OllamaHost),
+		azureEndpoint:    strings.TrimRight(cfg.AzureEndpoint, "/"),
+		azureDeployment:  cfg.AzureDeployment,
+		azureAPIVersion:  cfg.AzureAPIVersion,
+		awsRegion:        cfg.AWSRegion,
+		aws:              cfg.AWS,
+		maxTokens:        cfg.MaxTokens,
+		temperature:      cfg.Temperature,
+		topP:             cfg.TopP,
+		checkCost:        cfg.CheckCost,
+		tiers:            sortTiers(cfg.ModelTiers),
+		style:            cfg.Style,
+		gitmojiMap:       cfg.GitmojiMap,
+		enforceGitmoji:   cfg.EnforceGitmoji,
+		truncation:       cfg.Truncation,
+		contextWindows:   cfg.ContextWindows,
+		verbForms:        cfg.VerbForms,
+		limiter:          cfg.Limiter,
+		bullet:           cfg.Bullet,
+		forbiddenWords:   cfg.ForbiddenWords,
+		sections:         cfg.RequiredSections,
+		priorities:       cfg.PriorityExtensions,
+		promptTemplate:   cfg.PromptTemplate,
+		bulletBody:       cfg.BulletBody,
+		bodyWidth:        cfg.BodyWidth,
+		language:         cfg.Language,
 		httpClient: &http.Client{
 			Timeout: 60 * time.Second,
+			Transport: &retryTransport{
+				base:        http.DefaultTransport,
+				maxAttempts: cfg.MaxAttempts,
+				onRetry:     cfg.OnRetry,
+			},
 		},
 	}
+	c.backend = newBackend(c)
+	return c
 }
 
-// GenerateCommitMessage generates a commit message from a git diff
-func (c *Client) GenerateCommitMessage(diff string, changedFiles []string) (string, error) {
-	if diff == "" {
+// CommitRequest describes the change to write a commit message for
+type CommitRequest struct {
+	Diff  string
+	Files []string
+	// Notes are facts about the change that the diff doesn't show well,
+	// e.g. "submodule lib updated to 1a2b3c4d5e6f"
+	Notes []string
+	// Breaking asks for a breaking change header and a BREAKING CHANGE
+	// footer with migration notes
+	Breaking bool
+	// BreakingChanges describe detected API breaks, e.g. "removed exported func Foo"
+	BreakingChanges []string
+	// Subject, when set, is used as the subject line as is; only the body
+	// is generated
+	Subject string
+	// Branch is the branch being committed to, for prompt templates
+	Branch string
+}
+
+// GenerateCommitMessage generates a commit message from a git diff.
+// Canceling ctx stops the request in flight.
+func (c *Client) GenerateCommitMessage(ctx context.Context, req CommitRequest) (string, error) {
+	if req.Diff == "" && len(req.Notes) == 0 {
 		return "", errors.New("no diff provided")
 	}
 
-	prompt := buildCommitPrompt(diff, changedFiles)
+	if req.Subject != "" {
+		return c.generateBody(ctx, req)
+	}
 
-	switch c.provider {
-	case ProviderOpenAI:
-		return c.callOpenAI(prompt)
-	case ProviderAnthropic:
-		return c.callAnthropic(prompt)
-	default:
+	ctx = c.withModelFor(ctx, req)
+	prompt, err := c.buildCommitPrompt(req)
+	if err != nil {
+		return "", err
+	}
+	// Messages may have a body of several paragraphs, and a breaking change
+	// footer with migration notes
+	message, err := c.generate(ctx, prompt, c.completionBudget(true, 1))
+	if err != nil {
+		return "", err
+	}
+	return c.postProcess(req, message), nil
+}
+
+// postProcess applies style rules to a generated message
+func (c *Client) postProcess(req CommitRequest, message string) string {
+	message = commitmsg.Format(message, c.bullet)
+	message = commitmsg.NormalizeEmptyScope(message)
+	if c.style == StyleGitmoji && (c.enforceGitmoji || !c.hasGitmoji(message)) {
+		message = commitmsg.ApplyGitmoji(message, c.gitmojiMap)
+	}
+	if c.verbForms != nil {
+		message = commitmsg.FixImperative(message, c.verbForms)
+	}
+	if req.Breaking {
+		message = commitmsg.MarkBreaking(message)
+	}
+	if c.bulletBody {
+		message = commitmsg.WrapBody(message, c.bodyWidth)
+	}
+	return message
+}
+
+// generate completes a prompt, retrying once with a clarifying instruction
+// if the model refuses
+func (c *Client) generate(ctx context.Context, prompt string, maxTokens int) (string, error) {
+	message, err := c.complete(ctx, prompt, maxTokens)
+	if err == nil && !IsRefusal(message) {
+		return message, nil
+	}
+	if err != nil && !errors.Is(err, ErrRefusal) {
+		return "", err
+	}
+
+	// The model refused; retry once with a clarifying instruction
+	message, err = c.complete(ctx, prompt+clarifyingInstruction, maxTokens)
+	if err != nil {
+		return "", err
+	}
+	if IsRefusal(message) {
+		return "", fmt.Errorf("%w: %s", ErrRefusal, message)
+	}
+	return message, nil
+}
+
+// complete sends a prompt to the configured provider and returns the response text.
+// maxTokens bounds the completion for providers that require a limit.
+func (c *Client) complete(ctx context.Context, prompt string, maxTokens int) (string, error) {
+	model := c.RequestModel(ctx)
+	if c.checkCost != nil {
+		estimate, known := c.estimateCost(model, Usage{
+			InputTokens:  estimateTokens(prompt),
+			OutputTokens: maxTokens,
+		})
+		if err := c.checkCost(c, estimate, known); err != nil {
+			return "", err
+		}
+	}
+
+	if err := c.limiter.acquire(ctx); err != nil {
+		return "", err
+	}
+	defer c.limiter.release()
+
+	if c.backend == nil {
 		return "", fmt.Errorf("unsupported provider: %s", c.provider)
 	}
+	c.completionTokens = maxTokens
+	before := c.usage
+	text, err := c.backend.Generate(ctx, prompt)
+	c.addSpend(model, before)
+	return text, err
+}
+
+// Validate checks that the API key is accepted by the provider.
+// It performs a lightweight authenticated request that does not consume tokens.
+func (c *Client) Validate(ctx context.Context) error {
+	if lister, ok := c.backend.(ModelLister); ok {
+		_, err := lister.ListModels(ctx)
+		return err
+	}
+
+	req, err := c.newModelsRequest(ctx)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API key rejected (status %d): %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return nil
 }
 
-func buildCommitPrompt(diff string, changedFiles []string) string {
-	// Truncate diff if too long
-	maxDiffLen := 12000
-	truncatedDiff := diff
-	if len(diff) > maxDiffLen {
-		truncatedDiff = diff[:maxDiffLen] + "\n... [diff truncated]"
+// singleMessageResponse asks the model for just the commit message
+const singleMessageResponse = `6. Do NOT include any explanation, just the commit message
+7. Do NOT wrap in quotes or code blocks
+
+Respond with ONLY the commit message, nothing else.`
+
+// buildCommitPrompt builds the prompt for a single commit message, from the
+// configured template when there is one
+func (c *Client) buildCommitPrompt(req CommitRequest) (string, error) {
+	if c.promptTemplate != "" {
+		return c.renderPromptTemplate(req)
 	}
+	return c.buildPrompt(req, singleMessageResponse), nil
+}
+
+// buildPrompt builds the commit prompt with the given response format instructions
+func (c *Client) buildPrompt(req CommitRequest, responseFormat string) string {
+	model, _ := c.ModelFor(req)
+	truncatedDiff := c.truncateDiffFor(model, req.Diff)
 
 	filesContext := ""
-	if len(changedFiles) > 0 {
-		filesContext = fmt.Sprintf("\nChanged files:\n- %s\n", strings.Join(changedFiles, "\n- "))
+	if len(req.Files) > 0 {
+		filesContext = fmt.Sprintf("\nChanged files:\n- %s\n", strings.Join(req.Files, "\n- "))
+	}
+	if len(req.Notes) > 0 {
+		filesContext += fmt.Sprintf("\nAdditional context:\n- %s\n", strings.Join(req.Notes, "\n- "))
+	}
+
+	if req.Breaking {
+		filesContext += breakingInstructions(req.BreakingChanges)
+	}
+
+	styleName := "conventional commits"
+	format := "Use conventional commits format: type(scope): description"
+	if c.style == StyleGitmoji {
+		styleName = "gitmoji"
+		format = "Use gitmoji format: <emoji> type(scope): description, choosing the emoji by type: " + gitmojiLegend(c.gitmojiMap)
 	}
 
-	return fmt.Sprintf(`You are an expert at writing clear, concise git commit messages following conventional commits format.
+	return fmt.Sprintf(`You are an expert at writing clear, concise git commit messages following %s format.
 
 Analyze the following git diff and generate a meaningful commit message.
 %s
@@ -94,21 +527,121 @@ Git Diff:
 %s
 
 Rules for the commit message:
-1. Use conventional commits format: type(scope): description
+1. %s
 2. Types: feat, fix, docs, style, refactor, perf, test, build, ci, chore
 3. Keep the first line under 72 characters
 4. Be specific about what changed and why
 5. If there are multiple unrelated changes, focus on the main one
-6. Do NOT include any explanation, just the commit message
-7. Do NOT wrap in quotes or code blocks
+%s%s%s%s%s`, styleName, filesContext, truncatedDiff, format, c.bodyRule(), c.languageRule(true), c.forbiddenRule(), c.sectionsRule(), responseFormat)
+}
+
+// bodyRule asks for a bulleted body under the subject, if enabled
+func (c *Client) bodyRule() string {
+	if !c.bulletBody {
+		return ""
+	}
+	return fmt.Sprintf("- After the first line, add a blank line and a body listing the main changes as bullet points starting with \"%s \", one change per bullet, wrapped at %d characters\n", c.bullet, c.bodyWidth)
+}
+
+// languageRule asks for text in the configured language, if any. In commit
+// messages the type, scope and footer tokens stay in English, since tools
+// parse them.
+func (c *Client) languageRule(commit bool) string {
+	if c.language == "" {
+		return ""
+	}
+	if commit {
+		return fmt.Sprintf("- Write the description and body in %s; keep the type, scope and footer tokens such as BREAKING CHANGE in English\n", c.language)
+	}
+	return fmt.Sprintf("- Write in %s\n", c.language)
+}
+
+// forbiddenRule tells the model which words to avoid, if any
+func (c *Client) forbiddenRule() string {
+	if len(c.forbiddenWords) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("- Never use these words or names, in any case: %s\n", strings.Join(c.forbiddenWords, ", "))
+}
+
+// sectionsRule asks for the required body sections, if any
+func (c *Client) sectionsRule() string {
+	var names []string
+	for _, section := range c.sections {
+		if name := commitmsg.SectionName(section); name != "" {
+			names = append(names, name+":")
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("- The body must contain these sections, each starting on its own line with the name and colon, in this order: %s\n", strings.Join(names, " "))
+}
+
+// breakingInstructions asks for a breaking change header and migration footer,
+// listing any detected API breaks
+func breakingInstructions(changes []string) string {
+	text := "\nThis is a BREAKING CHANGE"
+	if len(changes) > 0 {
+		text += fmt.Sprintf(":\n- %s\n", strings.Join(changes, "\n- "))
+	} else {
+		text += ".\n"
+	}
+	return text + `Mark the header with "!" after the type/scope (e.g. feat(api)!: ...), then add a blank line and a footer
+"BREAKING CHANGE: <what changed and how users should migrate>".
+`
+}
+
+// hasGitmoji reports whether a message starts with an official gitmoji or
+// one of the configured ones; others are replaced by the type's emoji
+func (c *Client) hasGitmoji(message string) bool {
+	subject, _ := commitmsg.Split(message)
+	extra := make([]string, 0, len(c.gitmojiMap))
+	for _, emoji := range c.gitmojiMap {
+		extra = append(extra, emoji)
+	}
+	return commitmsg.IsGitmoji(commitmsg.LeadingGitmoji(subject), extra...)
+}
+
+// gitmojiLegend formats the type→emoji mapping for the prompt
+func gitmojiLegend(gitmojiMap map[string]string) string {
+	types := make([]string, 0, len(gitmojiMap))
+	for t := range gitmojiMap {
+		types = append(types, t)
+	}
+	sort.Strings(types)
 
-Respond with ONLY the commit message, nothing else.`, filesContext, truncatedDiff)
+	pairs := make([]string, 0, len(types))
+	for _, t := range types {
+		pairs = append(pairs, gitmojiMap[t]+" "+t)
+	}
+	return strings.Join(pairs, ", ")
+}
+
+// maxDiffLen is the longest diff sent to a model whose context window is unknown
+const maxDiffLen = 12000
+
+// truncateDiff shortens a diff that is too long for the model's context
+// window using the configured truncation strategy
+func (c *Client) truncateDiff(diff string) string {
+	return c.truncateDiffFor(c.model, diff)
+}
+
+// truncateDiffFor shortens a diff to fit the context window of a model
+func (c *Client) truncateDiffFor(model, diff string) string {
+	return TruncateDiff(diff, c.truncation, c.diffBudget(model), c.priorities...)
 }
 
 // OpenAI API types
 type openAIRequest struct {
 	Model    string          `json:"model"`
 	Messages []openAIMessage `json:"messages"`
+	// Models are OpenRouter's fallbacks, tried after Model
+	Models        []string             `json:"models,omitempty"`
+	Stream        bool                 `json:"stream,omitempty"`
+	StreamOptions *openAIStreamOptions `json:"stream_options,omitempty"`
+	Temperature   *float64             `json:"temperature,omitempty"`
+	TopP          *float64             `json:"top_p,omitempty"`
 }
 
 type openAIMessage struct {
@@ -120,19 +653,53 @@ type openAIResponse struct {
 	Choices []struct {
 		Message struct {
 			Content string `json:"content"`
+			Refusal string `json:"refusal"`
 		} `json:"message"`
 	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
 	Error *struct {
-		Message string `json:"message"`
+		Message string    `json:"message"`
+		Code    errorCode `json:"code"`
 	} `json:"error"`
+	// Mistral reports errors at the top level
+	Message json.RawMessage `json:"message"`
+	Type    string          `json:"type"`
 }
 
-func (c *Client) callOpenAI(prompt string) (string, error) {
+// errorCode is an error code, a string for OpenAI and a number for OpenRouter
+type errorCode string
+
+func (e *errorCode) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		s = string(data)
+	}
+	*e = errorCode(s)
+	return nil
+}
+
+func (c *Client) callOpenAI(ctx context.Context, prompt string) (string, error) {
+	model := c.RequestModel(ctx)
 	reqBody := openAIRequest{
-		Model: c.model,
+		Model: model,
 		Messages: []openAIMessage{
 			{Role: "user", Content: prompt},
 		},
+		Temperature: c.temperature,
+		TopP:        c.topP,
+	}
+	if c.provider == ProviderOpenRouter && len(c.fallbacks) > 0 {
+		reqBody.Models = append([]string{model}, c.fallbacks...)
+	}
+	if c.streaming() {
+		reqBody.Stream = true
+		// The other providers include usage in the last chunk unasked
+		if c.provider == ProviderOpenAI {
+			reqBody.StreamOptions = &openAIStreamOptions{IncludeUsage: true}
+		}
 	}
 
 	jsonBody, err := json.Marshal(reqBody)
@@ -140,13 +707,13 @@ func (c *Client) callOpenAI(prompt string) (string, error) {
 		return "", err
 	}
 
-	req, err := http.NewRequest("POST", "https://api.openai.com/v1/chat/completions", bytes.NewBuffer(jsonBody))
+	req, err := c.newChatRequest(ctx, jsonBody)
 	if err != nil {
 		return "", err
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	c.setOpenAIHeaders(req)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -154,6 +721,10 @@ func (c *Client) callOpenAI(prompt string) (string, error) {
 	}
 	defer resp.Body.Close()
 
+	if c.streaming() && isEventStream(resp) {
+		return c.readOpenAIStream(resp.Body)
+	}
+
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return "", err
@@ -164,22 +735,117 @@ func (c *Client) callOpenAI(prompt string) (string, error) {
 		return "", fmt.Errorf("failed to parse response: %w", err)
 	}
 
+	c.usage.add(result.Usage.PromptTokens, result.Usage.CompletionTokens)
+
 	if result.Error != nil {
+		switch result.Error.Code {
+		case "model_not_found":
+			return "", c.modelNotFound(ctx)
+		case "DeploymentNotFound":
+			return "", c.azureDeploymentNotFound(ctx, result.Error.Message)
+		case "content_filter":
+			// Azure's content filter rejected the prompt or completion
+			return "", fmt.Errorf("%w: %s", ErrRefusal, result.Error.Message)
+		}
+		if c.provider == ProviderOpenRouter && strings.Contains(result.Error.Message, "not a valid model") {
+			return "", c.modelNotFound(ctx)
+		}
 		return "", fmt.Errorf("API error: %s", result.Error.Message)
 	}
+	if resp.StatusCode >= 400 && len(result.Message) > 0 {
+		return "", c.mistralError(ctx, result.Type, result.Message)
+	}
 
 	if len(result.Choices) == 0 {
 		return "", errors.New("no response from API")
 	}
 
+	if refusal := result.Choices[0].Message.Refusal; refusal != "" {
+		return "", fmt.Errorf("%w: %s", ErrRefusal, refusal)
+	}
+
 	return strings.TrimSpace(result.Choices[0].Message.Content), nil
 }
 
+// newChatRequest builds the chat completions request for OpenAI, a
+// compatible server or Mistral, or for the deployment when using Azure OpenAI
+func (c *Client) newChatRequest(ctx context.Context, body []byte) (*http.Request, error) {
+	if c.provider == ProviderAzure {
+		return c.newAzureChatRequest(ctx, body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.chatBaseURL()+"/chat/completions", bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+	c.setOpenAIAuth(req)
+	if c.provider == ProviderOpenRouter {
+		setOpenRouterHeaders(req)
+	}
+	return req, nil
+}
+
+// chatBaseURL returns the base URL of the Chat Completions API in use
+func (c *Client) chatBaseURL() string {
+	switch c.provider {
+	case ProviderMistral:
+		return mistralBaseURL
+	case ProviderOpenRouter:
+		return openRouterBaseURL
+	}
+	return c.openAIBaseURL
+}
+
+// setOpenAIAuth sets the bearer token, which local compatible servers may
+// not need
+func (c *Client) setOpenAIAuth(req *http.Request) {
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+}
+
+// setOpenAIHeaders adds the configured extra headers, which are meant for
+// the OpenAI endpoint or gateway and aren't sent to other vendors
+func (c *Client) setOpenAIHeaders(req *http.Request) {
+	if c.provider != ProviderOpenAI && c.provider != ProviderAzure {
+		return
+	}
+	for name, value := range c.openAIHeaders {
+		req.Header.Set(name, value)
+	}
+}
+
+// Completion budgets in tokens. A single-line subject needs very little; a
+// detailed message with a body needs room for several wrapped paragraphs.
+const (
+	singleLineMaxTokens = 256
+	detailedMaxTokens   = 1024
+)
+
+// completionBudget returns the max_tokens for a request producing the given
+// number of messages, honoring the configured override
+func (c *Client) completionBudget(detailed bool, messages int) int {
+	if c.maxTokens > 0 {
+		return c.maxTokens
+	}
+	if messages < 1 {
+		messages = 1
+	}
+	if detailed {
+		return detailedMaxTokens * messages
+	}
+	return singleLineMaxTokens * messages
+}
+
 // Anthropic API types
 type anthropicRequest struct {
 	Model     string             `json:"model"`
 	MaxTokens int                `json:"max_tokens"`
 	Messages  []anthropicMessage `json:"messages"`
+	Stream    bool               `json:"stream,omitempty"`
+	// Sampling parameters, sent only when configured
+	Temperature *float64 `json:"temperature,omitempty"`
+	TopP        *float64 `json:"top_p,omitempty"`
 }
 
 type anthropicMessage struct {
@@ -191,18 +857,27 @@ type anthropicResponse struct {
 	Content []struct {
 		Text string `json:"text"`
 	} `json:"content"`
+	StopReason string `json:"stop_reason"`
+	Usage      struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
 	Error *struct {
+		Type    string `json:"type"`
 		Message string `json:"message"`
 	} `json:"error"`
 }
 
-func (c *Client) callAnthropic(prompt string) (string, error) {
+func (c *Client) callAnthropic(ctx context.Context, prompt string) (string, error) {
 	reqBody := anthropicRequest{
-		Model:     c.model,
-		MaxTokens: 256,
+		Model:     c.RequestModel(ctx),
+		MaxTokens: c.completionTokens,
 		Messages: []anthropicMessage{
 			{Role: "user", Content: prompt},
 		},
+		Stream:      c.streaming(),
+		Temperature: c.temperature,
+		TopP:        c.topP,
 	}
 
 	jsonBody, err := json.Marshal(reqBody)
@@ -210,14 +885,13 @@ func (c *Client) callAnthropic(prompt string) (string, error) {
 		return "", err
 	}
 
-	req, err := http.NewRequest("POST", "https://api.anthropic.com/v1/messages", bytes.NewBuffer(jsonBody))
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.anthropic.com/v1/messages", bytes.NewBuffer(jsonBody))
 	if err != nil {
 		return "", err
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("x-api-key", c.apiKey)
-	req.Header.Set("anthropic-version", "2023-06-01")
+	c.setAnthropicHeaders(req)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -225,6 +899,10 @@ func (c *Client) callAnthropic(prompt string) (string, error) {
 	}
 	defer resp.Body.Close()
 
+	if c.streaming() && isEventStream(resp) {
+		return c.readAnthropicStream(resp.Body)
+	}
+
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return "", err
@@ -235,10 +913,22 @@ func (c *Client) callAnthropic(prompt string) (string, error) {
 		return "", fmt.Errorf("failed to parse response: %w", err)
 	}
 
+	c.usage.add(result.Usage.InputTokens, result.Usage.OutputTokens)
+
 	if result.Error != nil {
+		// Anthropic reports unknown models as a 404 not_found_error
+		// whose message names the model
+		if resp.StatusCode == http.StatusNotFound && result.Error.Type == "not_found_error" &&
+			strings.Contains(result.Error.Message, "model") {
+			return "", c.modelNotFound(ctx)
+		}
 		return "", fmt.Errorf("API error: %s", result.Error.Message)
 	}
 
+	if result.StopReason == "refusal" {
+		return "", ErrRefusal
+	}
+
 	if len(result.Content) == 0 {
 		return "", errors.New("no response from API")
 	}
@@ -246,3 +936,12 @@ func (c *Client) callAnthropic(prompt string) (string, error) {
 	return strings.TrimSpace(result.Content[0].Text), nil
 }
 
+// setAnthropicHeaders sets the authentication and versioning headers for Anthropic requests
+func (c *Client) setAnthropicHeaders(req *http.Request) {
+	req.Header.Set("x-api-key", c.apiKey)
+	req.Header.Set("anthropic-version", c.anthropicVersion)
+	if c.anthropicBeta != "" {
+		req.Header.Set("anthropic-beta", c.anthropicBeta)
+	}
+}
+
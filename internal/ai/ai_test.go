@@ -0,0 +1,39 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// testProvider is served by the generate function given to newTestClient
+const testProvider Provider = "test"
+
+// testGenerate answers the prompts of the next client newTestClient creates
+var testGenerate func(ctx context.Context, c *Client, prompt string) (string, error)
+
+func init() {
+	RegisterBackend(testProvider, func(c *Client) Backend {
+		generate := testGenerate
+		return BackendFunc(func(ctx context.Context, prompt string) (string, error) {
+			return generate(ctx, c, prompt)
+		})
+	})
+}
+
+// newTestClient returns a client whose requests are answered by generate
+func newTestClient(cfg Config, generate func(ctx context.Context, c *Client, prompt string) (string, error)) *Client {
+	testGenerate = generate
+	cfg.Provider = testProvider
+	return New(cfg)
+}
+
+// testDiff returns a diff of one file adding n lines
+func testDiff(n int) string {
+	var b strings.Builder
+	b.WriteString("diff --git a/main.go b/main.go\n--- a/main.go\n+++ b/main.go\n@@ -1,0 +1,1 @@\n")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "+line %d\n", i)
+	}
+	return b.String()
+}
@@ -0,0 +1,58 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// defaultAzureAPIVersion is the api-version sent when none is configured
+const defaultAzureAPIVersion = "2024-06-01"
+
+// azureURL builds the URL of an Azure OpenAI resource path, e.g.
+// "/openai/models", with the api-version query parameter
+func (c *Client) azureURL(path string) (string, error) {
+	if c.azureEndpoint == "" {
+		return "", errors.New("azure_endpoint is not configured (e.g. https://my-resource.openai.azure.com)")
+	}
+	return fmt.Sprintf("%s%s?api-version=%s", c.azureEndpoint, path, url.QueryEscape(c.azureAPIVersion)), nil
+}
+
+// azureDeploymentName returns the deployment requests made with ctx are
+// routed to, defaulting to the model name
+func (c *Client) azureDeploymentName(ctx context.Context) string {
+	if c.azureDeployment != "" {
+		return c.azureDeployment
+	}
+	return c.RequestModel(ctx)
+}
+
+// newAzureChatRequest builds a chat completions request for the configured
+// deployment. The body is the OpenAI chat completions request.
+func (c *Client) newAzureChatRequest(ctx context.Context, body []byte) (*http.Request, error) {
+	deployment := c.azureDeploymentName(ctx)
+	if deployment == "" {
+		return nil, errors.New("azure_deployment is not configured")
+	}
+	endpoint, err := c.azureURL("/openai/deployments/" + url.PathEscape(deployment) + "/chat/completions")
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("api-key", c.apiKey)
+	return req, nil
+}
+
+// azureDeploymentNotFound explains a request to a deployment the resource
+// doesn't have; unlike models, deployments can't be listed with the API key
+func (c *Client) azureDeploymentNotFound(ctx context.Context, message string) error {
+	return fmt.Errorf("deployment %q was not found at %s: %s\n  Set the deployment with azure_deployment in the config file",
+		c.azureDeploymentName(ctx), c.azureEndpoint, message)
+}
@@ -0,0 +1,111 @@
+package ai
+
+import (
+	"context"
+	"sync"
+)
+
+// Backend sends prompts to a provider's API. Provider names a backend in
+// the configuration; the backend does the talking.
+type Backend interface {
+	// Generate returns the model's completion of prompt
+	Generate(ctx context.Context, prompt string) (string, error)
+}
+
+// BackendFunc adapts a function to the Backend interface
+type BackendFunc func(ctx context.Context, prompt string) (string, error)
+
+// Generate calls f
+func (f BackendFunc) Generate(ctx context.Context, prompt string) (string, error) {
+	return f(ctx, prompt)
+}
+
+// ModelLister is implemented by backends that can list their models, for
+// model suggestions and Validate
+type ModelLister interface {
+	ListModels(ctx context.Context) ([]string, error)
+}
+
+// BackendFactory builds a provider's backend for a client. The backend reads
+// each request's settings from the client (RequestModel, APIKey,
+// CompletionTokens, Temperature, TopP) and reports the tokens it used with
+// AddUsage.
+type BackendFactory func(c *Client) Backend
+
+var (
+	backendsMu sync.RWMutex
+	backends   = map[Provider]BackendFactory{
+		ProviderOpenAI:     chatCompletionsBackend,
+		ProviderAzure:      chatCompletionsBackend,
+		ProviderMistral:    chatCompletionsBackend,
+		ProviderOpenRouter: chatCompletionsBackend,
+		ProviderAnthropic:  func(c *Client) Backend { return BackendFunc(c.callAnthropic) },
+		ProviderOllama:     func(c *Client) Backend { return BackendFunc(c.callOllama) },
+		ProviderGemini:     func(c *Client) Backend { return BackendFunc(c.callGemini) },
+		ProviderBedrock:    func(c *Client) Backend { return BackendFunc(c.callBedrock) },
+		ProviderMock:       func(c *Client) Backend { return mockBackend{} },
+	}
+)
+
+// chatCompletionsBackend serves the providers speaking OpenAI's Chat
+// Completions protocol
+func chatCompletionsBackend(c *Client) Backend {
+	return BackendFunc(c.callOpenAI)
+}
+
+// RegisterBackend makes a provider available under its name, adding it to
+// Providers or replacing the built-in backend of that name. It's meant to
+// be called from an init function, before any client is created.
+func RegisterBackend(provider Provider, factory BackendFactory) {
+	backendsMu.Lock()
+	defer backendsMu.Unlock()
+
+	if _, ok := backends[provider]; !ok {
+		Providers = append(Providers, provider)
+	}
+	backends[provider] = factory
+}
+
+// newBackend builds the backend registered for a provider, or nil
+func newBackend(c *Client) Backend {
+	backendsMu.RLock()
+	factory := backends[c.provider]
+	backendsMu.RUnlock()
+
+	if factory == nil {
+		return nil
+	}
+	return factory(c)
+}
+
+// APIKey returns the API key the client authenticates with
+func (c *Client) APIKey() string {
+	return c.apiKey
+}
+
+// CompletionTokens returns the completion budget of the request being sent,
+// for backends whose API requires a limit
+func (c *Client) CompletionTokens() int {
+	return c.completionTokens
+}
+
+// Temperature returns the configured sampling temperature, if any
+func (c *Client) Temperature() (float64, bool) {
+	if c.temperature == nil {
+		return 0, false
+	}
+	return *c.temperature, true
+}
+
+// TopP returns the configured nucleus sampling probability, if any
+func (c *Client) TopP() (float64, bool) {
+	if c.topP == nil {
+		return 0, false
+	}
+	return *c.topP, true
+}
+
+// AddUsage records tokens consumed by a backend's request
+func (c *Client) AddUsage(input, output int) {
+	c.usage.add(input, output)
+}
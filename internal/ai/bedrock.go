@@ -0,0 +1,192 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// defaultBedrockRegion is used when no AWS region is configured
+const defaultBedrockRegion = "us-east-1"
+
+// bedrockSigningName is the SigV4 service name of both Bedrock endpoints
+const bedrockSigningName = "bedrock"
+
+// bedrockRegionPrefixes start cross-region inference profile IDs, e.g.
+// "us.anthropic.claude-3-5-sonnet-20240620-v1:0"
+var bedrockRegionPrefixes = []string{"us.", "eu.", "apac.", "us-gov."}
+
+// bedrockBaseModel strips the inference profile region and the vendor from
+// a Bedrock model ID, so "us.anthropic.claude-3-haiku-20240307-v1:0" prices
+// as claude-3-haiku
+func bedrockBaseModel(id string) string {
+	for _, prefix := range bedrockRegionPrefixes {
+		id = strings.TrimPrefix(id, prefix)
+	}
+	if _, model, ok := strings.Cut(id, "."); ok {
+		return model
+	}
+	return id
+}
+
+// Bedrock Converse API types
+type bedrockRequest struct {
+	Messages        []bedrockMessage       `json:"messages"`
+	InferenceConfig bedrockInferenceConfig `json:"inferenceConfig"`
+}
+
+type bedrockMessage struct {
+	Role    string           `json:"role"`
+	Content []bedrockContent `json:"content"`
+}
+
+type bedrockContent struct {
+	Text string `json:"text"`
+}
+
+type bedrockInferenceConfig struct {
+	MaxTokens   int      `json:"maxTokens,omitempty"`
+	Temperature *float64 `json:"temperature,omitempty"`
+	TopP        *float64 `json:"topP,omitempty"`
+}
+
+type bedrockResponse struct {
+	Output struct {
+		Message bedrockMessage `json:"message"`
+	} `json:"output"`
+	StopReason string `json:"stopReason"`
+	Usage      struct {
+		InputTokens  int `json:"inputTokens"`
+		OutputTokens int `json:"outputTokens"`
+	} `json:"usage"`
+	Message string `json:"message"`
+}
+
+// newBedrockRequest builds a SigV4-signed request to a Bedrock endpoint,
+// "bedrock" for the control plane or "bedrock-runtime" for inference
+func (c *Client) newBedrockRequest(ctx context.Context, method, endpoint, path string, body []byte) (*http.Request, error) {
+	if c.aws.AccessKeyID == "" || c.aws.SecretAccessKey == "" {
+		return nil, errors.New("AWS credentials are not configured (set AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY)")
+	}
+
+	host := fmt.Sprintf("%s.%s.amazonaws.com", endpoint, c.awsRegion)
+	req, err := http.NewRequestWithContext(ctx, method, "https://"+host, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	// Model IDs contain ":", which AWS expects percent-encoded in the path
+	req.URL.Path = path
+	req.URL.RawPath = escapePath(path)
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	signV4(req, body, c.aws, c.awsRegion, bedrockSigningName, time.Now())
+	return req, nil
+}
+
+// escapePath percent-encodes each segment of a path
+func escapePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = awsEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+func (c *Client) callBedrock(ctx context.Context, prompt string) (string, error) {
+	reqBody := bedrockRequest{
+		Messages: []bedrockMessage{
+			{Role: "user", Content: []bedrockContent{{Text: prompt}}},
+		},
+		InferenceConfig: bedrockInferenceConfig{
+			MaxTokens:   c.completionTokens,
+			Temperature: c.temperature,
+			TopP:        c.topP,
+		},
+	}
+
+	jsonBody, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := c.newBedrockRequest(ctx, "POST", "bedrock-runtime", "/model/"+c.RequestModel(ctx)+"/converse", jsonBody)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	var result bedrockResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	c.usage.add(result.Usage.InputTokens, result.Usage.OutputTokens)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		// Bedrock reports unknown model IDs as a validation error
+		errorType := resp.Header.Get("X-Amzn-ErrorType")
+		if strings.HasPrefix(errorType, "ResourceNotFoundException") ||
+			strings.Contains(result.Message, "model identifier is invalid") {
+			return "", c.modelNotFound(ctx)
+		}
+		return "", fmt.Errorf("API error (status %d): %s", resp.StatusCode, result.Message)
+	}
+
+	if result.StopReason == "content_filtered" || result.StopReason == "guardrail_intervened" {
+		return "", fmt.Errorf("%w: %s", ErrRefusal, result.StopReason)
+	}
+
+	var text strings.Builder
+	for _, content := range result.Output.Message.Content {
+		text.WriteString(content.Text)
+	}
+	if text.Len() == 0 {
+		return "", errors.New("no response from API")
+	}
+
+	return strings.TrimSpace(text.String()), nil
+}
+
+// parseBedrockModels reads the text models from a foundation models response
+func parseBedrockModels(body []byte) ([]string, error) {
+	var result struct {
+		ModelSummaries []struct {
+			ModelID          string   `json:"modelId"`
+			OutputModalities []string `json:"outputModalities"`
+		} `json:"modelSummaries"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	var models []string
+	for _, m := range result.ModelSummaries {
+		for _, modality := range m.OutputModalities {
+			if modality == "TEXT" {
+				models = append(models, m.ModelID)
+				break
+			}
+		}
+	}
+	sort.Strings(models)
+	return models, nil
+}
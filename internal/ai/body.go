@@ -0,0 +1,59 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/namin2/gh-assistant/internal/commitmsg"
+)
+
+// generateBody asks only for a body elaborating on req.Subject and returns
+// the subject, unchanged, followed by the body
+func (c *Client) generateBody(ctx context.Context, req CommitRequest) (string, error) {
+	ctx = c.withModelFor(ctx, req)
+	response, err := c.generate(ctx, c.buildBodyPrompt(req), c.completionBudget(true, 1))
+	if err != nil {
+		return "", err
+	}
+
+	// Models sometimes repeat the subject above the body
+	body := strings.TrimSpace(response)
+	if first, rest, _ := strings.Cut(body, "\n"); strings.TrimSpace(first) == strings.TrimSpace(req.Subject) {
+		body = strings.TrimSpace(rest)
+	}
+	return commitmsg.WrapBody(commitmsg.Format(commitmsg.Join(req.Subject, body), c.bullet), c.bodyWidth), nil
+}
+
+// buildBodyPrompt asks for the body of a commit whose subject is given
+func (c *Client) buildBodyPrompt(req CommitRequest) string {
+	model, _ := c.ModelFor(req)
+	context := ""
+	if len(req.Files) > 0 {
+		context = fmt.Sprintf("\nChanged files:\n- %s\n", strings.Join(req.Files, "\n- "))
+	}
+	if len(req.Notes) > 0 {
+		context += fmt.Sprintf("\nAdditional context:\n- %s\n", strings.Join(req.Notes, "\n- "))
+	}
+	if req.Breaking {
+		context += "\nThis is a BREAKING CHANGE. End the body with a footer \"BREAKING CHANGE: <what changed and how users should migrate>\".\n"
+	}
+
+	return fmt.Sprintf(`You are an expert at writing clear git commit messages.
+
+The author has written this commit subject:
+%s
+
+Write the commit body that elaborates on that subject using the following git diff.
+%s
+Git Diff:
+%s
+
+Rules for the body:
+1. Explain what changed and why in 2-5 short bullet points starting with "%s "
+2. Stay consistent with the subject; don't contradict or restate it
+3. Wrap lines at %d characters
+4. Do NOT include the subject line, any explanation, quotes or code blocks
+%s%s%s
+Respond with ONLY the body, nothing else.`, req.Subject, context, c.truncateDiffFor(model, req.Diff), c.bullet, c.bodyWidth, c.languageRule(true), c.forbiddenRule(), c.sectionsRule())
+}
@@ -0,0 +1,84 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// maxBranchNameLen caps the length of generated branch names
+const maxBranchNameLen = 50
+
+// GenerateBranchName proposes a branch name such as "feat/login-oauth" for a diff
+func (c *Client) GenerateBranchName(ctx context.Context, diff string) (string, error) {
+	if diff == "" {
+		return "", errors.New("no diff provided")
+	}
+
+	prompt := fmt.Sprintf(`Propose a git branch name for the following changes.
+
+Git Diff:
+%s
+
+Rules:
+1. Use the format: type/short-description
+2. type is one of: feat, fix, docs, style, refactor, perf, test, build, ci, chore
+3. short-description is 2-5 lowercase words joined by hyphens, e.g. feat/login-oauth
+4. Use only lowercase letters, digits, hyphens and a single slash
+
+Respond with ONLY the branch name.`, c.truncateDiff(diff))
+
+	name, err := c.generate(ctx, prompt, c.completionBudget(false, 1))
+	if err != nil {
+		return "", err
+	}
+	name = cleanBranchName(name)
+	if name == "" {
+		return "", errors.New("the model did not return a usable branch name")
+	}
+	return name, nil
+}
+
+// cleanBranchName reduces a model response to a valid branch name: the first
+// line, lower-cased, with anything but letters, digits and the first "/"
+// turned into hyphens
+func cleanBranchName(response string) string {
+	line := strings.TrimSpace(response)
+	if i := strings.IndexByte(line, '\n'); i >= 0 {
+		line = line[:i]
+	}
+	// Drop a label such as "Branch name: "
+	if i := strings.LastIndex(line, ": "); i >= 0 {
+		line = line[i+2:]
+	}
+	line = strings.ToLower(strings.Trim(line, "`'\" "))
+
+	var b strings.Builder
+	slash := false
+	lastDash := false
+	for _, r := range line {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+			lastDash = false
+		case r == '/' && !slash && b.Len() > 0:
+			name := strings.TrimRight(b.String(), "-")
+			b.Reset()
+			b.WriteString(name + "/")
+			slash = true
+			lastDash = true
+		default:
+			if !lastDash && b.Len() > 0 {
+				b.WriteByte('-')
+				lastDash = true
+			}
+		}
+	}
+
+	name := b.String()
+	if len(name) > maxBranchNameLen {
+		name = name[:maxBranchNameLen]
+	}
+	return strings.Trim(name, "-/")
+}
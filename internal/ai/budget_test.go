@@ -0,0 +1,116 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCompletionBudget(t *testing.T) {
+	tests := []struct {
+		name      string
+		maxTokens int
+		detailed  bool
+		messages  int
+		want      int
+	}{
+		{"single line", 0, false, 1, singleLineMaxTokens},
+		{"detailed", 0, true, 1, detailedMaxTokens},
+		{"several messages", 0, true, 3, 3 * detailedMaxTokens},
+		{"no messages counts as one", 0, false, 0, singleLineMaxTokens},
+		{"override", 300, true, 3, 300},
+		{"override of single line", 2000, false, 1, 2000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(Config{Provider: ProviderAnthropic, MaxTokens: tt.maxTokens})
+			if got := c.completionBudget(tt.detailed, tt.messages); got != tt.want {
+				t.Errorf("completionBudget(%v, %d) = %d, want %d", tt.detailed, tt.messages, got, tt.want)
+			}
+		})
+	}
+}
+
+// roundTripFunc serves HTTP requests with a function
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestAnthropicRequestsCompletionBudget(t *testing.T) {
+	tests := []struct {
+		name      string
+		maxTokens int
+		want      int
+	}{
+		{"sized by output", 0, detailedMaxTokens},
+		{"override", 700, 700},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(Config{Provider: ProviderAnthropic, APIKey: "key", MaxTokens: tt.maxTokens})
+			var got int
+			c.httpClient.Transport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+				var req anthropicRequest
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					return nil, err
+				}
+				got = req.MaxTokens
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Type": {"application/json"}},
+					Body:       io.NopCloser(strings.NewReader(`{"content": [{"type": "text", "text": "feat: add login"}], "stop_reason": "end_turn"}`)),
+				}, nil
+			})
+
+			if _, err := c.GenerateCommitMessage(context.Background(), CommitRequest{Diff: testDiff(3)}); err != nil {
+				t.Fatalf("GenerateCommitMessage() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("requested max_tokens %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetailedModeRequestsLargerBudget(t *testing.T) {
+	c := New(Config{Provider: ProviderAnthropic, APIKey: "key", Model: "claude-3-5-sonnet-latest"})
+	var maxTokens []int
+	c.httpClient.Transport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		var req anthropicRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			return nil, err
+		}
+		maxTokens = append(maxTokens, req.MaxTokens)
+		answer := "feat/add-login"
+		if strings.Contains(req.Messages[0].Content, "commit message") {
+			answer = "feat: add login\n\nUsers can sign in with a password."
+		}
+		body, _ := json.Marshal(map[string]interface{}{
+			"content":     []map[string]string{{"type": "text", "text": answer}},
+			"stop_reason": "end_turn",
+		})
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(string(body))),
+		}, nil
+	})
+
+	ctx := context.Background()
+	if _, err := c.GenerateBranchName(ctx, testDiff(3)); err != nil {
+		t.Fatalf("GenerateBranchName() error = %v", err)
+	}
+	if _, err := c.GenerateCommitMessage(ctx, CommitRequest{Diff: testDiff(3)}); err != nil {
+		t.Fatalf("GenerateCommitMessage() error = %v", err)
+	}
+
+	if len(maxTokens) != 2 {
+		t.Fatalf("sent %d requests, want 2", len(maxTokens))
+	}
+	if single, detailed := maxTokens[0], maxTokens[1]; detailed <= single || detailed <= 256 {
+		t.Errorf("detailed mode requested max_tokens %d, want more than single-line %d and the old 256", detailed, single)
+	}
+}
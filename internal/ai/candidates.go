@@ -0,0 +1,92 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/namin2/gh-assistant/internal/commitmsg"
+)
+
+// GenerateCandidates asks the model for n distinct commit messages in a
+// single call, each describing the change from a different angle (type,
+// scope or emphasis), best first. Unlike ranked suggestions, candidates are
+// complete messages with a body when the change needs one. If the response
+// can't be parsed, it falls back to generating a single message.
+func (c *Client) GenerateCandidates(ctx context.Context, req CommitRequest, n int) ([]string, error) {
+	if req.Diff == "" && len(req.Notes) == 0 {
+		return nil, errors.New("no diff provided")
+	}
+	if n < 2 {
+		message, err := c.GenerateCommitMessage(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return []string{message}, nil
+	}
+
+	ctx = c.withModelFor(ctx, req)
+	prompt := c.buildPrompt(req, candidatesResponseFormat(n))
+
+	response, err := c.complete(ctx, prompt, c.completionBudget(true, n))
+	if err == nil {
+		if candidates, parseErr := parseCandidates(response); parseErr == nil {
+			for i := range candidates {
+				candidates[i] = c.postProcess(req, candidates[i])
+			}
+			return candidates, nil
+		}
+	} else if !errors.Is(err, ErrRefusal) {
+		return nil, err
+	}
+
+	// Fall back to single-message mode
+	message, err := c.GenerateCommitMessage(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return []string{message}, nil
+}
+
+func candidatesResponseFormat(n int) string {
+	return fmt.Sprintf(`6. Each candidate must be a complete commit message on its own, with a body when the change needs one
+7. Make the candidates genuinely different: vary the type, the scope or what the description emphasizes, not just the wording
+8. Do NOT include any explanation outside the JSON
+
+Respond with ONLY a JSON array of exactly %d commit messages as strings, best first:
+["type(scope): description\n\nOptional body", ...]`, n)
+}
+
+// parseCandidates parses a JSON array of commit messages from a model
+// response, dropping candidates whose subject repeats an earlier one
+func parseCandidates(response string) ([]string, error) {
+	start := strings.Index(response, "[")
+	end := strings.LastIndex(response, "]")
+	if start < 0 || end <= start {
+		return nil, errors.New("no JSON array in response")
+	}
+
+	var raw []string
+	if err := json.Unmarshal([]byte(response[start:end+1]), &raw); err != nil {
+		return nil, fmt.Errorf("invalid candidates JSON: %w", err)
+	}
+
+	var candidates []string
+	seen := make(map[string]bool)
+	for _, message := range raw {
+		message = strings.TrimSpace(message)
+		subject, _ := commitmsg.Split(message)
+		if message == "" || seen[strings.ToLower(subject)] || IsRefusal(message) {
+			continue
+		}
+		seen[strings.ToLower(subject)] = true
+		candidates = append(candidates, message)
+	}
+
+	if len(candidates) == 0 {
+		return nil, errors.New("no usable candidates in response")
+	}
+	return candidates, nil
+}
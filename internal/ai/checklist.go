@@ -0,0 +1,67 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// maxChecklistItems bounds the review checklist
+const maxChecklistItems = 8
+
+// GenerateReviewChecklist suggests what reviewers of a diff should check,
+// e.g. "Check error handling when the upload is retried"
+func (c *Client) GenerateReviewChecklist(ctx context.Context, diff string) ([]string, error) {
+	if diff == "" {
+		return nil, errors.New("no diff provided")
+	}
+
+	prompt := fmt.Sprintf(`Write a checklist for reviewers of the following code changes.
+
+Git Diff:
+%s
+
+Rules:
+1. List 3 to %d concrete things a reviewer should verify, one per line starting with "- "
+2. Name the specific function, file or behavior each item is about
+3. Focus on risk: error handling, edge cases, migrations, security, compatibility and missing tests
+4. Skip generic advice that applies to any change, like "check code style"
+5. Use plain text only, no Markdown headings or code blocks
+%s
+Respond with ONLY the checklist.`, c.truncateDiff(diff), maxChecklistItems, c.languageRule(false))
+
+	response, err := c.generate(ctx, prompt, c.completionBudget(true, 1))
+	if err != nil {
+		return nil, err
+	}
+	items := parseChecklist(response)
+	if len(items) == 0 {
+		return nil, errors.New("the model did not return a checklist")
+	}
+	return items, nil
+}
+
+// checklistMarker matches the list marker of a checklist line: "-", "*",
+// "1." or "1)", optionally followed by a Markdown checkbox
+var checklistMarker = regexp.MustCompile(`^(?:[-*•]|\d+[.)])\s+(?:\[[ xX]?\]\s*)?`)
+
+// parseChecklist returns the list items of a response, without markers
+func parseChecklist(response string) []string {
+	var items []string
+	for _, line := range strings.Split(response, "\n") {
+		line = strings.TrimSpace(line)
+		marker := checklistMarker.FindString(line)
+		if marker == "" {
+			continue
+		}
+		if item := strings.TrimSpace(line[len(marker):]); item != "" {
+			items = append(items, item)
+		}
+		if len(items) == maxChecklistItems {
+			break
+		}
+	}
+	return items
+}
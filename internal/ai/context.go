@@ -0,0 +1,63 @@
+package ai
+
+// contextWindows maps model name prefixes to their context window in tokens.
+// Longer prefixes win, as for modelPrices.
+var contextWindows = map[string]int{
+	"gpt-4o":        128000,
+	"gpt-4-turbo":   128000,
+	"gpt-4":         8192,
+	"gpt-3.5-turbo": 16385,
+	"claude-3":      200000,
+	"gemini":        1048576,
+
+	// Mistral AI
+	"mistral-small":     32000,
+	"mistral-large":     128000,
+	"open-mistral-nemo": 128000,
+	"codestral":         32000,
+	"ministral":         128000,
+
+	// Amazon Titan on Bedrock
+	"titan-text-lite":    4096,
+	"titan-text-express": 8192,
+	"titan-text-premier": 32000,
+}
+
+// promptOverheadTokens is reserved for the prompt's instructions, file list
+// and notes when sizing the diff
+const promptOverheadTokens = 1024
+
+// minDiffLen is the smallest diff budget, so tiny context windows still get
+// some of the diff
+const minDiffLen = 2000
+
+// ContextWindow returns the context window of the client's current model in
+// tokens, from the ContextWindows config or the built-in table. The second
+// return value is false if it's unknown.
+func (c *Client) ContextWindow() (int, bool) {
+	return c.contextWindow(c.model)
+}
+
+// contextWindow returns the context window of a model in tokens
+func (c *Client) contextWindow(model string) (int, bool) {
+	if window, ok := lookupByPrefix(c.contextWindows, model); ok {
+		return window, true
+	}
+	return lookupByPrefix(contextWindows, c.baseModel(model))
+}
+
+// diffBudget returns how many characters of diff fit in a model's context
+// window after reserving room for the instructions and the completion.
+// Models with an unknown window get maxDiffLen.
+func (c *Client) diffBudget(model string) int {
+	window, ok := c.contextWindow(model)
+	if !ok {
+		return maxDiffLen
+	}
+
+	tokens := window - promptOverheadTokens - c.completionBudget(true, 1)
+	if budget := tokens * charsPerToken; budget > minDiffLen {
+		return budget
+	}
+	return minDiffLen
+}
@@ -0,0 +1,165 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// geminiBaseURL is the Generative Language API endpoint
+const geminiBaseURL = "https://generativelanguage.googleapis.com/v1beta"
+
+// Gemini API types
+type geminiRequest struct {
+	Contents         []geminiContent        `json:"contents"`
+	GenerationConfig geminiGenerationConfig `json:"generationConfig"`
+}
+
+type geminiContent struct {
+	Role  string       `json:"role,omitempty"`
+	Parts []geminiPart `json:"parts"`
+}
+
+type geminiPart struct {
+	Text string `json:"text"`
+}
+
+type geminiGenerationConfig struct {
+	MaxOutputTokens int      `json:"maxOutputTokens,omitempty"`
+	Temperature     *float64 `json:"temperature,omitempty"`
+	TopP            *float64 `json:"topP,omitempty"`
+}
+
+type geminiResponse struct {
+	Candidates []struct {
+		Content      geminiContent `json:"content"`
+		FinishReason string        `json:"finishReason"`
+	} `json:"candidates"`
+	PromptFeedback *struct {
+		BlockReason string `json:"blockReason"`
+	} `json:"promptFeedback"`
+	UsageMetadata struct {
+		PromptTokenCount     int `json:"promptTokenCount"`
+		CandidatesTokenCount int `json:"candidatesTokenCount"`
+	} `json:"usageMetadata"`
+	Error *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Status  string `json:"status"`
+	} `json:"error"`
+}
+
+// geminiBlockReasons are finish reasons meaning the model declined to answer
+var geminiBlockReasons = map[string]bool{
+	"SAFETY":             true,
+	"RECITATION":         true,
+	"BLOCKLIST":          true,
+	"PROHIBITED_CONTENT": true,
+	"SPII":               true,
+}
+
+func (c *Client) callGemini(ctx context.Context, prompt string) (string, error) {
+	reqBody := geminiRequest{
+		Contents: []geminiContent{
+			{Role: "user", Parts: []geminiPart{{Text: prompt}}},
+		},
+		GenerationConfig: geminiGenerationConfig{
+			MaxOutputTokens: c.completionTokens,
+			Temperature:     c.temperature,
+			TopP:            c.topP,
+		},
+	}
+
+	jsonBody, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", geminiBaseURL+"/models/"+c.RequestModel(ctx)+":generateContent", bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-goog-api-key", c.apiKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	var result geminiResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	c.usage.add(result.UsageMetadata.PromptTokenCount, result.UsageMetadata.CandidatesTokenCount)
+
+	if result.Error != nil {
+		if result.Error.Status == "NOT_FOUND" && strings.Contains(result.Error.Message, "models/") {
+			return "", c.modelNotFound(ctx)
+		}
+		return "", fmt.Errorf("API error: %s", result.Error.Message)
+	}
+
+	if result.PromptFeedback != nil && result.PromptFeedback.BlockReason != "" {
+		return "", fmt.Errorf("%w: prompt blocked (%s)", ErrRefusal, result.PromptFeedback.BlockReason)
+	}
+
+	if len(result.Candidates) == 0 {
+		return "", errors.New("no response from API")
+	}
+
+	candidate := result.Candidates[0]
+	if geminiBlockReasons[candidate.FinishReason] {
+		return "", fmt.Errorf("%w: response blocked (%s)", ErrRefusal, candidate.FinishReason)
+	}
+
+	var text strings.Builder
+	for _, part := range candidate.Content.Parts {
+		text.WriteString(part.Text)
+	}
+	if text.Len() == 0 {
+		return "", errors.New("no response from API")
+	}
+
+	return strings.TrimSpace(text.String()), nil
+}
+
+// parseGeminiModels reads the models that can generate content from a
+// models list response, without their "models/" prefix
+func parseGeminiModels(body []byte) ([]string, error) {
+	var result struct {
+		Models []struct {
+			Name    string   `json:"name"`
+			Methods []string `json:"supportedGenerationMethods"`
+		} `json:"models"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	var models []string
+	for _, m := range result.Models {
+		for _, method := range m.Methods {
+			if method == "generateContent" {
+				models = append(models, strings.TrimPrefix(m.Name, "models/"))
+				break
+			}
+		}
+	}
+	sort.Strings(models)
+	return models, nil
+}
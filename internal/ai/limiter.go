@@ -0,0 +1,39 @@
+package ai
+
+import "context"
+
+// Limiter bounds the number of API requests in flight across all the
+// clients that share it, to stay under provider rate limits
+type Limiter struct {
+	slots chan struct{}
+}
+
+// NewLimiter returns a Limiter allowing n concurrent requests; n below 1
+// allows one
+func NewLimiter(n int) *Limiter {
+	if n < 1 {
+		n = 1
+	}
+	return &Limiter{slots: make(chan struct{}, n)}
+}
+
+// acquire waits for a free slot, or until ctx is done. A nil Limiter never
+// blocks.
+func (l *Limiter) acquire(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release frees a slot taken by acquire
+func (l *Limiter) release() {
+	if l != nil {
+		<-l.slots
+	}
+}
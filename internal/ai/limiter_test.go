@@ -0,0 +1,121 @@
+package ai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// inFlightServer is an OpenAI-compatible server that refuses requests
+// beyond max in flight with 429 and records the most it saw at once
+type inFlightServer struct {
+	*httptest.Server
+	max int
+
+	mu       sync.Mutex
+	inFlight int
+	peak     int
+}
+
+func newInFlightServer(t *testing.T, max int) *inFlightServer {
+	s := &inFlightServer{max: max}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.inFlight++
+		if s.inFlight > s.peak {
+			s.peak = s.inFlight
+		}
+		over := s.inFlight > s.max
+		s.mu.Unlock()
+		defer func() {
+			s.mu.Lock()
+			s.inFlight--
+			s.mu.Unlock()
+		}()
+
+		if over {
+			http.Error(w, `{"error": {"message": "too many requests in flight"}}`, http.StatusTooManyRequests)
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"choices": [{"message": {"content": "feat: add x"}}]}`))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Peak returns the most requests that were in flight at once
+func (s *inFlightServer) Peak() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.peak
+}
+
+func TestLimiterBoundsRequestsInFlight(t *testing.T) {
+	const limit, clients, requests = 2, 4, 3
+	server := newInFlightServer(t, limit)
+	limiter := NewLimiter(limit)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, clients*requests)
+	for i := 0; i < clients; i++ {
+		// Each repository gets its own client sharing the limiter
+		client := New(Config{
+			Provider:      ProviderOpenAI,
+			APIKey:        "test",
+			OpenAIBaseURL: server.URL,
+			MaxAttempts:   1,
+			Limiter:       limiter,
+		})
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < requests; j++ {
+				if _, err := client.GenerateCommitMessage(context.Background(), CommitRequest{Diff: testDiff(1)}); err != nil {
+					errs <- err
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("GenerateCommitMessage() error = %v", err)
+	}
+	if peak := server.Peak(); peak != limit {
+		t.Errorf("peak requests in flight = %d, want %d", peak, limit)
+	}
+}
+
+func TestLimiterAcquireHonorsContext(t *testing.T) {
+	limiter := NewLimiter(1)
+	if err := limiter.acquire(context.Background()); err != nil {
+		t.Fatalf("acquire() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := limiter.acquire(ctx); err != context.DeadlineExceeded {
+		t.Errorf("acquire() with no free slot = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	limiter.release()
+	if err := limiter.acquire(context.Background()); err != nil {
+		t.Errorf("acquire() after release error = %v", err)
+	}
+}
+
+func TestNilLimiterNeverBlocks(t *testing.T) {
+	var limiter *Limiter
+	for i := 0; i < 3; i++ {
+		if err := limiter.acquire(context.Background()); err != nil {
+			t.Fatalf("acquire() error = %v", err)
+		}
+	}
+	limiter.release()
+}
@@ -0,0 +1,25 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// mistralBaseURL is the Mistral AI API, which speaks the Chat Completions
+// protocol from servers in the EU
+const mistralBaseURL = "https://api.mistral.ai/v1"
+
+// mistralError converts an error Mistral reports at the top level of the
+// response. Its message is a string, or an object for validation errors.
+func (c *Client) mistralError(ctx context.Context, errorType string, message json.RawMessage) error {
+	var text string
+	if err := json.Unmarshal(message, &text); err != nil {
+		text = string(message)
+	}
+	if errorType == "invalid_model" || strings.HasPrefix(text, "Invalid model") {
+		return c.modelNotFound(ctx)
+	}
+	return fmt.Errorf("API error: %s", text)
+}
@@ -0,0 +1,88 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// mockModel is the only model of the mock provider
+const mockModel = "template"
+
+// mockBackend answers every prompt with a commit message built from the
+// stats of the diff in the prompt, without calling a model. The same
+// prompt always gets the same answer, so it suits CI and test suites.
+type mockBackend struct{}
+
+func (mockBackend) Generate(ctx context.Context, prompt string) (string, error) {
+	diff := extractDiff(prompt)
+	subject := HeuristicMessage(CommitRequest{Diff: diff})
+	files, insertions, deletions := diffStats(diff)
+	if files == 0 {
+		return subject, nil
+	}
+	return fmt.Sprintf("%s\n\n%d file(s) changed, %d insertion(s)(+), %d deletion(s)(-)",
+		subject, files, insertions, deletions), nil
+}
+
+func (mockBackend) ListModels(ctx context.Context) ([]string, error) {
+	return []string{mockModel}, nil
+}
+
+// diffHeaderPrefixes start the lines of a file's header in a git diff
+var diffHeaderPrefixes = []string{
+	"index ", "--- ", "+++ ", "new file mode ", "deleted file mode ", "old mode ", "new mode ",
+	"similarity index ", "dissimilarity index ", "rename from ", "rename to ", "copy from ", "copy to ",
+	"Binary files ",
+}
+
+// extractDiff returns the git diff embedded in a prompt: the lines from the
+// first "diff --git" up to the first line that can't belong to a diff
+func extractDiff(prompt string) string {
+	start := strings.Index(prompt, "diff --git ")
+	if start < 0 {
+		return ""
+	}
+
+	var kept []string
+	inHunk := false
+	for _, line := range strings.Split(prompt[start:], "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			inHunk = false
+		case strings.HasPrefix(line, "@@"):
+			inHunk = true
+		case inHunk && line != "" && strings.ContainsRune(" +-\\", rune(line[0])):
+		case !inHunk && hasAnyPrefix(line, diffHeaderPrefixes):
+		default:
+			return strings.Join(kept, "\n") + "\n"
+		}
+		kept = append(kept, line)
+	}
+	return strings.Join(kept, "\n") + "\n"
+}
+
+// diffStats counts the files and the added and removed lines of a diff
+func diffStats(diff string) (files, insertions, deletions int) {
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			files++
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+		case strings.HasPrefix(line, "+"):
+			insertions++
+		case strings.HasPrefix(line, "-"):
+			deletions++
+		}
+	}
+	return files, insertions, deletions
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package ai
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMockProvider(t *testing.T) {
+	newFile := "diff --git a/docs/guide.md b/docs/guide.md\nnew file mode 100644\n--- /dev/null\n+++ b/docs/guide.md\n@@ -0,0 +1,2 @@\n+# Guide\n+Read me.\n"
+	tests := []struct {
+		name string
+		req  CommitRequest
+		want string
+	}{
+		{
+			name: "changed file",
+			req:  CommitRequest{Diff: testDiff(3)},
+			want: "chore: update main.go\n\n1 file(s) changed, 3 insertion(s)(+), 0 deletion(s)(-)",
+		},
+		{
+			name: "new doc",
+			req:  CommitRequest{Diff: newFile},
+			want: "docs(docs): add guide.md\n\n1 file(s) changed, 2 insertion(s)(+), 0 deletion(s)(-)",
+		},
+		{
+			name: "several files",
+			req:  CommitRequest{Diff: testDiff(2) + "diff --git a/go.mod b/go.mod\n--- a/go.mod\n+++ b/go.mod\n@@ -1 +1 @@\n-go 1.20\n+go 1.21\n"},
+			want: "chore: update 2 files\n\n2 file(s) changed, 3 insertion(s)(+), 1 deletion(s)(-)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// No API key is needed
+			client := New(Config{Provider: ProviderMock})
+			if got := client.Model(); got != mockModel {
+				t.Errorf("Model() = %q, want %q", got, mockModel)
+			}
+
+			first, err := client.GenerateCommitMessage(context.Background(), tt.req)
+			if err != nil {
+				t.Fatalf("GenerateCommitMessage() error = %v", err)
+			}
+			if first != tt.want {
+				t.Errorf("GenerateCommitMessage() = %q, want %q", first, tt.want)
+			}
+			second, _ := client.GenerateCommitMessage(context.Background(), tt.req)
+			if second != first {
+				t.Errorf("second message %q differs from the first %q", second, first)
+			}
+		})
+	}
+}
+
+func TestMockProviderListsItsModel(t *testing.T) {
+	models, err := New(Config{Provider: ProviderMock}).ListModels(context.Background())
+	if err != nil || len(models) != 1 || models[0] != mockModel {
+		t.Errorf("ListModels() = %v, %v, want [%s]", models, err, mockModel)
+	}
+}
+
+func TestExtractDiff(t *testing.T) {
+	diff := testDiff(2)
+	tests := []struct {
+		name, prompt, want string
+	}{
+		{"no diff", "Summarize the change.", ""},
+		{"diff between instructions", "Git Diff:\n" + diff + "\nRules:\n1. Be brief", diff},
+		{"diff at the end", "Git Diff:\n" + diff, diff},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extractDiff(tt.prompt); got != tt.want {
+				t.Errorf("extractDiff() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
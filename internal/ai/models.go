@@ -0,0 +1,185 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// maxModelSuggestions is how many available models a ModelNotFoundError lists
+const maxModelSuggestions = 5
+
+// ModelNotFoundError is returned when the provider doesn't know the configured model
+type ModelNotFoundError struct {
+	Provider Provider
+	Model    string
+	// Suggestions are available models with names closest to Model, if the
+	// provider's model list could be fetched
+	Suggestions []string
+}
+
+func (e *ModelNotFoundError) Error() string {
+	msg := fmt.Sprintf("model %q was not found by %s", e.Model, e.Provider)
+	if len(e.Suggestions) > 0 {
+		msg += fmt.Sprintf("\n  Available models include: %s", strings.Join(e.Suggestions, ", "))
+	}
+	return msg + `
+  Check the configured model with: gh-assistant config --show
+  Set a different model with:      gh-assistant config --model MODEL`
+}
+
+// modelNotFound builds a ModelNotFoundError for the model requests made with
+// ctx go to, suggesting similar models when the provider's model list is
+// available
+func (c *Client) modelNotFound(ctx context.Context) error {
+	model := c.RequestModel(ctx)
+	err := &ModelNotFoundError{Provider: c.provider, Model: model}
+	if models, listErr := c.ListModels(ctx); listErr == nil {
+		err.Suggestions = closestModels(model, models, maxModelSuggestions)
+	}
+	return err
+}
+
+// ListModels returns the IDs of the models available to the API key, or the
+// models pulled into a local Ollama server
+func (c *Client) ListModels(ctx context.Context) ([]string, error) {
+	if lister, ok := c.backend.(ModelLister); ok {
+		return lister.ListModels(ctx)
+	}
+
+	req, err := c.newModelsRequest(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	switch c.provider {
+	case ProviderOllama:
+		return parseOllamaModels(body)
+	case ProviderGemini:
+		return parseGeminiModels(body)
+	case ProviderBedrock:
+		return parseBedrockModels(body)
+	}
+
+	var result struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	models := make([]string, 0, len(result.Data))
+	for _, m := range result.Data {
+		models = append(models, m.ID)
+	}
+	sort.Strings(models)
+	return models, nil
+}
+
+// CheckModel verifies that the provider lists the client's model, returning
+// a ModelNotFoundError with similar models when it doesn't
+func (c *Client) CheckModel(ctx context.Context) error {
+	models, err := c.ListModels(ctx)
+	if err != nil {
+		return err
+	}
+	for _, m := range models {
+		if m == c.model {
+			return nil
+		}
+	}
+	return &ModelNotFoundError{
+		Provider:    c.provider,
+		Model:       c.model,
+		Suggestions: closestModels(c.model, models, maxModelSuggestions),
+	}
+}
+
+// newModelsRequest builds an authenticated request for the provider's model list
+func (c *Client) newModelsRequest(ctx context.Context) (*http.Request, error) {
+	switch c.provider {
+	case ProviderOpenAI, ProviderMistral, ProviderOpenRouter:
+		req, err := http.NewRequestWithContext(ctx, "GET", c.chatBaseURL()+"/models", nil)
+		if err != nil {
+			return nil, err
+		}
+		c.setOpenAIAuth(req)
+		c.setOpenAIHeaders(req)
+		if c.provider == ProviderOpenRouter {
+			setOpenRouterHeaders(req)
+		}
+		return req, nil
+	case ProviderAnthropic:
+		req, err := http.NewRequestWithContext(ctx, "GET", "https://api.anthropic.com/v1/models", nil)
+		if err != nil {
+			return nil, err
+		}
+		c.setAnthropicHeaders(req)
+		return req, nil
+	case ProviderOllama:
+		return http.NewRequestWithContext(ctx, "GET", c.ollamaURL+"/api/tags", nil)
+	case ProviderAzure:
+		endpoint, err := c.azureURL("/openai/models")
+		if err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("api-key", c.apiKey)
+		c.setOpenAIHeaders(req)
+		return req, nil
+	case ProviderBedrock:
+		return c.newBedrockRequest(ctx, "GET", "bedrock", "/foundation-models", nil)
+	case ProviderGemini:
+		req, err := http.NewRequestWithContext(ctx, "GET", geminiBaseURL+"/models?pageSize=1000", nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("x-goog-api-key", c.apiKey)
+		return req, nil
+	default:
+		return nil, fmt.Errorf("unsupported provider: %s", c.provider)
+	}
+}
+
+// closestModels returns up to n models ordered by how long a prefix they
+// share with model, then by name
+func closestModels(model string, models []string, n int) []string {
+	shared := func(m string) int {
+		i := 0
+		for i < len(m) && i < len(model) && m[i] == model[i] {
+			i++
+		}
+		return i
+	}
+
+	ranked := append([]string(nil), models...)
+	sort.SliceStable(ranked, func(a, b int) bool {
+		return shared(ranked[a]) > shared(ranked[b])
+	})
+	if len(ranked) > n {
+		ranked = ranked[:n]
+	}
+	return ranked
+}
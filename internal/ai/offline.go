@@ -0,0 +1,111 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/namin2/gh-assistant/internal/patch"
+)
+
+// IsUnreachable reports whether err means the provider couldn't be reached
+// at all (no network, DNS failure, refused connection, timeout), as opposed
+// to an error returned by the API. A canceled request isn't unreachable.
+func IsUnreachable(err error) bool {
+	var urlErr *url.Error
+	return errors.As(err, &urlErr) && !errors.Is(err, context.Canceled)
+}
+
+// HeuristicMessage writes a simple commit message from the shape of the diff
+// without calling a model, e.g. "docs: update README.md" or "feat(api): add
+// 3 files". It is a fallback for when the provider is unreachable.
+func HeuristicMessage(req CommitRequest) string {
+	files := patch.Parse(req.Diff)
+	paths := req.Files
+	if len(paths) == 0 {
+		for _, f := range files {
+			paths = append(paths, f.Path)
+		}
+	}
+	if len(paths) == 0 {
+		return "chore: update files"
+	}
+
+	added, deleted := 0, 0
+	for _, f := range files {
+		switch {
+		case strings.Contains(f.Text, "\nnew file mode "):
+			added++
+		case strings.Contains(f.Text, "\ndeleted file mode "):
+			deleted++
+		}
+	}
+
+	commitType := "chore"
+	switch {
+	case allPaths(paths, isDocPath):
+		commitType = "docs"
+	case allPaths(paths, isTestPath):
+		commitType = "test"
+	case added == len(files) && len(files) > 0:
+		commitType = "feat"
+	}
+
+	verb := "update"
+	switch {
+	case added == len(files) && len(files) > 0:
+		verb = "add"
+	case deleted == len(files) && len(files) > 0:
+		verb = "remove"
+	}
+
+	what := path.Base(paths[0])
+	if len(paths) > 1 {
+		what = fmt.Sprintf("%d files", len(paths))
+	}
+
+	header := commitType
+	if scope := commonDir(paths); scope != "" {
+		header += "(" + scope + ")"
+	}
+	return fmt.Sprintf("%s: %s %s", header, verb, what)
+}
+
+// allPaths reports whether every path satisfies match
+func allPaths(paths []string, match func(string) bool) bool {
+	for _, p := range paths {
+		if !match(p) {
+			return false
+		}
+	}
+	return true
+}
+
+func isDocPath(p string) bool {
+	ext := strings.ToLower(path.Ext(p))
+	return ext == ".md" || ext == ".rst" || ext == ".txt" || strings.HasPrefix(p, "docs/")
+}
+
+func isTestPath(p string) bool {
+	return strings.HasSuffix(p, "_test.go") || strings.Contains(p, "/test/") ||
+		strings.HasPrefix(p, "test/") || strings.Contains(path.Base(p), ".test.") ||
+		strings.Contains(path.Base(p), ".spec.")
+}
+
+// commonDir returns the last segment of the directory shared by all paths,
+// or "" if they don't share one
+func commonDir(paths []string) string {
+	dir := path.Dir(paths[0])
+	for _, p := range paths[1:] {
+		for dir != "." && dir != "/" && p != dir && !strings.HasPrefix(p, dir+"/") {
+			dir = path.Dir(dir)
+		}
+	}
+	if dir == "." || dir == "/" {
+		return ""
+	}
+	return path.Base(dir)
+}
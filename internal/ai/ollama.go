@@ -0,0 +1,155 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// DefaultOllamaHost is the address of a local Ollama server
+const DefaultOllamaHost = "http://localhost:11434"
+
+// defaultOllamaPort is used for hosts given without a scheme or port, as
+// the ollama CLI does for OLLAMA_HOST
+const defaultOllamaPort = "11434"
+
+// OllamaURL normalizes an Ollama host setting ("localhost",
+// "10.0.0.5:11434" or "https://ollama.example.com") to a base URL
+func OllamaURL(host string) string {
+	host = strings.TrimRight(strings.TrimSpace(host), "/")
+	if host == "" {
+		return DefaultOllamaHost
+	}
+	if strings.Contains(host, "://") {
+		return host
+	}
+
+	u, err := url.Parse("http://" + host)
+	if err != nil || u.Host == "" {
+		return "http://" + host
+	}
+	if u.Port() == "" {
+		u.Host += ":" + defaultOllamaPort
+	}
+	return u.String()
+}
+
+// Ollama API types
+type ollamaRequest struct {
+	Model    string          `json:"model"`
+	Messages []ollamaMessage `json:"messages"`
+	Stream   bool            `json:"stream"`
+	Options  ollamaOptions   `json:"options"`
+}
+
+type ollamaMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type ollamaOptions struct {
+	NumPredict  int      `json:"num_predict,omitempty"`
+	NumCtx      int      `json:"num_ctx,omitempty"`
+	Temperature *float64 `json:"temperature,omitempty"`
+	TopP        *float64 `json:"top_p,omitempty"`
+}
+
+type ollamaResponse struct {
+	Message struct {
+		Content string `json:"content"`
+	} `json:"message"`
+	PromptEvalCount int    `json:"prompt_eval_count"`
+	EvalCount       int    `json:"eval_count"`
+	Error           string `json:"error"`
+}
+
+func (c *Client) callOllama(ctx context.Context, prompt string) (string, error) {
+	model := c.RequestModel(ctx)
+	reqBody := ollamaRequest{
+		Model: model,
+		Messages: []ollamaMessage{
+			{Role: "user", Content: prompt},
+		},
+		Options: ollamaOptions{
+			NumPredict:  c.completionTokens,
+			Temperature: c.temperature,
+			TopP:        c.topP,
+		},
+	}
+	// Ollama loads models with a small context window by default and
+	// silently drops the start of longer prompts, so ask for the window
+	// the diff was sized for
+	if window, ok := c.contextWindow(model); ok {
+		reqBody.Options.NumCtx = window
+	}
+
+	jsonBody, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.ollamaURL+"/api/chat", bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("API request failed (is Ollama running at %s?): %w", c.ollamaURL, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	var result ollamaResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	c.usage.add(result.PromptEvalCount, result.EvalCount)
+
+	if result.Error != "" {
+		// Ollama reports models that haven't been pulled as a 404
+		if resp.StatusCode == http.StatusNotFound && strings.Contains(result.Error, "not found") {
+			return "", c.modelNotFound(ctx)
+		}
+		return "", fmt.Errorf("API error: %s", result.Error)
+	}
+
+	if result.Message.Content == "" {
+		return "", errors.New("no response from API")
+	}
+
+	return strings.TrimSpace(result.Message.Content), nil
+}
+
+// parseOllamaModels reads the pulled models from an /api/tags response
+func parseOllamaModels(body []byte) ([]string, error) {
+	var result struct {
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	models := make([]string, 0, len(result.Models))
+	for _, m := range result.Models {
+		models = append(models, m.Name)
+	}
+	sort.Strings(models)
+	return models, nil
+}
@@ -0,0 +1,37 @@
+package ai
+
+import (
+	"net/http"
+	"strings"
+)
+
+// openRouterBaseURL is the OpenRouter API, which routes Chat Completions
+// requests to the vendor named in "vendor/model" IDs
+const openRouterBaseURL = "https://openrouter.ai/api/v1"
+
+// OpenRouter attribution headers identifying the app making the requests
+const (
+	openRouterReferer = "https://github.com/namin2/gh-assistant"
+	openRouterTitle   = "gh-assistant"
+)
+
+// setOpenRouterHeaders sets the headers OpenRouter uses to attribute requests
+func setOpenRouterHeaders(req *http.Request) {
+	req.Header.Set("HTTP-Referer", openRouterReferer)
+	req.Header.Set("X-Title", openRouterTitle)
+}
+
+// openRouterBaseModel strips the vendor and variant from an OpenRouter
+// model ID, so "openai/gpt-4o-mini:nitro" prices as gpt-4o-mini
+func openRouterBaseModel(id string) string {
+	if i := strings.LastIndex(id, "/"); i >= 0 {
+		id = id[i+1:]
+	}
+	id, _, _ = strings.Cut(id, ":")
+	return id
+}
+
+// openRouterFree reports whether a model ID names a free variant
+func openRouterFree(id string) bool {
+	return strings.HasSuffix(id, ":free")
+}
@@ -0,0 +1,50 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// GeneratePlainSummary explains a diff in one plain-English sentence for
+// readers who don't read code, such as product managers
+func (c *Client) GeneratePlainSummary(ctx context.Context, diff string) (string, error) {
+	if diff == "" {
+		return "", errors.New("no diff provided")
+	}
+
+	prompt := fmt.Sprintf(`Explain what the following code changes do for a non-technical reader, such as a product manager.
+
+Git Diff:
+%s
+
+Rules:
+1. Write exactly one sentence of at most 120 characters
+2. Describe the effect for users or the product, not how the code changed
+3. Avoid jargon, file names, function names and other code identifiers
+4. Use plain text only, no Markdown or quotes
+
+Respond with ONLY the sentence.`, c.truncateDiff(diff))
+
+	summary, err := c.generate(ctx, prompt, c.completionBudget(false, 1))
+	if err != nil {
+		return "", err
+	}
+	summary = firstLine(summary)
+	if summary == "" {
+		return "", errors.New("the model did not return an explanation")
+	}
+	return summary, nil
+}
+
+// firstLine returns the first non-empty line of a response without
+// surrounding whitespace or quotes
+func firstLine(response string) string {
+	for _, line := range strings.Split(response, "\n") {
+		if line = strings.Trim(line, "`'\" \t\r"); line != "" {
+			return line
+		}
+	}
+	return ""
+}
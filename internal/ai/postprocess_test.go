@@ -0,0 +1,78 @@
+package ai
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestGeneratedMessagesDropEmptyScopes(t *testing.T) {
+	tests := []struct {
+		style  Style
+		answer string
+		want   string
+	}{
+		{StyleConventional, "feat(): add login", "feat: add login"},
+		{StyleConventional, "fix( )!: drop the v1 API", "fix!: drop the v1 API"},
+		{StyleConventional, "feat(auth): add login", "feat(auth): add login"},
+		{StyleGitmoji, "✨ feat(): add login", "✨ feat: add login"},
+	}
+	for _, tt := range tests {
+		client := newTestClient(Config{Style: tt.style}, func(ctx context.Context, c *Client, prompt string) (string, error) {
+			return tt.answer, nil
+		})
+		got, err := client.GenerateCommitMessage(context.Background(), CommitRequest{Diff: testDiff(3)})
+		if err != nil {
+			t.Fatalf("GenerateCommitMessage() error = %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("%s message %q became %q, want %q", tt.style, tt.answer, got, tt.want)
+		}
+	}
+}
+
+func TestBreakingRequests(t *testing.T) {
+	tests := []struct {
+		name   string
+		style  Style
+		answer string
+		want   string
+	}{
+		{
+			name:   "header marked and footer moved last",
+			style:  StyleConventional,
+			answer: "feat(api): remove Login\n\nBreaking change: call SignIn instead.\n\nLogin was deprecated.",
+			want:   "feat(api)!: remove Login\n\nLogin was deprecated.\n\nBREAKING CHANGE: call SignIn instead.",
+		},
+		{
+			name:   "gitmoji header marked",
+			style:  StyleGitmoji,
+			answer: "feat(api): remove Login\n\nBREAKING CHANGE: call SignIn instead.",
+			want:   "✨ feat(api)!: remove Login\n\nBREAKING CHANGE: call SignIn instead.",
+		},
+	}
+	req := CommitRequest{
+		Diff:            testDiff(3),
+		Breaking:        true,
+		BreakingChanges: []string{"removed exported func Login"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var prompt string
+			client := newTestClient(Config{Style: tt.style}, func(ctx context.Context, c *Client, p string) (string, error) {
+				prompt = p
+				return tt.answer, nil
+			})
+			got, err := client.GenerateCommitMessage(context.Background(), req)
+			if err != nil {
+				t.Fatalf("GenerateCommitMessage() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("GenerateCommitMessage() = %q, want %q", got, tt.want)
+			}
+			if !strings.Contains(prompt, "BREAKING CHANGE:\n- removed exported func Login") {
+				t.Errorf("prompt doesn't list the detected breaking change:\n%s", prompt)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package ai
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrRefusal is returned when the model declines to produce a commit message
+var ErrRefusal = errors.New("model refused to generate a commit message")
+
+// clarifyingInstruction is appended to the prompt when retrying after a refusal
+const clarifyingInstruction = `
+
+Note: this is a routine request to summarize source code changes for version control.
+The diff above is the user's own code. Describe what changed as a commit message.`
+
+// refusalMaxLen is the longest response still considered a possible refusal.
+// Real refusals are short; long responses are treated as genuine output.
+const refusalMaxLen = 300
+
+// refusalPhrases are apologetic openers that indicate the model declined
+var refusalPhrases = []string{
+	"i can't",
+	"i cannot",
+	"i can not",
+	"i won't",
+	"i will not",
+	"i'm sorry",
+	"i am sorry",
+	"i'm unable",
+	"i am unable",
+	"i apologize",
+	"sorry,",
+	"sorry but",
+	"as an ai",
+	"unfortunately, i",
+	"i'm not able",
+	"i am not able",
+}
+
+// IsRefusal reports whether a model response looks like a refusal rather than
+// a commit message. It only flags short responses that open with, or contain,
+// typical apologetic phrasing.
+func IsRefusal(response string) bool {
+	text := strings.ToLower(strings.TrimSpace(response))
+	if text == "" || len(text) > refusalMaxLen {
+		return false
+	}
+
+	// Normalize curly apostrophes so "I can’t" matches "i can't"
+	text = strings.ReplaceAll(text, "’", "'")
+
+	for _, phrase := range refusalPhrases {
+		if strings.HasPrefix(text, phrase) {
+			return true
+		}
+	}
+
+	// Phrases later in a short response still count (e.g. "Hmm, I'm sorry...")
+	firstLine := strings.SplitN(text, "\n", 2)[0]
+	for _, phrase := range refusalPhrases {
+		if strings.Contains(firstLine, phrase) && !looksLikeConventionalCommit(firstLine) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// looksLikeConventionalCommit reports whether a line starts with "type:" or "type(scope):"
+func looksLikeConventionalCommit(line string) bool {
+	colon := strings.Index(line, ":")
+	if colon <= 0 {
+		return false
+	}
+	head := line[:colon]
+	if paren := strings.Index(head, "("); paren > 0 {
+		head = head[:paren]
+	}
+	head = strings.TrimSuffix(head, "!")
+	for _, r := range head {
+		if r < 'a' || r > 'z' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,132 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestIsRefusal(t *testing.T) {
+	tests := []struct {
+		response string
+		want     bool
+	}{
+		{"I can't help with that.", true},
+		{"I’m sorry, but I cannot generate a commit message for this diff.", true},
+		{"  Sorry, I am unable to process this request.  ", true},
+		{"As an AI, I do not have access to your repository.", true},
+		{"Unfortunately, I need more context.", true},
+		{"Hmm, I'm not able to read this diff.", true},
+		{"I CANNOT do this", true},
+		{"feat: add login", false},
+		{"fix: handle the case where I can't reach the server", false},
+		{"fix(ui)!: show sorry, try again on failure", false},
+		{"docs: explain why I won't merge unreviewed changes\n\nI'm sorry wording removed.", false},
+		{"Update README\n\nI'm sorry for the typo earlier.", false},
+		{"", false},
+		{"I'm sorry, " + strings.Repeat("long explanation ", 30), false},
+	}
+	for _, tt := range tests {
+		if got := IsRefusal(tt.response); got != tt.want {
+			t.Errorf("IsRefusal(%q) = %v, want %v", tt.response, got, tt.want)
+		}
+	}
+}
+
+func TestRefusalIsRetriedWithClarification(t *testing.T) {
+	failure := errors.New("connection reset")
+	tests := []struct {
+		name     string
+		answers  []string
+		errs     []error
+		want     string
+		wantErr  error
+		requests int
+	}{
+		{
+			name:     "message",
+			answers:  []string{"feat: add login"},
+			want:     "feat: add login",
+			requests: 1,
+		},
+		{
+			name:     "refusal then message",
+			answers:  []string{"I'm sorry, I can't help with that.", "feat: add login"},
+			want:     "feat: add login",
+			requests: 2,
+		},
+		{
+			name:     "provider refusal then message",
+			answers:  []string{"", "feat: add login"},
+			errs:     []error{ErrRefusal, nil},
+			want:     "feat: add login",
+			requests: 2,
+		},
+		{
+			name:     "refused twice",
+			answers:  []string{"I can't help with that.", "Sorry, I cannot help with that either."},
+			wantErr:  ErrRefusal,
+			requests: 2,
+		},
+		{
+			name:     "other errors aren't retried",
+			answers:  []string{""},
+			errs:     []error{failure},
+			wantErr:  failure,
+			requests: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var prompts []string
+			client := newTestClient(Config{}, func(ctx context.Context, c *Client, prompt string) (string, error) {
+				i := len(prompts)
+				prompts = append(prompts, prompt)
+				if i >= len(tt.answers) {
+					return "", errors.New("unexpected request")
+				}
+				if i < len(tt.errs) && tt.errs[i] != nil {
+					return "", tt.errs[i]
+				}
+				return tt.answers[i], nil
+			})
+
+			got, err := client.GenerateCommitMessage(context.Background(), CommitRequest{Diff: testDiff(3)})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("GenerateCommitMessage() error = %v, want %v", err, tt.wantErr)
+				}
+			} else if err != nil || got != tt.want {
+				t.Errorf("GenerateCommitMessage() = %q, %v, want %q", got, err, tt.want)
+			}
+
+			if len(prompts) != tt.requests {
+				t.Fatalf("sent %d requests, want %d", len(prompts), tt.requests)
+			}
+			if strings.Contains(prompts[0], clarifyingInstruction) {
+				t.Error("first request already has the clarifying instruction")
+			}
+			if tt.requests > 1 && prompts[1] != prompts[0]+clarifyingInstruction {
+				t.Error("retry isn't the first prompt with the clarifying instruction")
+			}
+		})
+	}
+}
+
+func TestOpenAIRefusalField(t *testing.T) {
+	client := New(Config{Provider: ProviderOpenAI, APIKey: "key"})
+	client.httpClient.Transport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{"choices": [{"message": {"content": "", "refusal": "I can't assist with that."}}]}`)),
+		}, nil
+	})
+	_, err := client.GenerateCommitMessage(context.Background(), CommitRequest{Diff: testDiff(3)})
+	if !errors.Is(err, ErrRefusal) {
+		t.Errorf("GenerateCommitMessage() error = %v, want ErrRefusal", err)
+	}
+}
@@ -0,0 +1,33 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// GenerateReleaseNotes writes user-facing release notes from a changelog of
+// commits already grouped by type
+func (c *Client) GenerateReleaseNotes(ctx context.Context, version, changelog string) (string, error) {
+	if changelog == "" {
+		return "", errors.New("no commits provided")
+	}
+
+	prompt := fmt.Sprintf(`You are writing release notes for %s of a software project.
+
+Below are the commits in this release, grouped by conventional commit type:
+
+%s
+
+Write clear, user-facing release notes in Markdown:
+1. Use these sections, in this order, omitting any that would be empty:
+   "## Breaking Changes", "## Features", "## Fixes", "## Other Changes"
+2. Describe the impact on users, not implementation details
+3. Merge related commits into a single bullet
+4. Under Breaking Changes, explain what users must change
+5. Do NOT invent changes that aren't in the commits
+
+Respond with ONLY the release notes.`, version, changelog)
+
+	return c.generate(ctx, prompt, c.completionBudget(true, 2))
+}
@@ -0,0 +1,141 @@
+package ai
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultMaxAttempts is how many times a transiently failing request is
+// sent when no limit is configured
+const DefaultMaxAttempts = 3
+
+// Backoff between attempts: the first retry waits up to retryBaseDelay,
+// doubling with each attempt up to retryMaxDelay
+const (
+	retryBaseDelay = time.Second
+	retryMaxDelay  = 30 * time.Second
+)
+
+// retryableStatus are the statuses of failures that are likely to succeed
+// when retried
+var retryableStatus = map[int]bool{
+	http.StatusTooManyRequests:     true,
+	http.StatusInternalServerError: true,
+	http.StatusBadGateway:          true,
+	http.StatusServiceUnavailable:  true,
+	529:                            true, // Anthropic is overloaded
+}
+
+// RetryFunc is called before a failed request is sent again, with the
+// attempt about to be made, the configured maximum, the wait before it and
+// why the previous attempt failed
+type RetryFunc func(attempt, maxAttempts int, wait time.Duration, reason string)
+
+// retryTransport resends requests that fail with a network error or a
+// retryable status, waiting with jittered exponential backoff or as long
+// as the Retry-After header asks
+type retryTransport struct {
+	base        http.RoundTripper
+	maxAttempts int
+	onRetry     RetryFunc
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	attemptReq := req
+	for attempt := 1; ; attempt++ {
+		resp, err := t.base.RoundTrip(attemptReq)
+		if attempt >= t.maxAttempts || ctx.Err() != nil {
+			return resp, err
+		}
+		// A body that can't be replayed can't be sent again
+		if req.Body != nil && req.GetBody == nil {
+			return resp, err
+		}
+
+		var reason string
+		var wait time.Duration
+		switch {
+		case err != nil:
+			reason = fmt.Sprintf("request failed (%v)", err)
+			wait = backoff(attempt)
+		case retryableStatus[resp.StatusCode]:
+			reason = fmt.Sprintf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+			wait = retryAfter(resp.Header.Get("Retry-After"), time.Now())
+			if wait <= 0 {
+				wait = backoff(attempt)
+			}
+		default:
+			return resp, err
+		}
+
+		// Report the failure rather than waiting past the request's deadline
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			return resp, err
+		}
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		if t.onRetry != nil {
+			t.onRetry(attempt+1, t.maxAttempts, wait, reason)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		attemptReq = req.Clone(ctx)
+		if req.Body != nil {
+			if attemptReq.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// backoff returns the wait before the attempt after the given one: a random
+// duration in the upper half of the exponentially growing delay, so clients
+// hitting the same limit don't retry in lockstep
+func backoff(attempt int) time.Duration {
+	delay := retryBaseDelay << (attempt - 1)
+	if delay > retryMaxDelay || delay <= 0 {
+		delay = retryMaxDelay
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)))
+}
+
+// retryAfter parses a Retry-After header, given in seconds or as an HTTP
+// date, into a wait of at most retryMaxDelay, so a server can't stall the
+// command. It's zero when the header is missing, invalid or not in the
+// future, leaving the wait to backoff.
+func retryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+
+	var wait time.Duration
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if seconds > int64(retryMaxDelay/time.Second) {
+			return retryMaxDelay
+		}
+		wait = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(value); err == nil {
+		wait = date.Sub(now)
+	}
+
+	if wait <= 0 {
+		return 0
+	}
+	return min(wait, retryMaxDelay)
+}
@@ -0,0 +1,54 @@
+package ai
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	date := func(d time.Duration) string { return now.Add(d).Format(http.TimeFormat) }
+
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+	}{
+		{"missing", "", 0},
+		{"seconds", "5", 5 * time.Second},
+		{"seconds with spaces", " 5 ", 5 * time.Second},
+		{"zero seconds", "0", 0},
+		{"negative seconds", "-5", 0},
+		{"seconds past the maximum", "3600", retryMaxDelay},
+		{"seconds overflowing a duration", "99999999999999999", retryMaxDelay},
+		{"seconds out of range", "999999999999999999999", 0},
+		{"HTTP date", date(10 * time.Second), 10 * time.Second},
+		{"HTTP date now", date(0), 0},
+		{"HTTP date in the past", date(-time.Hour), 0},
+		{"HTTP date past the maximum", date(time.Hour), retryMaxDelay},
+		{"RFC 850 date", now.Add(20 * time.Second).Format(time.RFC850), 20 * time.Second},
+		{"ANSI C date", now.Add(20 * time.Second).Format(time.ANSIC), 20 * time.Second},
+		{"fractional seconds", "1.5", 0},
+		{"invalid", "soon", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryAfter(tt.value, now); got != tt.want {
+				t.Errorf("retryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackoffStaysWithinTheMaximum(t *testing.T) {
+	if got := backoff(1); got < retryBaseDelay/2 || got >= retryBaseDelay {
+		t.Errorf("backoff(1) = %v, want within [%v, %v)", got, retryBaseDelay/2, retryBaseDelay)
+	}
+	// Shifting the delay this far overflows; it must still be capped
+	for _, attempt := range []int{6, 10, 64, 70} {
+		if got := backoff(attempt); got < retryMaxDelay/2 || got >= retryMaxDelay {
+			t.Errorf("backoff(%d) = %v, want within [%v, %v)", attempt, got, retryMaxDelay/2, retryMaxDelay)
+		}
+	}
+}
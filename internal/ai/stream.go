@@ -0,0 +1,206 @@
+package ai
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// StreamCommitMessage generates a commit message like GenerateCommitMessage,
+// calling onText with each piece of the raw message as the provider streams
+// it. Only the OpenAI-compatible and Anthropic providers stream; the others
+// never call onText. The returned message is post-processed and may differ
+// from the streamed text.
+func (c *Client) StreamCommitMessage(ctx context.Context, req CommitRequest, onText func(text string)) (string, error) {
+	c.onText = onText
+	defer func() { c.onText = nil }()
+	return c.GenerateCommitMessage(ctx, req)
+}
+
+// streaming reports whether requests should be streamed
+func (c *Client) streaming() bool {
+	return c.onText != nil
+}
+
+// isEventStream reports whether a response is a successful server-sent
+// events stream; errors come back as plain JSON
+func isEventStream(resp *http.Response) bool {
+	return resp.StatusCode == http.StatusOK &&
+		strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream")
+}
+
+// readEvents parses a server-sent events stream, calling handle with each
+// event's name and data. Comment lines are skipped.
+func readEvents(r io.Reader, handle func(event, data string) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var event string
+	var data []string
+	dispatch := func() error {
+		if len(data) == 0 {
+			event = ""
+			return nil
+		}
+		err := handle(event, strings.Join(data, "\n"))
+		event, data = "", nil
+		return err
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch {
+		case line == "":
+			if err := dispatch(); err != nil {
+				return err
+			}
+		case field == "event":
+			event = value
+		case field == "data":
+			data = append(data, value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read stream: %w", err)
+	}
+	return dispatch()
+}
+
+// errStreamDone stops reading a stream at OpenAI's [DONE] marker
+var errStreamDone = errors.New("stream done")
+
+type openAIStreamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+type openAIStreamChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+			Refusal string `json:"refusal"`
+		} `json:"delta"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+	Usage *struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// readOpenAIStream collects a streamed Chat Completions response
+func (c *Client) readOpenAIStream(body io.Reader) (string, error) {
+	var text, refusal strings.Builder
+	filtered := false
+	err := readEvents(body, func(event, data string) error {
+		if data == "[DONE]" {
+			return errStreamDone
+		}
+
+		var chunk openAIStreamChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return fmt.Errorf("failed to parse response: %w", err)
+		}
+		if chunk.Error != nil {
+			return fmt.Errorf("API error: %s", chunk.Error.Message)
+		}
+		if chunk.Usage != nil {
+			c.usage.add(chunk.Usage.PromptTokens, chunk.Usage.CompletionTokens)
+		}
+		for _, choice := range chunk.Choices {
+			if choice.Delta.Content != "" {
+				text.WriteString(choice.Delta.Content)
+				c.onText(choice.Delta.Content)
+			}
+			refusal.WriteString(choice.Delta.Refusal)
+			if choice.FinishReason == "content_filter" {
+				filtered = true
+			}
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errStreamDone) {
+		return "", err
+	}
+
+	switch {
+	case refusal.Len() > 0:
+		return "", fmt.Errorf("%w: %s", ErrRefusal, refusal.String())
+	case filtered:
+		return "", fmt.Errorf("%w: content_filter", ErrRefusal)
+	case text.Len() == 0:
+		return "", errors.New("no response from API")
+	}
+	return strings.TrimSpace(text.String()), nil
+}
+
+type anthropicStreamEvent struct {
+	Message struct {
+		Usage struct {
+			InputTokens int `json:"input_tokens"`
+		} `json:"usage"`
+	} `json:"message"`
+	Delta struct {
+		Type       string `json:"type"`
+		Text       string `json:"text"`
+		StopReason string `json:"stop_reason"`
+	} `json:"delta"`
+	Usage struct {
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+	Error *struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// readAnthropicStream collects a streamed Messages response
+func (c *Client) readAnthropicStream(body io.Reader) (string, error) {
+	var text strings.Builder
+	var stopReason string
+	err := readEvents(body, func(event, data string) error {
+		var ev anthropicStreamEvent
+		if err := json.Unmarshal([]byte(data), &ev); err != nil {
+			return fmt.Errorf("failed to parse response: %w", err)
+		}
+
+		switch event {
+		case "message_start":
+			c.usage.add(ev.Message.Usage.InputTokens, 0)
+		case "content_block_delta":
+			if ev.Delta.Type == "text_delta" {
+				text.WriteString(ev.Delta.Text)
+				c.onText(ev.Delta.Text)
+			}
+		case "message_delta":
+			// output_tokens is the running total for the message
+			c.usage.add(0, ev.Usage.OutputTokens)
+			stopReason = ev.Delta.StopReason
+		case "error":
+			if ev.Error != nil {
+				return fmt.Errorf("API error: %s", ev.Error.Message)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if stopReason == "refusal" {
+		return "", ErrRefusal
+	}
+	if text.Len() == 0 {
+		return "", errors.New("no response from API")
+	}
+	return strings.TrimSpace(text.String()), nil
+}
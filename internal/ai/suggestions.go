@@ -0,0 +1,101 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Suggestion is a commit message proposed by the model with its own confidence score
+type Suggestion struct {
+	Message    string  `json:"message"`
+	Confidence float64 `json:"confidence"`
+}
+
+// GenerateRankedSuggestions asks the model for n commit messages in a single call,
+// each with a confidence score, and returns them sorted by confidence (highest first).
+// If the response can't be parsed, it falls back to generating a single message.
+func (c *Client) GenerateRankedSuggestions(ctx context.Context, req CommitRequest, n int) ([]Suggestion, error) {
+	if req.Diff == "" && len(req.Notes) == 0 {
+		return nil, errors.New("no diff provided")
+	}
+	if n < 2 {
+		message, err := c.GenerateCommitMessage(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return []Suggestion{{Message: message, Confidence: 1}}, nil
+	}
+
+	ctx = c.withModelFor(ctx, req)
+	prompt := c.buildPrompt(req, rankedResponseFormat(n))
+
+	response, err := c.complete(ctx, prompt, c.completionBudget(c.bulletBody, n))
+	if err == nil {
+		if suggestions, parseErr := parseSuggestions(response); parseErr == nil {
+			for i := range suggestions {
+				suggestions[i].Message = c.postProcess(req, suggestions[i].Message)
+			}
+			return suggestions, nil
+		}
+	} else if !errors.Is(err, ErrRefusal) {
+		return nil, err
+	}
+
+	// Fall back to single-message mode
+	message, err := c.GenerateCommitMessage(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return []Suggestion{{Message: message, Confidence: 1}}, nil
+}
+
+func rankedResponseFormat(n int) string {
+	return fmt.Sprintf(`6. Each suggestion must be a complete commit message on its own
+7. Do NOT include any explanation outside the JSON
+
+Respond with ONLY a JSON array of exactly %d distinct suggestions, ranked from best to worst:
+[{"message": "type(scope): description", "confidence": 0.9}, ...]
+"confidence" is a number between 0 and 1 expressing how well the message describes the diff.`, n)
+}
+
+// parseSuggestions parses and validates a JSON array of suggestions from a model response
+func parseSuggestions(response string) ([]Suggestion, error) {
+	start := strings.Index(response, "[")
+	end := strings.LastIndex(response, "]")
+	if start < 0 || end <= start {
+		return nil, errors.New("no JSON array in response")
+	}
+
+	var raw []Suggestion
+	if err := json.Unmarshal([]byte(response[start:end+1]), &raw); err != nil {
+		return nil, fmt.Errorf("invalid suggestions JSON: %w", err)
+	}
+
+	var suggestions []Suggestion
+	seen := make(map[string]bool)
+	for _, s := range raw {
+		s.Message = strings.TrimSpace(s.Message)
+		if s.Message == "" || seen[s.Message] || IsRefusal(s.Message) {
+			continue
+		}
+		if s.Confidence < 0 || s.Confidence > 1 {
+			return nil, fmt.Errorf("confidence out of range for %q: %v", s.Message, s.Confidence)
+		}
+		seen[s.Message] = true
+		suggestions = append(suggestions, s)
+	}
+
+	if len(suggestions) == 0 {
+		return nil, errors.New("no usable suggestions in response")
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Confidence > suggestions[j].Confidence
+	})
+
+	return suggestions, nil
+}
@@ -0,0 +1,58 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// GenerateChangeSummary writes a short plain-text summary of a diff, suitable
+// for a ticket description. Bullets start with "- ".
+func (c *Client) GenerateChangeSummary(ctx context.Context, diff string) (string, error) {
+	if diff == "" {
+		return "", errors.New("no diff provided")
+	}
+
+	prompt := fmt.Sprintf(`Summarize the following code changes for a project tracking ticket.
+
+Git Diff:
+%s
+
+Rules:
+1. Start with one sentence describing the purpose of the change
+2. Follow with up to 5 bullet points (starting with "- ") covering the main changes
+3. Write for teammates who haven't seen the code; avoid line-level detail
+4. Use plain text only, no Markdown headings or code blocks
+%s
+Respond with ONLY the summary.`, c.truncateDiff(diff), c.languageRule(false))
+
+	return c.generate(ctx, prompt, c.completionBudget(true, 1))
+}
+
+// GeneratePullRequestSummary writes a pull request description of a diff
+// that also explains how the change meets the issue's requirements
+// (description and acceptance criteria)
+func (c *Client) GeneratePullRequestSummary(ctx context.Context, diff, requirements string) (string, error) {
+	if diff == "" {
+		return "", errors.New("no diff provided")
+	}
+
+	prompt := fmt.Sprintf(`Summarize the following code changes for a pull request description.
+
+Issue requirements:
+%s
+
+Git Diff:
+%s
+
+Rules:
+1. Start with one sentence describing the purpose of the change
+2. Follow with up to 5 bullet points (starting with "- ") covering the main changes
+3. End with "Acceptance criteria:" and one bullet per criterion saying how the change meets it, or that it isn't addressed yet
+4. Only claim what the diff shows; don't invent tests or behavior
+5. Use plain text only, no Markdown headings or code blocks
+%s
+Respond with ONLY the summary.`, requirements, c.truncateDiff(diff), c.languageRule(false))
+
+	return c.generate(ctx, prompt, c.completionBudget(true, 1))
+}
@@ -0,0 +1,66 @@
+package ai
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// PromptData is the data available to a commit prompt template
+type PromptData struct {
+	// Diff is the staged diff, truncated to fit the model's context window
+	Diff string
+	// Files are the paths of the changed files
+	Files []string
+	// Branch is the current branch, if known
+	Branch string
+	// Notes are facts about the change the diff doesn't show well, e.g.
+	// submodule updates or the linked Jira issue's summary
+	Notes []string
+	// Breaking is set when a breaking change message was asked for, with
+	// any detected API breaks in BreakingChanges
+	Breaking        bool
+	BreakingChanges []string
+	// Style is the commit message convention, "conventional" or "gitmoji"
+	Style string
+	// Language is the language to write the message in; empty for English
+	Language string
+}
+
+// promptFuncs are the functions available to prompt templates in addition
+// to the text/template builtins
+var promptFuncs = template.FuncMap{
+	"join": strings.Join,
+}
+
+// renderPromptTemplate builds the commit prompt from the template file
+func (c *Client) renderPromptTemplate(req CommitRequest) (string, error) {
+	text, err := os.ReadFile(c.promptTemplate)
+	if err != nil {
+		return "", fmt.Errorf("failed to read commit_prompt_template: %w", err)
+	}
+
+	tmpl, err := template.New(filepath.Base(c.promptTemplate)).Funcs(promptFuncs).Parse(string(text))
+	if err != nil {
+		return "", fmt.Errorf("invalid commit_prompt_template: %w", err)
+	}
+
+	model, _ := c.ModelFor(req)
+	data := PromptData{
+		Diff:            c.truncateDiffFor(model, req.Diff),
+		Files:           req.Files,
+		Branch:          req.Branch,
+		Notes:           req.Notes,
+		Breaking:        req.Breaking,
+		BreakingChanges: req.BreakingChanges,
+		Style:           string(c.style),
+		Language:        c.language,
+	}
+	var prompt strings.Builder
+	if err := tmpl.Execute(&prompt, data); err != nil {
+		return "", fmt.Errorf("invalid commit_prompt_template: %w", err)
+	}
+	return prompt.String(), nil
+}
@@ -0,0 +1,85 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ModelTier selects a model for diffs up to a size
+type ModelTier struct {
+	// MaxLines is the largest number of changed lines this tier handles;
+	// zero means no limit
+	MaxLines int
+	Model    string
+}
+
+// ModelFor returns the model used for a commit request and a description of
+// why it was chosen. Tiers are checked from smallest to largest; diffs that
+// exceed every tier use the client's default model.
+func (c *Client) ModelFor(req CommitRequest) (string, string) {
+	if len(c.tiers) == 0 {
+		return c.model, "default"
+	}
+
+	lines := changedLines(req.Diff)
+	for _, tier := range c.tiers {
+		if tier.MaxLines == 0 || lines <= tier.MaxLines {
+			if tier.MaxLines == 0 {
+				return tier.Model, fmt.Sprintf("tier for any size, %d changed lines", lines)
+			}
+			return tier.Model, fmt.Sprintf("tier ≤%d lines, %d changed lines", tier.MaxLines, lines)
+		}
+	}
+	return c.model, fmt.Sprintf("default, %d changed lines exceeds all tiers", lines)
+}
+
+// modelKey is the context key of the model a request goes to
+type modelKey struct{}
+
+// withModelFor returns a context whose requests go to the tier model for
+// req; the client's configured model is left as it is for other requests
+func (c *Client) withModelFor(ctx context.Context, req CommitRequest) context.Context {
+	model, _ := c.ModelFor(req)
+	return context.WithValue(ctx, modelKey{}, model)
+}
+
+// RequestModel returns the model requests made with ctx go to: the tier
+// model chosen for a commit request, or the configured model
+func (c *Client) RequestModel(ctx context.Context) string {
+	if model, ok := ctx.Value(modelKey{}).(string); ok && model != "" {
+		return model
+	}
+	return c.model
+}
+
+// sortTiers orders tiers by size with the unlimited tier last
+func sortTiers(tiers []ModelTier) []ModelTier {
+	var sorted []ModelTier
+	for _, t := range tiers {
+		if t.Model != "" {
+			sorted = append(sorted, t)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i].MaxLines, sorted[j].MaxLines
+		if a == 0 || b == 0 {
+			return b == 0 && a != 0
+		}
+		return a < b
+	})
+	return sorted
+}
+
+// changedLines counts added and removed lines in a diff
+func changedLines(diff string) int {
+	n := 0
+	for _, line := range strings.Split(diff, "\n") {
+		if (strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "+++")) ||
+			(strings.HasPrefix(line, "-") && !strings.HasPrefix(line, "---")) {
+			n++
+		}
+	}
+	return n
+}
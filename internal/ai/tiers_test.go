@@ -0,0 +1,77 @@
+package ai
+
+import (
+	"context"
+	"testing"
+)
+
+func TestModelTiersDontChangeTheDefault(t *testing.T) {
+	var models []string
+	client := newTestClient(Config{
+		Model:      "gpt-4o",
+		ModelTiers: []ModelTier{{MaxLines: 10, Model: "gpt-4o-mini"}},
+	}, func(ctx context.Context, c *Client, prompt string) (string, error) {
+		models = append(models, c.RequestModel(ctx))
+		c.AddUsage(1000000, 0)
+		return "feat: add lines", nil
+	})
+
+	ctx := context.Background()
+	for _, lines := range []int{3, 50, 3} {
+		if _, err := client.GenerateCommitMessage(ctx, CommitRequest{Diff: testDiff(lines)}); err != nil {
+			t.Fatalf("GenerateCommitMessage() error = %v", err)
+		}
+	}
+
+	want := []string{"gpt-4o-mini", "gpt-4o", "gpt-4o-mini"}
+	if len(models) != len(want) {
+		t.Fatalf("requested models = %v, want %v", models, want)
+	}
+	for i := range want {
+		if models[i] != want[i] {
+			t.Errorf("request %d went to %s, want %s", i, models[i], want[i])
+		}
+	}
+	if got := client.Model(); got != "gpt-4o" {
+		t.Errorf("Model() = %s after tiered requests, want gpt-4o", got)
+	}
+	if got := client.RequestModel(ctx); got != "gpt-4o" {
+		t.Errorf("RequestModel() outside a commit request = %s, want gpt-4o", got)
+	}
+
+	// Each million input tokens is priced with the model that used them
+	spend := client.Spend()
+	if want := 0.15 + 2.50 + 0.15; spend.CostUSD < want-1e-9 || spend.CostUSD > want+1e-9 {
+		t.Errorf("Spend().CostUSD = %g, want %g", spend.CostUSD, want)
+	}
+	if len(spend.Models) != 2 || spend.Models[0] != "gpt-4o-mini" || spend.Models[1] != "gpt-4o" {
+		t.Errorf("Spend().Models = %v, want [gpt-4o-mini gpt-4o]", spend.Models)
+	}
+}
+
+func TestModelFor(t *testing.T) {
+	client := New(Config{
+		Provider: ProviderOpenAI,
+		Model:    "gpt-4o",
+		ModelTiers: []ModelTier{
+			{MaxLines: 0, Model: "big"},
+			{MaxLines: 10, Model: "small"},
+			{MaxLines: 100, Model: "medium"},
+		},
+	})
+
+	tests := []struct {
+		lines int
+		want  string
+	}{
+		{lines: 1, want: "small"},
+		{lines: 10, want: "small"},
+		{lines: 11, want: "medium"},
+		{lines: 500, want: "big"},
+	}
+	for _, tt := range tests {
+		if got, _ := client.ModelFor(CommitRequest{Diff: testDiff(tt.lines)}); got != tt.want {
+			t.Errorf("ModelFor(%d lines) = %s, want %s", tt.lines, got, tt.want)
+		}
+	}
+}
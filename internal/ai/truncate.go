@@ -0,0 +1,177 @@
+package ai
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/namin2/gh-assistant/internal/patch"
+)
+
+// TruncationStrategy chooses which part of an oversized diff is sent to the model
+type TruncationStrategy string
+
+const (
+	// TruncateHead keeps the start of the diff
+	TruncateHead TruncationStrategy = "head"
+	// TruncateTail keeps the end of the diff
+	TruncateTail TruncationStrategy = "tail"
+	// TruncateBalanced keeps the start and the end, dropping the middle
+	TruncateBalanced TruncationStrategy = "balanced"
+	// TruncateSmart shares the space between files, so one large file can't
+	// crowd out the others, and gives lock files and generated code what is left
+	TruncateSmart TruncationStrategy = "smart"
+)
+
+// TruncationStrategies lists the supported strategies
+var TruncationStrategies = []TruncationStrategy{TruncateHead, TruncateTail, TruncateBalanced, TruncateSmart}
+
+// truncatedMarker marks where diff text was dropped
+const truncatedMarker = "... [diff truncated]"
+
+// TruncateDiff shortens diff to about maxLen bytes using the given strategy.
+// Diffs that already fit are returned unchanged; unknown strategies behave like head.
+// The smart strategy fills the budget with files having the priority
+// extensions first, in the order given.
+func TruncateDiff(diff string, strategy TruncationStrategy, maxLen int, priorityExtensions ...string) string {
+	if len(diff) <= maxLen {
+		return diff
+	}
+
+	switch strategy {
+	case TruncateTail:
+		return truncatedMarker + "\n" + lastLines(diff, maxLen)
+	case TruncateBalanced:
+		half := maxLen / 2
+		return firstLines(diff, half) + "\n" + truncatedMarker + "\n" + lastLines(diff, maxLen-half)
+	case TruncateSmart:
+		return truncateSmart(diff, maxLen, priorityExtensions)
+	default:
+		return diff[:maxLen] + "\n" + truncatedMarker
+	}
+}
+
+// truncateSmart gives every file an equal share of maxLen, passing space that
+// small files don't need on to larger ones. Files with a priority extension
+// are served first, one extension at a time; low-priority files only get
+// space left over after the others; files that get none are listed by name.
+func truncateSmart(diff string, maxLen int, priorityExtensions []string) string {
+	files := patch.Parse(diff)
+	if len(files) == 0 {
+		return diff[:maxLen] + "\n" + truncatedMarker
+	}
+
+	// groups[0..n-1] hold the priority extensions, then the other files,
+	// then the low-priority ones
+	groups := make([][]int, len(priorityExtensions)+2)
+	for i, f := range files {
+		group := len(priorityExtensions)
+		if isLowPriority(f.Path) {
+			group++
+		} else if rank := extensionRank(f.Path, priorityExtensions); rank >= 0 {
+			group = rank
+		}
+		groups[group] = append(groups[group], i)
+	}
+
+	budget := make([]int, len(files))
+	remaining := maxLen
+	for _, group := range groups {
+		remaining = shareBudget(files, group, remaining, budget)
+	}
+
+	var kept []patch.File
+	var omitted []string
+	for i, f := range files {
+		switch {
+		case budget[i] <= 0:
+			omitted = append(omitted, f.Path)
+		case budget[i] < len(f.Text):
+			f.Text = firstLines(f.Text, budget[i]) + "\n" + truncatedMarker + "\n"
+			kept = append(kept, f)
+		default:
+			kept = append(kept, f)
+		}
+	}
+
+	result := patch.Join(kept)
+	if len(omitted) > 0 {
+		result += fmt.Sprintf("\n... [diff of %d more file(s) omitted: %s]", len(omitted), strings.Join(omitted, ", "))
+	}
+	return result
+}
+
+// firstLines returns the whole lines that fit in the first n bytes of s,
+// or the first n bytes if not even one line fits
+func firstLines(s string, n int) string {
+	cut := s[:n]
+	if i := strings.LastIndex(cut, "\n"); i > 0 {
+		return cut[:i]
+	}
+	return cut
+}
+
+// lastLines returns the whole lines that fit in the last n bytes of s,
+// or the last n bytes if not even one line fits
+func lastLines(s string, n int) string {
+	cut := s[len(s)-n:]
+	if i := strings.Index(cut, "\n"); i >= 0 && i < len(cut)-1 {
+		return cut[i+1:]
+	}
+	return cut
+}
+
+// shareBudget splits available between the files at indexes, smallest first,
+// recording each allocation in budget. It returns the unused remainder.
+func shareBudget(files []patch.File, indexes []int, available int, budget []int) int {
+	order := append([]int(nil), indexes...)
+	sort.SliceStable(order, func(a, b int) bool {
+		return len(files[order[a]].Text) < len(files[order[b]].Text)
+	})
+
+	for n, i := range order {
+		share := available / (len(order) - n)
+		if size := len(files[i].Text); size < share {
+			share = size
+		}
+		budget[i] = share
+		available -= share
+	}
+	return available
+}
+
+// extensionRank returns the index of the first extension ("go" or ".go") the
+// file name ends with, or -1
+func extensionRank(filePath string, extensions []string) int {
+	base := strings.ToLower(path.Base(filePath))
+	for i, ext := range extensions {
+		ext = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(ext), "."))
+		if ext != "" && strings.HasSuffix(base, "."+ext) {
+			return i
+		}
+	}
+	return -1
+}
+
+// lowPriorityFiles are file names whose diffs rarely explain a change
+var lowPriorityFiles = map[string]bool{
+	"go.sum":            true,
+	"package-lock.json": true,
+	"yarn.lock":         true,
+	"pnpm-lock.yaml":    true,
+	"Cargo.lock":        true,
+	"Gemfile.lock":      true,
+	"poetry.lock":       true,
+	"composer.lock":     true,
+}
+
+// isLowPriority reports whether a file is a lock file or generated code
+func isLowPriority(filePath string) bool {
+	base := path.Base(filePath)
+	return lowPriorityFiles[base] ||
+		strings.HasSuffix(base, ".min.js") ||
+		strings.HasSuffix(base, ".min.css") ||
+		strings.HasSuffix(base, ".pb.go") ||
+		strings.HasSuffix(base, "_generated.go")
+}
@@ -0,0 +1,158 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// fileDiff returns a diff of one file adding n lines, each naming the file
+func fileDiff(path string, n int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "diff --git a/%s b/%s\n--- a/%s\n+++ b/%s\n@@ -1,0 +1,%d @@\n", path, path, path, path, n)
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "+%s line %d\n", path, i)
+	}
+	return b.String()
+}
+
+func TestTruncateDiffKeepsDiffsThatFit(t *testing.T) {
+	diff := testDiff(10)
+	for _, strategy := range append(TruncationStrategies, "unknown") {
+		if got := TruncateDiff(diff, strategy, len(diff)); got != diff {
+			t.Errorf("%s: diff that fits was changed to %q", strategy, got)
+		}
+	}
+}
+
+func TestTruncateDiffStrategies(t *testing.T) {
+	diff := testDiff(1000)
+	const maxLen = 2000
+
+	tests := []struct {
+		strategy TruncationStrategy
+		keep     []string
+		drop     []string
+	}{
+		{TruncateHead, []string{"+++ b/main.go", "+line 0\n"}, []string{"+line 500\n", "+line 999\n"}},
+		{TruncateTail, []string{"+line 999\n"}, []string{"+++ b/main.go", "+line 0\n", "+line 500\n"}},
+		{TruncateBalanced, []string{"+++ b/main.go", "+line 0\n", "+line 999\n"}, []string{"+line 500\n"}},
+		{"unknown", []string{"+++ b/main.go", "+line 0\n"}, []string{"+line 500\n", "+line 999\n"}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.strategy), func(t *testing.T) {
+			got := TruncateDiff(diff, tt.strategy, maxLen)
+			if !strings.Contains(got, truncatedMarker) {
+				t.Errorf("result has no %q marker", truncatedMarker)
+			}
+			if limit := maxLen + 2*len(truncatedMarker); len(got) > limit {
+				t.Errorf("result is %d bytes, want at most %d", len(got), limit)
+			}
+			for _, s := range tt.keep {
+				if !strings.Contains(got, s) {
+					t.Errorf("result is missing %q", s)
+				}
+			}
+			for _, s := range tt.drop {
+				if strings.Contains(got, s) {
+					t.Errorf("result still has %q", s)
+				}
+			}
+		})
+	}
+}
+
+func TestTruncateDiffCutsWholeLines(t *testing.T) {
+	diff := testDiff(1000)
+	for _, strategy := range []TruncationStrategy{TruncateTail, TruncateBalanced} {
+		got := TruncateDiff(diff, strategy, 2001)
+		for _, line := range strings.Split(got, "\n") {
+			if line != "" && line != truncatedMarker && !strings.HasPrefix(line, "+line ") &&
+				!strings.HasPrefix(line, "diff ") && !strings.HasPrefix(line, "--- ") &&
+				!strings.HasPrefix(line, "+++ ") && !strings.HasPrefix(line, "@@ ") {
+				t.Errorf("%s: result has partial line %q", strategy, line)
+			}
+		}
+	}
+}
+
+func TestTruncateDiffSmart(t *testing.T) {
+	small := fileDiff("small.go", 3)
+	mainGo := fileDiff("main.go", 10)
+	tests := []struct {
+		name       string
+		diff       string
+		priorities []string
+		// whole holds the diffs of files that must be kept completely; cut
+		// and omitted name files kept partly and only by name
+		whole, cut, omitted []string
+	}{
+		{
+			name:  "small files aren't crowded out",
+			diff:  fileDiff("big.go", 500) + small,
+			whole: []string{small},
+			cut:   []string{"big.go"},
+		},
+		{
+			name: "large files share the space",
+			diff: fileDiff("a.go", 500) + fileDiff("b.md", 500),
+			cut:  []string{"a.go", "b.md"},
+		},
+		{
+			name:       "priority extensions go first",
+			diff:       fileDiff("b.md", 500) + fileDiff("a.go", 500),
+			priorities: []string{".go"},
+			cut:        []string{"a.go"},
+			omitted:    []string{"b.md"},
+		},
+		{
+			name:    "lock files get what is left",
+			diff:    fileDiff("go.sum", 500) + fileDiff("main.go", 500),
+			cut:     []string{"main.go"},
+			omitted: []string{"go.sum"},
+		},
+		{
+			name:  "generated code gets what is left",
+			diff:  fileDiff("api.pb.go", 500) + mainGo,
+			whole: []string{mainGo},
+			cut:   []string{"api.pb.go"},
+		},
+	}
+	const maxLen = 3000
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TruncateDiff(tt.diff, TruncateSmart, maxLen, tt.priorities...)
+			if limit := maxLen + 200; len(got) > limit {
+				t.Errorf("result is %d bytes, want at most %d", len(got), limit)
+			}
+			for _, f := range tt.whole {
+				if !strings.Contains(got+"\n", f) {
+					t.Errorf("%q wasn't kept whole:\n%s", f, got)
+				}
+			}
+			for _, f := range tt.cut {
+				if !strings.Contains(got, "+"+f+" line 0\n") || strings.Contains(got, "+"+f+" line 499\n") {
+					t.Errorf("%s wasn't cut:\n%s", f, got)
+				}
+			}
+			for _, f := range tt.omitted {
+				if strings.Contains(got, "+"+f+" line 0\n") {
+					t.Errorf("%s wasn't omitted", f)
+				}
+			}
+			if len(tt.omitted) > 0 {
+				want := fmt.Sprintf("[diff of %d more file(s) omitted: %s]", len(tt.omitted), strings.Join(tt.omitted, ", "))
+				if !strings.HasSuffix(got, want) {
+					t.Errorf("result doesn't end with %q", want)
+				}
+			}
+		})
+	}
+}
+
+func TestTruncateDiffSmartWithoutFileHeaders(t *testing.T) {
+	diff := strings.Repeat("x", 100)
+	if got, want := TruncateDiff(diff, TruncateSmart, 10), diff[:10]+"\n"+truncatedMarker; got != want {
+		t.Errorf("TruncateDiff() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,164 @@
+package ai
+
+import (
+	"slices"
+	"strings"
+)
+
+// Usage records the tokens consumed by a client's API calls
+type Usage struct {
+	InputTokens  int
+	OutputTokens int
+}
+
+func (u *Usage) add(input, output int) {
+	u.InputTokens += input
+	u.OutputTokens += output
+}
+
+// Spend is the estimated cost of a client's API calls, each priced with the
+// model it went to
+type Spend struct {
+	CostUSD float64
+	// UnpricedCalls counts calls to models whose pricing is unknown
+	UnpricedCalls int
+	// Models are the models called, in order of first use
+	Models []string
+}
+
+// modelPrice is the USD price per million tokens
+type modelPrice struct {
+	input  float64
+	output float64
+}
+
+// modelPrices maps model name prefixes to their list prices.
+// Longer prefixes win, so "gpt-4o-mini" is matched before "gpt-4o".
+var modelPrices = map[string]modelPrice{
+	"gpt-4o-mini":       {input: 0.15, output: 0.60},
+	"gpt-4o":            {input: 2.50, output: 10.00},
+	"gpt-4-turbo":       {input: 10.00, output: 30.00},
+	"gpt-4":             {input: 30.00, output: 60.00},
+	"gpt-3.5-turbo":     {input: 0.50, output: 1.50},
+	"claude-3-5-sonnet": {input: 3.00, output: 15.00},
+	"claude-3-5-haiku":  {input: 0.80, output: 4.00},
+	"claude-3-opus":     {input: 15.00, output: 75.00},
+	"claude-3-sonnet":   {input: 3.00, output: 15.00},
+	"claude-3-haiku":    {input: 0.25, output: 1.25},
+	"gemini-1.5-flash":  {input: 0.075, output: 0.30},
+	"gemini-1.5-pro":    {input: 1.25, output: 5.00},
+	"gemini-2.0-flash":  {input: 0.10, output: 0.40},
+
+	// Mistral AI
+	"mistral-small":     {input: 0.20, output: 0.60},
+	"mistral-large":     {input: 2.00, output: 6.00},
+	"open-mistral-nemo": {input: 0.15, output: 0.15},
+	"codestral":         {input: 0.20, output: 0.60},
+	"ministral-8b":      {input: 0.10, output: 0.10},
+	"ministral-3b":      {input: 0.04, output: 0.04},
+
+	// Amazon Titan on Bedrock
+	"titan-text-lite":    {input: 0.15, output: 0.20},
+	"titan-text-express": {input: 0.20, output: 0.60},
+	"titan-text-premier": {input: 0.50, output: 1.50},
+}
+
+// lookupPrice finds the price for a model by longest matching prefix
+func lookupPrice(model string) (modelPrice, bool) {
+	return lookupByPrefix(modelPrices, model)
+}
+
+// lookupByPrefix returns the value of the longest key in table that
+// prefixes model
+func lookupByPrefix[T any](table map[string]T, model string) (T, bool) {
+	var best string
+	for prefix := range table {
+		if strings.HasPrefix(model, prefix) && len(prefix) > len(best) {
+			best = prefix
+		}
+	}
+	value, ok := table[best]
+	return value, ok && best != ""
+}
+
+// EstimateCost returns the USD cost of the given usage for a model.
+// The second return value is false if the model's pricing is unknown.
+func EstimateCost(model string, u Usage) (float64, bool) {
+	price, ok := lookupPrice(model)
+	if !ok {
+		return 0, false
+	}
+	return (float64(u.InputTokens)*price.input + float64(u.OutputTokens)*price.output) / 1e6, true
+}
+
+// EstimateCost returns the USD cost of the given usage with the client's
+// model; local providers and free OpenRouter variants cost nothing
+func (c *Client) EstimateCost(u Usage) (float64, bool) {
+	return c.estimateCost(c.model, u)
+}
+
+// estimateCost returns the USD cost of the given usage with a model
+func (c *Client) estimateCost(model string, u Usage) (float64, bool) {
+	if c.provider.Local() || c.provider == ProviderOpenRouter && openRouterFree(model) {
+		return 0, true
+	}
+	return EstimateCost(c.baseModel(model), u)
+}
+
+// baseModel returns the model name used to look up prices and context
+// windows, without the vendor of Bedrock and OpenRouter model IDs
+func (c *Client) baseModel(model string) string {
+	switch c.provider {
+	case ProviderBedrock:
+		return bedrockBaseModel(model)
+	case ProviderOpenRouter:
+		return openRouterBaseModel(model)
+	}
+	return model
+}
+
+// charsPerToken is the rough number of characters in a token
+const charsPerToken = 4
+
+// estimateTokens approximates the token count of text
+func estimateTokens(text string) int {
+	return (len(text) + charsPerToken - 1) / charsPerToken
+}
+
+// Model returns the configured model; commit requests go to the tier model
+// for their diff when model tiers are configured (see RequestModel)
+func (c *Client) Model() string {
+	return c.model
+}
+
+// Usage returns the total tokens consumed by this client so far
+func (c *Client) Usage() Usage {
+	return c.usage
+}
+
+// Spend returns the estimated cost of this client's calls so far
+func (c *Client) Spend() Spend {
+	spend := c.spend
+	spend.Models = append([]string(nil), c.spend.Models...)
+	return spend
+}
+
+// addSpend prices the usage of a call to model since before
+func (c *Client) addSpend(model string, before Usage) {
+	delta := Usage{
+		InputTokens:  c.usage.InputTokens - before.InputTokens,
+		OutputTokens: c.usage.OutputTokens - before.OutputTokens,
+	}
+	if delta.InputTokens == 0 && delta.OutputTokens == 0 {
+		return
+	}
+
+	if !slices.Contains(c.spend.Models, model) {
+		c.spend.Models = append(c.spend.Models, model)
+	}
+	cost, known := c.estimateCost(model, delta)
+	c.spend.CostUSD += cost
+	if !known {
+		c.spend.UnpricedCalls++
+	}
+}
@@ -0,0 +1,89 @@
+package anonymize
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// identifierPattern matches identifier-like words
+var identifierPattern = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*`)
+
+// placeholderPattern matches the placeholders produced by an Anonymizer
+var placeholderPattern = regexp.MustCompile(`\bid_[0-9]+\b`)
+
+// keep lists language keywords, common builtins and diff syntax that carry
+// no project-specific information and help the model read the code
+var keep = toSet(`
+	break case chan const continue default defer else fallthrough for func go goto if
+	import interface map package range return select struct switch type var
+	bool byte complex64 complex128 error float32 float64 int int8 int16 int32 int64
+	rune string uint uint8 uint16 uint32 uint64 uintptr any true false nil iota
+	append cap close copy delete len make new panic print println recover err
+	abstract as assert async await catch class def del elif except extends final
+	finally from function global implements in instanceof is lambda let not or and
+	private protected public raise self static super this throw throws try typeof
+	void while with yield None True False null undefined export enum namespace
+	diff git index file mode deleted rename similarity dissimilarity copy to
+	Binary files differ dev Subproject commit
+`)
+
+func toSet(words string) map[string]bool {
+	set := make(map[string]bool)
+	for _, w := range strings.Fields(words) {
+		set[w] = true
+	}
+	return set
+}
+
+// Anonymizer consistently replaces identifiers with placeholders such as
+// "id_1", so the same name always maps to the same placeholder, and can
+// restore the original names in text that uses the placeholders
+type Anonymizer struct {
+	forward map[string]string
+	reverse map[string]string
+}
+
+// New creates an Anonymizer with an empty mapping
+func New() *Anonymizer {
+	return &Anonymizer{
+		forward: make(map[string]string),
+		reverse: make(map[string]string),
+	}
+}
+
+// Anonymize replaces every identifier in text except keywords, common
+// builtins and one- or two-letter names with its placeholder
+func (a *Anonymizer) Anonymize(text string) string {
+	return identifierPattern.ReplaceAllStringFunc(text, func(word string) string {
+		if len(word) <= 2 || keep[word] {
+			return word
+		}
+		if placeholder, ok := a.forward[word]; ok {
+			return placeholder
+		}
+		placeholder := fmt.Sprintf("id_%d", len(a.forward)+1)
+		a.forward[word] = placeholder
+		a.reverse[placeholder] = word
+		return placeholder
+	})
+}
+
+// AnonymizeAll anonymizes each string in list
+func (a *Anonymizer) AnonymizeAll(list []string) []string {
+	out := make([]string, len(list))
+	for i, s := range list {
+		out[i] = a.Anonymize(s)
+	}
+	return out
+}
+
+// Restore replaces known placeholders in text with the original identifiers
+func (a *Anonymizer) Restore(text string) string {
+	return placeholderPattern.ReplaceAllStringFunc(text, func(placeholder string) string {
+		if word, ok := a.reverse[placeholder]; ok {
+			return word
+		}
+		return placeholder
+	})
+}
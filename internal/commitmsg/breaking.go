@@ -0,0 +1,105 @@
+package commitmsg
+
+import (
+	"regexp"
+	"strings"
+)
+
+// breakingFooterPattern matches a breaking change footer in the variations
+// models tend to produce, e.g. "BREAKING CHANGE:", "Breaking change -" or
+// "**BREAKING-CHANGES:**"
+var breakingFooterPattern = regexp.MustCompile(`(?i)^\**breaking[ -]changes?\**\s*(?::|-)\s*\**\s*(.*)$`)
+
+// breakingFooterToken is the footer token defined by Conventional Commits
+const breakingFooterToken = "BREAKING CHANGE: "
+
+// MarkBreaking formats a message as a breaking change: the header gets "!"
+// and a breaking change footer in the body is normalized to
+// "BREAKING CHANGE: ..." and moved, with its continuation lines, after the
+// rest of the body. A leading gitmoji is kept. Messages that aren't in
+// conventional commit form keep their subject line.
+func MarkBreaking(message string) string {
+	subject, body := Split(message)
+	emoji := subject[:len(subject)-len(StripLeadingEmoji(subject))]
+	if header, ok := ParseHeader(subject[len(emoji):]); ok {
+		header.Breaking = true
+		subject = emoji + header.String()
+	}
+
+	footer, rest := extractBreakingFooter(body)
+	if footer == "" {
+		return Join(subject, body)
+	}
+
+	// Keep the footer ahead of a closing git trailer block (Signed-off-by, ...)
+	var paragraphs []string
+	if rest != "" {
+		paragraphs = strings.Split(rest, "\n\n")
+	}
+	at := len(paragraphs)
+	if at > 0 && isTrailerBlock(paragraphs[at-1]) {
+		at--
+	}
+	paragraphs = append(paragraphs[:at], append([]string{footer}, paragraphs[at:]...)...)
+	return Join(subject, strings.Join(paragraphs, "\n\n"))
+}
+
+// trailerLinePattern matches a git trailer line such as "Signed-off-by: A <a@b>"
+var trailerLinePattern = regexp.MustCompile(`^[A-Za-z0-9-]+: \S`)
+
+// isTrailerBlock reports whether every line of a paragraph is a git trailer
+func isTrailerBlock(paragraph string) bool {
+	for _, line := range strings.Split(paragraph, "\n") {
+		if !trailerLinePattern.MatchString(line) {
+			return false
+		}
+	}
+	return true
+}
+
+// BreakingFooter returns the description from a message's breaking change
+// footer, or "" if it has none
+func BreakingFooter(message string) string {
+	_, body := Split(message)
+	footer, _ := extractBreakingFooter(body)
+	return strings.TrimPrefix(footer, breakingFooterToken)
+}
+
+// extractBreakingFooter removes the first breaking change footer paragraph
+// from body, returning it in canonical form along with the remaining body
+func extractBreakingFooter(body string) (footer, rest string) {
+	lines := strings.Split(body, "\n")
+	for i, line := range lines {
+		m := breakingFooterPattern.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+
+		// The footer runs to the end of its paragraph
+		end := i + 1
+		for end < len(lines) && strings.TrimSpace(lines[end]) != "" {
+			end++
+		}
+
+		description := []string{strings.TrimSpace(strings.Trim(m[1], "*"))}
+		if description[0] == "" {
+			// Heading-style footer with the description on the next lines
+			description = nil
+		}
+		for _, l := range lines[i+1 : end] {
+			description = append(description, strings.TrimSpace(l))
+		}
+		if len(description) == 0 {
+			continue
+		}
+		footer = breakingFooterToken + strings.Join(description, "\n")
+
+		before := strings.Trim(strings.Join(lines[:i], "\n"), "\n")
+		after := strings.Trim(strings.Join(lines[end:], "\n"), "\n")
+		if before == "" || after == "" {
+			return footer, before + after
+		}
+		return footer, before + "\n\n" + after
+	}
+	return "", body
+}
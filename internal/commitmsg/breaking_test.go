@@ -0,0 +1,108 @@
+package commitmsg
+
+import "testing"
+
+func TestMarkBreaking(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    string
+	}{
+		{
+			name:    "header only",
+			message: "feat(api): drop v1 endpoints",
+			want:    "feat(api)!: drop v1 endpoints",
+		},
+		{
+			name:    "already marked",
+			message: "feat!: drop v1 endpoints\n\nBREAKING CHANGE: use /v2.",
+			want:    "feat!: drop v1 endpoints\n\nBREAKING CHANGE: use /v2.",
+		},
+		{
+			name:    "footer moved to the end",
+			message: "feat: drop v1 endpoints\n\nBREAKING CHANGE: use /v2.\n\nThe v1 endpoints were deprecated.",
+			want:    "feat!: drop v1 endpoints\n\nThe v1 endpoints were deprecated.\n\nBREAKING CHANGE: use /v2.",
+		},
+		{
+			name:    "footer variations normalized",
+			message: "feat: drop v1\n\n**Breaking changes:** clients must\nuse /v2.",
+			want:    "feat!: drop v1\n\nBREAKING CHANGE: clients must\nuse /v2.",
+		},
+		{
+			name:    "dash separator",
+			message: "feat: drop v1\n\nBreaking-change - use /v2.",
+			want:    "feat!: drop v1\n\nBREAKING CHANGE: use /v2.",
+		},
+		{
+			name:    "heading style footer",
+			message: "feat: drop v1\n\nBREAKING CHANGE:\n  Clients must use /v2.",
+			want:    "feat!: drop v1\n\nBREAKING CHANGE: Clients must use /v2.",
+		},
+		{
+			name:    "footer kept ahead of trailers",
+			message: "feat: drop v1\n\nBREAKING CHANGE: use /v2.\n\nWhy: cleanup.\n\nRefs: PROJ-1\nSigned-off-by: A <a@example.com>",
+			want:    "feat!: drop v1\n\nWhy: cleanup.\n\nBREAKING CHANGE: use /v2.\n\nRefs: PROJ-1\nSigned-off-by: A <a@example.com>",
+		},
+		{
+			name:    "gitmoji header",
+			message: "💥 feat: drop v1",
+			want:    "💥 feat!: drop v1",
+		},
+		{
+			name:    "gitmoji shortcode",
+			message: ":boom: feat(api): drop v1",
+			want:    ":boom: feat(api)!: drop v1",
+		},
+		{
+			name:    "not conventional",
+			message: "Drop v1 endpoints\n\nBREAKING CHANGE: use /v2.",
+			want:    "Drop v1 endpoints\n\nBREAKING CHANGE: use /v2.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MarkBreaking(tt.message); got != tt.want {
+				t.Errorf("MarkBreaking() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBreakingFooter(t *testing.T) {
+	tests := []struct {
+		message, want string
+	}{
+		{"feat: add login", ""},
+		{"feat!: drop v1\n\nBREAKING CHANGE: use /v2.", "use /v2."},
+		{"feat!: drop v1\n\nBreaking change: clients must\nuse /v2.\n\nRefs: PROJ-1", "clients must\nuse /v2."},
+		{"feat!: drop v1\n\nThis is not a breaking change: really.", ""},
+		{"feat!: drop v1\n\nBREAKING CHANGE:", ""},
+	}
+	for _, tt := range tests {
+		if got := BreakingFooter(tt.message); got != tt.want {
+			t.Errorf("BreakingFooter(%q) = %q, want %q", tt.message, got, tt.want)
+		}
+	}
+}
+
+func TestIsBreaking(t *testing.T) {
+	tests := []struct {
+		message string
+		want    bool
+	}{
+		{"feat: add login", false},
+		{"feat!: drop v1", true},
+		{"feat(api)!: drop v1", true},
+		{"💥 feat!: drop v1", true},
+		{":boom: feat(api)!: drop v1", true},
+		{"feat: drop v1\n\nBREAKING CHANGE: use /v2.", true},
+		{"feat: drop v1\n\nBREAKING-CHANGE: use /v2.", true},
+		{"feat: drop v1\n\nNot a BREAKING CHANGE: really.", false},
+		{"Wow! Drop v1", false},
+	}
+	for _, tt := range tests {
+		if got := IsBreaking(tt.message); got != tt.want {
+			t.Errorf("IsBreaking(%q) = %v, want %v", tt.message, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package commitmsg
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// headerPattern matches a conventional commit header: type(scope)!: description
+var headerPattern = regexp.MustCompile(`^([A-Za-z]+)(?:\(([^)]*)\))?(!)?: (.*)$`)
+
+// Header is the parsed first line of a conventional commit message
+type Header struct {
+	Type        string
+	Scope       string
+	Breaking    bool
+	Description string
+}
+
+// ParseHeader parses a conventional commit subject line.
+// It returns false if the line isn't in conventional commit form.
+func ParseHeader(subject string) (Header, bool) {
+	m := headerPattern.FindStringSubmatch(strings.TrimSpace(subject))
+	if m == nil {
+		return Header{}, false
+	}
+	return Header{
+		Type:        m[1],
+		Scope:       m[2],
+		Breaking:    m[3] == "!",
+		Description: m[4],
+	}, true
+}
+
+// String formats the header back into a subject line
+func (h Header) String() string {
+	var b strings.Builder
+	b.WriteString(h.Type)
+	if h.Scope != "" {
+		fmt.Fprintf(&b, "(%s)", h.Scope)
+	}
+	if h.Breaking {
+		b.WriteString("!")
+	}
+	b.WriteString(": ")
+	b.WriteString(h.Description)
+	return b.String()
+}
+
+// Split separates a message into its subject line and the remaining body
+func Split(message string) (subject, body string) {
+	parts := strings.SplitN(message, "\n", 2)
+	subject = strings.TrimSpace(parts[0])
+	if len(parts) == 2 {
+		body = strings.Trim(parts[1], "\n")
+	}
+	return subject, body
+}
+
+// Join combines a subject and body with the conventional blank line between them
+func Join(subject, body string) string {
+	if body == "" {
+		return subject
+	}
+	return subject + "\n\n" + body
+}
+
+// SetScope replaces the scope of a conventional commit message.
+// Messages that aren't in conventional commit form are returned unchanged.
+func SetScope(message, scope string) string {
+	subject, body := Split(message)
+	header, ok := ParseHeader(subject)
+	if !ok {
+		return message
+	}
+	header.Scope = scope
+	return Join(header.String(), body)
+}
+
+// emptyScopePattern matches a header with empty scope parentheses, e.g.
+// "feat(): x" or "✨ fix( )!: x"
+var emptyScopePattern = regexp.MustCompile(`^([^:(]*?[A-Za-z]+)\(\s*\)(!?): `)
+
+// NormalizeEmptyScope rewrites an invalid empty scope, "type(): description",
+// to the scope-less form "type: description"
+func NormalizeEmptyScope(message string) string {
+	subject, body := Split(message)
+	if !emptyScopePattern.MatchString(subject) {
+		return message
+	}
+	return Join(emptyScopePattern.ReplaceAllString(subject, "$1$2: "), body)
+}
+
+// HasEmptyScope reports whether a message's header has empty scope parentheses
+func HasEmptyScope(message string) bool {
+	subject, _ := Split(message)
+	return emptyScopePattern.MatchString(subject)
+}
+
+// IsBreaking reports whether a message marks a breaking change, either with
+// "!" in the header (after any gitmoji) or a BREAKING CHANGE footer
+func IsBreaking(message string) bool {
+	subject, body := Split(message)
+	if header, ok := ParseHeader(StripLeadingEmoji(subject)); ok && header.Breaking {
+		return true
+	}
+	for _, line := range strings.Split(body, "\n") {
+		if strings.HasPrefix(line, "BREAKING CHANGE:") || strings.HasPrefix(line, "BREAKING-CHANGE:") {
+			return true
+		}
+	}
+	return false
+}
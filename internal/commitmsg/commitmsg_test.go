@@ -0,0 +1,41 @@
+package commitmsg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeEmptyScope(t *testing.T) {
+	tests := []struct {
+		message string
+		want    string
+		empty   bool
+	}{
+		{"feat(): add login", "feat: add login", true},
+		{"fix( )!: drop v1 API", "fix!: drop v1 API", true},
+		{"✨ feat(): add login", "✨ feat: add login", true},
+		{"feat(): add login\n\nUsers can sign in.", "feat: add login\n\nUsers can sign in.", true},
+		{"feat(auth): add login", "feat(auth): add login", false},
+		{"feat: add login", "feat: add login", false},
+		{"feat: call init() on start", "feat: call init() on start", false},
+		{"feat: add login\n\nfix(): not the header", "feat: add login\n\nfix(): not the header", false},
+		{"Add login()", "Add login()", false},
+	}
+	for _, tt := range tests {
+		if got := NormalizeEmptyScope(tt.message); got != tt.want {
+			t.Errorf("NormalizeEmptyScope(%q) = %q, want %q", tt.message, got, tt.want)
+		}
+		if got := HasEmptyScope(tt.message); got != tt.empty {
+			t.Errorf("HasEmptyScope(%q) = %v, want %v", tt.message, got, tt.empty)
+		}
+	}
+}
+
+func TestValidateRejectsEmptyScope(t *testing.T) {
+	for _, message := range []string{"feat(): add login", "✨ feat( ): add login"} {
+		problems := Validate(message, Rules{AllowEmoji: true})
+		if len(problems) != 1 || !strings.Contains(problems[0], "scope is empty") {
+			t.Errorf("Validate(%q) = %q, want an empty scope problem", message, problems)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package commitmsg
+
+import (
+	"regexp"
+	"strings"
+)
+
+// forbiddenPattern matches any of the words case-insensitively and as whole
+// words, so "hotfix" matches "Hotfix" but not "hotfixes"
+func forbiddenPattern(words []string) *regexp.Regexp {
+	var alternatives []string
+	for _, w := range words {
+		w = strings.TrimSpace(w)
+		if w == "" {
+			continue
+		}
+		alt := regexp.QuoteMeta(w)
+		if isWordByte(w[0]) {
+			alt = `\b` + alt
+		}
+		if isWordByte(w[len(w)-1]) {
+			alt += `\b`
+		}
+		alternatives = append(alternatives, alt)
+	}
+	if len(alternatives) == 0 {
+		return nil
+	}
+	return regexp.MustCompile(`(?i)` + strings.Join(alternatives, "|"))
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
+
+// FindForbidden returns the forbidden words used in the message, as written
+// there, without duplicates
+func FindForbidden(message string, words []string) []string {
+	pattern := forbiddenPattern(words)
+	if pattern == nil {
+		return nil
+	}
+
+	var found []string
+	seen := map[string]bool{}
+	for _, m := range pattern.FindAllString(message, -1) {
+		if key := strings.ToLower(m); !seen[key] {
+			seen[key] = true
+			found = append(found, m)
+		}
+	}
+	return found
+}
+
+// MaskForbidden replaces each forbidden word in the message with asterisks
+func MaskForbidden(message string, words []string) string {
+	pattern := forbiddenPattern(words)
+	if pattern == nil {
+		return message
+	}
+	return pattern.ReplaceAllStringFunc(message, func(m string) string {
+		return strings.Repeat("*", len([]rune(m)))
+	})
+}
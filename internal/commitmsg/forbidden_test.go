@@ -0,0 +1,51 @@
+package commitmsg
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindForbidden(t *testing.T) {
+	words := []string{"hotfix", "JIRA", " ", "C++", "@team"}
+	tests := []struct {
+		message string
+		want    []string
+	}{
+		{"fix: handle empty input", nil},
+		{"fix: Hotfix the sync", []string{"Hotfix"}},
+		{"fix: apply hotfixes", nil},
+		{"fix: hotfix_sync helper", nil},
+		{"fix: sync jira\n\nThe JIRA client dropped a hotfix.", []string{"jira", "hotfix"}},
+		{"feat: port to C++ and c++20", []string{"C++"}},
+		{"chore: ping @team", []string{"@team"}},
+	}
+	for _, tt := range tests {
+		if got := FindForbidden(tt.message, words); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("FindForbidden(%q) = %q, want %q", tt.message, got, tt.want)
+		}
+	}
+}
+
+func TestFindForbiddenWithoutWords(t *testing.T) {
+	for _, words := range [][]string{nil, {""}, {"  "}} {
+		if got := FindForbidden("fix: anything", words); got != nil {
+			t.Errorf("FindForbidden(%q) = %q, want nil", words, got)
+		}
+	}
+}
+
+func TestMaskForbidden(t *testing.T) {
+	words := []string{"hotfix", "naïve"}
+	tests := []struct {
+		message, want string
+	}{
+		{"fix: handle empty input", "fix: handle empty input"},
+		{"fix: Hotfix the hotfixes", "fix: ****** the hotfixes"},
+		{"fix: drop the Naïve check", "fix: drop the ***** check"},
+	}
+	for _, tt := range tests {
+		if got := MaskForbidden(tt.message, words); got != tt.want {
+			t.Errorf("MaskForbidden(%q) = %q, want %q", tt.message, got, tt.want)
+		}
+	}
+}
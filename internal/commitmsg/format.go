@@ -0,0 +1,44 @@
+package commitmsg
+
+import "strings"
+
+// bulletMarkers are the list markers models use at the start of body lines
+var bulletMarkers = []string{"- ", "* ", "• ", "+ "}
+
+// Format normalizes a message into the canonical "subject\n\nbody" shape:
+// exactly one blank line after the subject, no runs of blank lines in the
+// body and no trailing whitespace. If bullet is set ("-" or "*"), list items
+// are rewritten to use it.
+func Format(message, bullet string) string {
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSpace(message), "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if bullet != "" {
+			line = replaceBullet(line, bullet)
+		}
+		// Keep at most one blank line in a row
+		if line == "" && len(lines) > 0 && lines[len(lines)-1] == "" {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+
+	subject := lines[0]
+	body := strings.Trim(strings.Join(lines[1:], "\n"), "\n")
+	return Join(subject, body)
+}
+
+// replaceBullet swaps a leading list marker for bullet, keeping indentation
+func replaceBullet(line, bullet string) string {
+	trimmed := strings.TrimLeft(line, " \t")
+	indent := line[:len(line)-len(trimmed)]
+	for _, marker := range bulletMarkers {
+		if strings.HasPrefix(trimmed, marker) {
+			return indent + bullet + " " + strings.TrimPrefix(trimmed, marker)
+		}
+	}
+	return line
+}
@@ -0,0 +1,30 @@
+package commitmsg
+
+import "testing"
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		bullet  string
+		want    string
+	}{
+		{"subject only", "feat: add login", "", "feat: add login"},
+		{"surrounding whitespace", "\n  feat: add login  \n\n", "", "feat: add login"},
+		{"missing blank line", "feat: add login\n- support SSO", "", "feat: add login\n\n- support SSO"},
+		{"extra blank lines", "feat: add login\n\n\n\nfirst\n\n\n\nsecond\n\n", "", "feat: add login\n\nfirst\n\nsecond"},
+		{"trailing whitespace", "feat: add login \t\r\n\r\nbody  \r\n", "", "feat: add login\n\nbody"},
+		{"bullets kept without a style", "feat: add login\n\n* one\n• two", "", "feat: add login\n\n* one\n• two"},
+		{"wrong bullets", "feat: add login\n\n* one\n• two\n+ three\n- four", "-", "feat: add login\n\n- one\n- two\n- three\n- four"},
+		{"star bullets", "feat: add login\n\n- one\n  - nested", "*", "feat: add login\n\n* one\n  * nested"},
+		{"not a bullet", "feat: add login\n\n-1 is returned\n*emphasis*", "-", "feat: add login\n\n-1 is returned\n*emphasis*"},
+		{"empty", " \n\n ", "-", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Format(tt.message, tt.bullet); got != tt.want {
+				t.Errorf("Format(%q, %q) = %q, want %q", tt.message, tt.bullet, got, tt.want)
+			}
+		})
+	}
+}
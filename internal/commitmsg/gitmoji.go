@@ -0,0 +1,165 @@
+package commitmsg
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// DefaultGitmojiMap maps conventional commit types to gitmoji
+var DefaultGitmojiMap = map[string]string{
+	"feat":     "✨",
+	"fix":      "🐛",
+	"docs":     "📝",
+	"style":    "🎨",
+	"refactor": "♻️",
+	"perf":     "⚡️",
+	"test":     "✅",
+	"build":    "📦️",
+	"ci":       "👷",
+	"chore":    "🔧",
+	"revert":   "⏪️",
+}
+
+// OfficialGitmojis maps the shortcodes of the official gitmoji list
+// (https://gitmoji.dev) to their emoji
+var OfficialGitmojis = map[string]string{
+	":art:":                       "🎨",
+	":zap:":                       "⚡️",
+	":fire:":                      "🔥",
+	":bug:":                       "🐛",
+	":ambulance:":                 "🚑️",
+	":sparkles:":                  "✨",
+	":memo:":                      "📝",
+	":rocket:":                    "🚀",
+	":lipstick:":                  "💄",
+	":tada:":                      "🎉",
+	":white_check_mark:":          "✅",
+	":lock:":                      "🔒️",
+	":closed_lock_with_key:":      "🔐",
+	":bookmark:":                  "🔖",
+	":rotating_light:":            "🚨",
+	":construction:":              "🚧",
+	":green_heart:":               "💚",
+	":arrow_down:":                "⬇️",
+	":arrow_up:":                  "⬆️",
+	":pushpin:":                   "📌",
+	":construction_worker:":       "👷",
+	":chart_with_upwards_trend:":  "📈",
+	":recycle:":                   "♻️",
+	":heavy_plus_sign:":           "➕",
+	":heavy_minus_sign:":          "➖",
+	":wrench:":                    "🔧",
+	":hammer:":                    "🔨",
+	":globe_with_meridians:":      "🌐",
+	":pencil2:":                   "✏️",
+	":poop:":                      "💩",
+	":rewind:":                    "⏪️",
+	":twisted_rightwards_arrows:": "🔀",
+	":package:":                   "📦️",
+	":alien:":                     "👽️",
+	":truck:":                     "🚚",
+	":page_facing_up:":            "📄",
+	":boom:":                      "💥",
+	":bento:":                     "🍱",
+	":wheelchair:":                "♿️",
+	":bulb:":                      "💡",
+	":beers:":                     "🍻",
+	":speech_balloon:":            "💬",
+	":card_file_box:":             "🗃️",
+	":loud_sound:":                "🔊",
+	":mute:":                      "🔇",
+	":busts_in_silhouette:":       "👥",
+	":children_crossing:":         "🚸",
+	":building_construction:":     "🏗️",
+	":iphone:":                    "📱",
+	":clown_face:":                "🤡",
+	":egg:":                       "🥚",
+	":see_no_evil:":               "🙈",
+	":camera_flash:":              "📸",
+	":alembic:":                   "⚗️",
+	":mag:":                       "🔍️",
+	":label:":                     "🏷️",
+	":seedling:":                  "🌱",
+	":triangular_flag_on_post:":   "🚩",
+	":goal_net:":                  "🥅",
+	":dizzy:":                     "💫",
+	":wastebasket:":               "🗑️",
+	":passport_control:":          "🛂",
+	":adhesive_bandage:":          "🩹",
+	":monocle_face:":              "🧐",
+	":coffin:":                    "⚰️",
+	":test_tube:":                 "🧪",
+	":necktie:":                   "👔",
+	":stethoscope:":               "🩺",
+	":bricks:":                    "🧱",
+	":technologist:":              "🧑‍💻",
+	":money_with_wings:":          "💸",
+	":thread:":                    "🧵",
+	":safety_vest:":               "🦺",
+	":airplane:":                  "✈️",
+}
+
+// shortcodePattern matches a leading gitmoji shortcode such as ":sparkles:"
+var shortcodePattern = regexp.MustCompile(`^:[a-z0-9_+-]+:`)
+
+// StripLeadingEmoji removes any emoji or :shortcode: (and surrounding
+// spaces) from the start of a subject line
+func StripLeadingEmoji(subject string) string {
+	subject = shortcodePattern.ReplaceAllString(subject, "")
+	return strings.TrimLeftFunc(subject, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// LeadingGitmoji returns the emoji or :shortcode: a subject line starts
+// with, or "" if it starts with a letter or digit
+func LeadingGitmoji(subject string) string {
+	if code := shortcodePattern.FindString(subject); code != "" {
+		return code
+	}
+	rest := StripLeadingEmoji(subject)
+	return strings.TrimSpace(subject[:len(subject)-len(rest)])
+}
+
+// IsGitmoji reports whether emoji, or its :shortcode:, is on the official
+// gitmoji list or is one of extra. Emoji are compared without variation
+// selectors, which models and editors add or drop inconsistently.
+func IsGitmoji(emoji string, extra ...string) bool {
+	if emoji == "" {
+		return false
+	}
+	emoji = withoutVariation(emoji)
+	for code, official := range OfficialGitmojis {
+		if emoji == withoutVariation(official) || emoji == code {
+			return true
+		}
+	}
+	for _, e := range extra {
+		if emoji == withoutVariation(e) {
+			return true
+		}
+	}
+	return false
+}
+
+// withoutVariation drops the emoji presentation selector (U+FE0F)
+func withoutVariation(emoji string) string {
+	return strings.ReplaceAll(emoji, "\ufe0f", "")
+}
+
+// ApplyGitmoji rewrites the subject's leading emoji to the one mapped to its
+// conventional commit type. Messages whose type has no mapping, or that
+// aren't in conventional commit form, are returned unchanged.
+func ApplyGitmoji(message string, gitmojiMap map[string]string) string {
+	subject, body := Split(message)
+	header, ok := ParseHeader(StripLeadingEmoji(subject))
+	if !ok {
+		return message
+	}
+	emoji, ok := gitmojiMap[strings.ToLower(header.Type)]
+	if !ok {
+		return message
+	}
+	return Join(emoji+" "+header.String(), body)
+}
@@ -0,0 +1,120 @@
+package commitmsg
+
+import (
+	"strings"
+	"unicode"
+)
+
+// DefaultVerbForms maps common non-imperative first words of a description
+// to their imperative form
+var DefaultVerbForms = map[string]string{
+	"added": "add", "adds": "add", "adding": "add",
+	"allowed": "allow", "allows": "allow", "allowing": "allow",
+	"bumped": "bump", "bumps": "bump", "bumping": "bump",
+	"changed": "change", "changes": "change", "changing": "change",
+	"cleaned": "clean", "cleans": "clean", "cleaning": "clean",
+	"created": "create", "creates": "create", "creating": "create",
+	"deleted": "delete", "deletes": "delete", "deleting": "delete",
+	"disabled": "disable", "disables": "disable", "disabling": "disable",
+	"enabled": "enable", "enables": "enable", "enabling": "enable",
+	"extracted": "extract", "extracts": "extract", "extracting": "extract",
+	"fixed": "fix", "fixes": "fix", "fixing": "fix",
+	"handled": "handle", "handles": "handle", "handling": "handle",
+	"implemented": "implement", "implements": "implement", "implementing": "implement",
+	"improved": "improve", "improves": "improve", "improving": "improve",
+	"introduced": "introduce", "introduces": "introduce", "introducing": "introduce",
+	"made": "make", "makes": "make", "making": "make",
+	"merged": "merge", "merges": "merge", "merging": "merge",
+	"moved": "move", "moves": "move", "moving": "move",
+	"prevented": "prevent", "prevents": "prevent", "preventing": "prevent",
+	"refactored": "refactor", "refactors": "refactor", "refactoring": "refactor",
+	"removed": "remove", "removes": "remove", "removing": "remove",
+	"renamed": "rename", "renames": "rename", "renaming": "rename",
+	"replaced": "replace", "replaces": "replace", "replacing": "replace",
+	"reverted": "revert", "reverts": "revert", "reverting": "revert",
+	"simplified": "simplify", "simplifies": "simplify", "simplifying": "simplify",
+	"supported": "support", "supports": "support", "supporting": "support",
+	"updated": "update", "updates": "update", "updating": "update",
+	"upgraded": "upgrade", "upgrades": "upgrade", "upgrading": "upgrade",
+	"used": "use", "uses": "use", "using": "use",
+	"wrote": "write", "writes": "write", "writing": "write",
+}
+
+// imperativeLookalikes end in "ed" or "ing" but are already imperative
+var imperativeLookalikes = []string{
+	"bring", "embed", "exceed", "feed", "need", "ping", "proceed", "seed",
+	"shed", "shred", "speed", "string", "succeed", "swing",
+}
+
+// VerbForms merges overrides into DefaultVerbForms. An override mapping a word
+// to "" marks it as imperative, e.g. to allow "embedding" as a noun.
+func VerbForms(overrides map[string]string) map[string]string {
+	forms := make(map[string]string, len(DefaultVerbForms)+len(overrides))
+	for word, imperative := range DefaultVerbForms {
+		forms[word] = imperative
+	}
+	for word, imperative := range overrides {
+		forms[strings.ToLower(word)] = strings.ToLower(imperative)
+	}
+	return forms
+}
+
+// NonImperativeVerb reports whether the description of message starts with a
+// past tense, gerund or third-person verb, returning that word and its
+// imperative form from forms ("" if unknown). Words missing from forms are
+// caught by their "ed" or "ing" ending.
+func NonImperativeVerb(message string, forms map[string]string) (word, imperative string, found bool) {
+	_, start := descriptionStart(message)
+	word = leadingWord(start)
+	lower := strings.ToLower(word)
+	if imperative, ok := forms[lower]; ok {
+		return word, imperative, imperative != ""
+	}
+	if len(lower) < 5 || containsFold(imperativeLookalikes, lower) {
+		return word, "", false
+	}
+	if strings.HasSuffix(lower, "ed") || strings.HasSuffix(lower, "ing") {
+		return word, "", true
+	}
+	return word, "", false
+}
+
+// FixImperative rewrites the first word of the description to its imperative
+// form when forms knows it, keeping its capitalization. Other messages are
+// returned unchanged.
+func FixImperative(message string, forms map[string]string) string {
+	word, imperative, found := NonImperativeVerb(message, forms)
+	if !found || imperative == "" {
+		return message
+	}
+	if unicode.IsUpper([]rune(word)[0]) {
+		imperative = strings.ToUpper(imperative[:1]) + imperative[1:]
+	}
+
+	_, body := Split(message)
+	prefix, description := descriptionStart(message)
+	return Join(prefix+imperative+description[len(word):], body)
+}
+
+// descriptionStart splits the subject of message into everything before the
+// description (emoji, type and scope) and the description itself. Subjects
+// that aren't in conventional commit form are all description.
+func descriptionStart(message string) (prefix, description string) {
+	subject, _ := Split(message)
+	header, ok := ParseHeader(StripLeadingEmoji(subject))
+	if !ok {
+		return "", subject
+	}
+	// ParseHeader only trims the subject, so the description is its suffix
+	cut := len(subject) - len(header.Description)
+	return subject[:cut], subject[cut:]
+}
+
+// leadingWord returns the letters at the start of s
+func leadingWord(s string) string {
+	end := strings.IndexFunc(s, func(r rune) bool { return !unicode.IsLetter(r) })
+	if end < 0 {
+		return s
+	}
+	return s[:end]
+}
@@ -0,0 +1,35 @@
+package commitmsg
+
+import "strings"
+
+// SectionName normalizes a configured section name: "Testing:" and
+// " Testing" both become "Testing"
+func SectionName(section string) string {
+	return strings.TrimSuffix(strings.TrimSpace(section), ":")
+}
+
+// MissingSections returns the sections with no body line starting with
+// "<name>:" (case-insensitive), in the order given
+func MissingSections(message string, sections []string) []string {
+	lines := strings.Split(strings.Trim(message, "\n"), "\n")
+
+	var missing []string
+	for _, section := range sections {
+		name := SectionName(section)
+		if name == "" {
+			continue
+		}
+		found := false
+		for _, line := range lines[1:] {
+			line = strings.TrimSpace(line)
+			if len(line) > len(name) && line[len(name)] == ':' && strings.EqualFold(line[:len(name)], name) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
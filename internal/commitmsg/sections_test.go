@@ -0,0 +1,44 @@
+package commitmsg
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMissingSections(t *testing.T) {
+	sections := []string{"Motivation", " Testing: ", ""}
+	tests := []struct {
+		name    string
+		message string
+		want    []string
+	}{
+		{"all present", "feat: add login\n\nMotivation: users asked.\n\nTesting: manual.", nil},
+		{"any case and indentation", "feat: add login\n\n  motivation: users asked.\nTESTING: manual.", nil},
+		{"one missing", "feat: add login\n\nMotivation: users asked.", []string{"Testing"}},
+		{"no body", "feat: add login", []string{"Motivation", "Testing"}},
+		{"only in the subject", "Testing: add login\n\nMotivation: users asked.", []string{"Testing"}},
+		{"name without colon", "feat: add login\n\nMotivation users asked.\nTesting", []string{"Motivation", "Testing"}},
+		{"name inside a line", "feat: add login\n\nSee Motivation: below.\nTesting: manual.", []string{"Motivation"}},
+		{"longer name", "feat: add login\n\nMotivations: many.\nTesting: manual.", []string{"Motivation"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MissingSections(tt.message, sections); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MissingSections() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSectionName(t *testing.T) {
+	for section, want := range map[string]string{
+		"Testing":    "Testing",
+		"Testing:":   "Testing",
+		" Testing: ": "Testing",
+		"":           "",
+	} {
+		if got := SectionName(section); got != want {
+			t.Errorf("SectionName(%q) = %q, want %q", section, got, want)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package commitmsg
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultTypes are the conventional commit types accepted by default
+var DefaultTypes = []string{"feat", "fix", "docs", "style", "refactor", "perf", "test", "build", "ci", "chore", "revert"}
+
+// DefaultMaxSubjectLength is the longest subject line accepted by default
+const DefaultMaxSubjectLength = 72
+
+// Rules configure message validation
+type Rules struct {
+	// Types are the allowed commit types (default DefaultTypes)
+	Types []string
+	// Scopes are the allowed scopes; any scope is accepted when empty
+	Scopes []string
+	// RequireScope rejects messages without a scope
+	RequireScope bool
+	// MaxSubjectLength limits the subject line in characters
+	// (default DefaultMaxSubjectLength)
+	MaxSubjectLength int
+	// MaxBodyLineLength limits body lines in characters; 0 means no limit
+	MaxBodyLineLength int
+	// AllowEmoji accepts a leading emoji before the type (gitmoji style)
+	AllowEmoji bool
+	// Gitmoji requires the subject to start with an emoji, or :shortcode:,
+	// from the official gitmoji list or ExtraGitmojis; it implies AllowEmoji
+	Gitmoji       bool
+	ExtraGitmojis []string
+	// Imperative requires the description to start with an imperative verb
+	// ("add", not "added" or "adding")
+	Imperative bool
+	// VerbForms maps non-imperative words to their imperative form for the
+	// Imperative check (default DefaultVerbForms)
+	VerbForms map[string]string
+	// ForbiddenWords may not appear anywhere in the message
+	ForbiddenWords []string
+	// RequiredSections must each start a body line as "<name>:", e.g.
+	// "Motivation:" or "Testing:"
+	RequiredSections []string
+}
+
+// Validate checks a commit message against the rules and returns a
+// description of each problem found, or nil if the message is valid
+func Validate(message string, rules Rules) []string {
+	if len(rules.Types) == 0 {
+		rules.Types = DefaultTypes
+	}
+	if rules.MaxSubjectLength == 0 {
+		rules.MaxSubjectLength = DefaultMaxSubjectLength
+	}
+
+	message = strings.Trim(message, "\n")
+	if strings.TrimSpace(message) == "" {
+		return []string{"message is empty"}
+	}
+
+	var problems []string
+	lines := strings.Split(message, "\n")
+	subject := lines[0]
+
+	if n := utf8.RuneCountInString(subject); n > rules.MaxSubjectLength {
+		problems = append(problems, fmt.Sprintf("subject is %d characters, the limit is %d", n, rules.MaxSubjectLength))
+	}
+	if len(lines) > 1 && strings.TrimSpace(lines[1]) != "" {
+		problems = append(problems, "subject must be followed by a blank line")
+	}
+	if rules.MaxBodyLineLength > 0 {
+		for i, line := range lines[1:] {
+			if n := utf8.RuneCountInString(line); n > rules.MaxBodyLineLength {
+				problems = append(problems, fmt.Sprintf("line %d is %d characters, the limit is %d", i+2, n, rules.MaxBodyLineLength))
+			}
+		}
+	}
+
+	if found := FindForbidden(message, rules.ForbiddenWords); len(found) > 0 {
+		problems = append(problems, fmt.Sprintf("message uses forbidden words: %s", strings.Join(found, ", ")))
+	}
+	if missing := MissingSections(message, rules.RequiredSections); len(missing) > 0 {
+		problems = append(problems, fmt.Sprintf("body is missing required sections: %s", strings.Join(missing, ", ")))
+	}
+
+	header := subject
+	if rules.Gitmoji {
+		switch emoji := LeadingGitmoji(subject); {
+		case emoji == "":
+			problems = append(problems, "subject must start with a gitmoji, e.g. \"✨ feat: ...\"")
+		case !IsGitmoji(emoji, rules.ExtraGitmojis...):
+			problems = append(problems, fmt.Sprintf("%q is not a gitmoji (see https://gitmoji.dev)", emoji))
+		}
+	}
+	if rules.AllowEmoji || rules.Gitmoji {
+		header = StripLeadingEmoji(header)
+	}
+	if HasEmptyScope(header) {
+		return append(problems, `scope is empty; omit the parentheses instead of writing "type()"`)
+	}
+
+	h, ok := ParseHeader(header)
+	if !ok {
+		return append(problems, `subject is not in conventional commit form "type(scope): description"`)
+	}
+
+	if !containsFold(rules.Types, h.Type) {
+		problems = append(problems, fmt.Sprintf("type %q is not one of: %s", h.Type, strings.Join(rules.Types, ", ")))
+	}
+	switch {
+	case h.Scope == "" && rules.RequireScope:
+		problems = append(problems, "a scope is required")
+	case h.Scope != "" && len(rules.Scopes) > 0 && !containsFold(rules.Scopes, h.Scope):
+		problems = append(problems, fmt.Sprintf("scope %q is not one of: %s", h.Scope, strings.Join(rules.Scopes, ", ")))
+	}
+
+	description := strings.TrimSpace(h.Description)
+	switch {
+	case description == "":
+		problems = append(problems, "description is empty")
+	case strings.HasSuffix(description, "."):
+		problems = append(problems, "description should not end with a period")
+	}
+	if rules.Imperative {
+		if problem := moodProblem(message, rules.VerbForms); problem != "" {
+			problems = append(problems, problem)
+		}
+	}
+
+	return problems
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// moodProblem describes a description that doesn't start in the imperative
+// mood, or returns "" if it does
+func moodProblem(message string, forms map[string]string) string {
+	if forms == nil {
+		forms = DefaultVerbForms
+	}
+	word, imperative, found := NonImperativeVerb(message, forms)
+	switch {
+	case !found:
+		return ""
+	case imperative != "":
+		return fmt.Sprintf("description should use the imperative mood: %q, not %q", imperative, word)
+	default:
+		return fmt.Sprintf("description should use the imperative mood; %q looks like past tense or a gerund", word)
+	}
+}
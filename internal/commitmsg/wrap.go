@@ -0,0 +1,64 @@
+package commitmsg
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultBodyWidth is the column commit message bodies are wrapped at, as
+// git's documentation recommends
+const DefaultBodyWidth = 72
+
+// WrapBody wraps body lines longer than width at word boundaries. Wrapped
+// list items continue under their text rather than their marker. Trailer
+// and BREAKING CHANGE footer lines are left alone, since git reads each as
+// one line, and so are words longer than the width, such as URLs.
+func WrapBody(message string, width int) string {
+	if width <= 0 {
+		return message
+	}
+	subject, body := Split(message)
+	if body == "" {
+		return message
+	}
+
+	var lines []string
+	for _, line := range strings.Split(body, "\n") {
+		if utf8.RuneCountInString(line) <= width || trailerLinePattern.MatchString(line) ||
+			strings.HasPrefix(line, breakingFooterToken) {
+			lines = append(lines, line)
+			continue
+		}
+		lines = append(lines, wrapLine(line, width)...)
+	}
+	return Join(subject, strings.Join(lines, "\n"))
+}
+
+// wrapLine breaks a line into lines of at most width characters where
+// possible, indenting continuations to the start of a list item's text
+func wrapLine(line string, width int) []string {
+	trimmed := strings.TrimLeft(line, " \t")
+	indent := line[:len(line)-len(trimmed)]
+	for _, marker := range bulletMarkers {
+		if strings.HasPrefix(trimmed, marker) {
+			indent += strings.Repeat(" ", utf8.RuneCountInString(marker))
+			break
+		}
+	}
+
+	words := strings.Fields(trimmed)
+	if len(words) == 0 {
+		return []string{line}
+	}
+	var lines []string
+	current := line[:len(line)-len(trimmed)] + words[0]
+	for _, word := range words[1:] {
+		if utf8.RuneCountInString(current)+1+utf8.RuneCountInString(word) > width {
+			lines = append(lines, current)
+			current = indent + word
+			continue
+		}
+		current += " " + word
+	}
+	return append(lines, current)
+}
@@ -0,0 +1,72 @@
+package commitmsg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWrapBody(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		width   int
+		want    string
+	}{
+		{
+			name:    "short lines are unchanged",
+			message: "feat: add x\n\n- Add x",
+			width:   72,
+			want:    "feat: add x\n\n- Add x",
+		},
+		{
+			name:    "bullets continue under their text",
+			message: "feat: add x\n\n- one two three four five",
+			width:   12,
+			want:    "feat: add x\n\n- one two\n  three four\n  five",
+		},
+		{
+			name:    "paragraphs wrap without indent",
+			message: "fix: y\n\none two three four",
+			width:   9,
+			want:    "fix: y\n\none two\nthree\nfour",
+		},
+		{
+			name:    "long words are kept whole",
+			message: "docs: z\n\nsee https://example.com/a/long/path",
+			width:   10,
+			want:    "docs: z\n\nsee\nhttps://example.com/a/long/path",
+		},
+		{
+			name:    "trailers and breaking change footers are left alone",
+			message: "feat!: w\n\nBREAKING CHANGE: one two three\nSigned-off-by: A B <a@b.c>",
+			width:   10,
+			want:    "feat!: w\n\nBREAKING CHANGE: one two three\nSigned-off-by: A B <a@b.c>",
+		},
+		{
+			name:    "no body",
+			message: "chore: v",
+			width:   5,
+			want:    "chore: v",
+		},
+		{
+			name:    "zero width disables wrapping",
+			message: "feat: add x\n\none two three",
+			width:   0,
+			want:    "feat: add x\n\none two three",
+		},
+		{
+			name:    "lines of only unicode whitespace are unchanged",
+			message: "feat: add x\n\n" + strings.Repeat("　", 80),
+			width:   72,
+			want:    "feat: add x\n\n" + strings.Repeat("　", 80),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WrapBody(tt.message, tt.width); got != tt.want {
+				t.Errorf("WrapBody() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,10 +4,82 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 )
 
+// MinVersion is the oldest supported git release (needed for --force-with-lease)
+var MinVersion = [3]int{1, 8, 5}
+
+// CheckInstalled verifies that git is on PATH and at least MinVersion.
+// It returns the detected version string.
+func CheckInstalled() (string, error) {
+	path, err := exec.LookPath("git")
+	if err != nil {
+		return "", errors.New("git not found in PATH. Install it from https://git-scm.com/downloads")
+	}
+
+	out, err := exec.Command(path, "--version").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to run %s --version: %w", path, err)
+	}
+
+	version, parsed, ok := parseVersion(string(out))
+	if !ok {
+		// Unknown format; don't block on it
+		return strings.TrimSpace(string(out)), nil
+	}
+
+	for i := range parsed {
+		if parsed[i] != MinVersion[i] {
+			if parsed[i] < MinVersion[i] {
+				return version, fmt.Errorf("git version %s is too old (need %d.%d.%d for --force-with-lease)",
+					version, MinVersion[0], MinVersion[1], MinVersion[2])
+			}
+			break
+		}
+	}
+
+	return version, nil
+}
+
+// parseVersion extracts the version from "git version 2.39.5" style output,
+// including vendor suffixes like ".windows.1" or " (Apple Git-137.1)"
+func parseVersion(output string) (string, [3]int, bool) {
+	var parsed [3]int
+	fields := strings.Fields(output)
+	if len(fields) < 3 || fields[0] != "git" || fields[1] != "version" {
+		return "", parsed, false
+	}
+
+	version := fields[2]
+	parts := strings.Split(version, ".")
+	if len(parts) < 2 {
+		return version, parsed, false
+	}
+	for i := 0; i < len(parsed) && i < len(parts); i++ {
+		n, err := strconv.Atoi(parts[i])
+		if err != nil {
+			return version, parsed, false
+		}
+		parsed[i] = n
+	}
+	return version, parsed, true
+}
+
+// Commit holds the identifying details of a commit
+type Commit struct {
+	Hash    string
+	Subject string
+	Body    string
+}
+
 // Git provides git operations
 type Git struct {
 	workDir string
@@ -21,10 +93,53 @@ func New(workDir string) *Git {
 	return &Git{workDir: workDir}
 }
 
-// run executes a git command and returns the output
-func (g *Git) run(args ...string) (string, error) {
+// repoEnv are the variables that point git at a repository regardless of
+// the working directory, e.g. GIT_DIR set for a hook in another worktree
+var repoEnv = map[string]bool{
+	"GIT_DIR":              true,
+	"GIT_WORK_TREE":        true,
+	"GIT_INDEX_FILE":       true,
+	"GIT_COMMON_DIR":       true,
+	"GIT_OBJECT_DIRECTORY": true,
+	"GIT_PREFIX":           true,
+}
+
+// command prepares a git command in the work directory. For an explicit
+// work directory, inherited repository variables are dropped so git finds
+// the repository (or linked worktree) from the directory itself.
+func (g *Git) command(args ...string) *exec.Cmd {
 	cmd := exec.Command("git", args...)
 	cmd.Dir = g.workDir
+	if g.workDir != "." {
+		for _, kv := range os.Environ() {
+			name, _, _ := strings.Cut(kv, "=")
+			if !repoEnv[name] {
+				cmd.Env = append(cmd.Env, kv)
+			}
+		}
+	}
+	return cmd
+}
+
+// run executes a git command and returns the output
+func (g *Git) run(args ...string) (string, error) {
+	cmd := g.command(args...)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s failed: %s", strings.Join(args, " "), stderr.String())
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// runWithInput executes a git command with the given stdin and returns the output
+func (g *Git) runWithInput(input string, args ...string) (string, error) {
+	cmd := g.command(args...)
+	cmd.Stdin = strings.NewReader(input)
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
@@ -37,17 +152,112 @@ func (g *Git) run(args ...string) (string, error) {
 	return strings.TrimSpace(stdout.String()), nil
 }
 
+// FindRepos returns the git repositories at or under root: every directory
+// holding a .git directory or file. Repositories nested inside another one,
+// such as submodules, belong to it and aren't listed.
+func FindRepos(root string) ([]string, error) {
+	var repos []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if _, err := os.Lstat(filepath.Join(path, ".git")); err == nil {
+			repos = append(repos, path)
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	return repos, err
+}
+
 // IsRepo checks if the current directory is a git repository
 func (g *Git) IsRepo() bool {
 	_, err := g.run("rev-parse", "--git-dir")
 	return err == nil
 }
 
+// gitPath runs rev-parse with the given option and returns the path it
+// prints, made absolute relative to the work directory
+func (g *Git) gitPath(option string) (string, error) {
+	path, err := g.run("rev-parse", option)
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(g.workDir, path)
+	}
+	return filepath.Abs(path)
+}
+
+// Toplevel returns the root directory of the work tree
+func (g *Git) Toplevel() (string, error) {
+	return g.gitPath("--show-toplevel")
+}
+
+// CommonDir returns the repository's shared git directory: the same for the
+// main worktree and every linked worktree
+func (g *Git) CommonDir() (string, error) {
+	return g.gitPath("--git-common-dir")
+}
+
+// IsLinkedWorktree reports whether the work directory is in a worktree made
+// with git worktree add, whose git directory differs from the shared one
+func (g *Git) IsLinkedWorktree() bool {
+	gitDir, err := g.gitPath("--git-dir")
+	if err != nil {
+		return false
+	}
+	commonDir, err := g.CommonDir()
+	return err == nil && gitDir != commonDir
+}
+
+// Operation is a merge, cherry-pick or revert stopped before its commit,
+// usually to resolve conflicts
+type Operation struct {
+	// Kind is "merge", "cherry-pick" or "revert"
+	Kind string
+	// Commit is the commit being merged, picked or reverted
+	Commit string
+	// Subject is the subject line of Commit
+	Subject string
+}
+
+// operationHeads maps the refs git leaves during an operation to its kind
+var operationHeads = []struct{ ref, kind string }{
+	{"MERGE_HEAD", "merge"},
+	{"CHERRY_PICK_HEAD", "cherry-pick"},
+	{"REVERT_HEAD", "revert"},
+}
+
+// InProgressOperation returns the merge, cherry-pick or revert waiting to be
+// committed, or nil if there is none
+func (g *Git) InProgressOperation() *Operation {
+	for _, head := range operationHeads {
+		sha, err := g.run("rev-parse", "--quiet", "--verify", head.ref)
+		if err != nil {
+			continue
+		}
+		subject, _ := g.run("log", "-1", "--format=%s", sha)
+		return &Operation{Kind: head.kind, Commit: sha, Subject: subject}
+	}
+	return nil
+}
+
 // GetStagedDiff returns the diff of staged changes
 func (g *Git) GetStagedDiff() (string, error) {
 	return g.run("diff", "--cached")
 }
 
+// GetStagedDiffIgnoringSpace returns the staged diff without changes that
+// only touch whitespace (git diff -w); hunks left with no other change are
+// dropped
+func (g *Git) GetStagedDiffIgnoringSpace() (string, error) {
+	return g.run("diff", "--cached", "--ignore-all-space")
+}
+
 // GetUnstagedDiff returns the diff of unstaged changes
 func (g *Git) GetUnstagedDiff() (string, error) {
 	return g.run("diff")
@@ -58,6 +268,11 @@ func (g *Git) GetAllDiff() (string, error) {
 	return g.run("diff", "HEAD")
 }
 
+// GetAllDiffIgnoringSpace is GetAllDiff without whitespace-only changes
+func (g *Git) GetAllDiffIgnoringSpace() (string, error) {
+	return g.run("diff", "HEAD", "--ignore-all-space")
+}
+
 // GetUnpushedCommits returns commits that haven't been pushed
 func (g *Git) GetUnpushedCommits() ([]string, error) {
 	branch, err := g.GetCurrentBranch()
@@ -113,9 +328,41 @@ func (g *Git) GetUnpushedCommitMessages() ([]string, error) {
 	return strings.Split(output, "\n"), nil
 }
 
-// GetCommitDiff returns the diff for a specific commit
+// GetCommits returns the non-merge commits in a revision range (e.g. "v1.0..v1.1"),
+// newest first
+func (g *Git) GetCommits(revRange string) ([]Commit, error) {
+	output, err := g.run("log", "--no-merges", "--format=%H%x1f%s%x1f%b%x1e", revRange)
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []Commit
+	for _, record := range strings.Split(output, "\x1e") {
+		fields := strings.SplitN(strings.TrimSpace(record), "\x1f", 3)
+		if len(fields) < 2 {
+			continue
+		}
+		c := Commit{Hash: fields[0], Subject: fields[1]}
+		if len(fields) == 3 {
+			c.Body = strings.TrimSpace(fields[2])
+		}
+		commits = append(commits, c)
+	}
+	return commits, nil
+}
+
+// GetCommitDiff returns the changes a commit introduced. Merge commits are
+// diffed against their first parent, i.e. what the merge brought in, rather
+// than shown as a combined diff; root commits against the empty tree.
 func (g *Git) GetCommitDiff(commitHash string) (string, error) {
-	return g.run("show", commitHash, "--format=", "--no-color")
+	parents, err := g.run("rev-list", "--parents", "-n", "1", commitHash)
+	if err != nil {
+		return "", err
+	}
+	if len(strings.Fields(parents)) < 2 {
+		return g.run("diff-tree", "-p", "--root", "--no-commit-id", "--no-color", "--no-ext-diff", commitHash)
+	}
+	return g.run("diff", "--no-color", "--no-ext-diff", commitHash+"^1", commitHash)
 }
 
 // GetUnpushedDiff returns combined diff of all unpushed commits
@@ -134,6 +381,16 @@ func (g *Git) GetUnpushedDiff() (string, error) {
 	return g.run("diff", upstream+"..HEAD")
 }
 
+// GetBranchDiff returns the changes on this branch since it diverged from base
+func (g *Git) GetBranchDiff(base string) (string, error) {
+	return g.run("diff", base+"...HEAD")
+}
+
+// HeadCommit returns the full hash of HEAD
+func (g *Git) HeadCommit() (string, error) {
+	return g.run("rev-parse", "HEAD")
+}
+
 // GetCurrentBranch returns the current branch name
 func (g *Git) GetCurrentBranch() (string, error) {
 	return g.run("rev-parse", "--abbrev-ref", "HEAD")
@@ -161,6 +418,131 @@ func (g *Git) GetRemote() (string, error) {
 	return remotes[0], nil
 }
 
+// DefaultBaseBranch returns the remote branch new work is based on, e.g. "origin/main".
+// It uses the remote's HEAD when known and falls back to main or master.
+func (g *Git) DefaultBaseBranch() (string, error) {
+	remote, err := g.GetRemote()
+	if err != nil {
+		return "", err
+	}
+
+	if head, err := g.run("symbolic-ref", "--short", "refs/remotes/"+remote+"/HEAD"); err == nil && head != "" {
+		return head, nil
+	}
+
+	for _, name := range []string{"main", "master"} {
+		ref := remote + "/" + name
+		if _, err := g.run("rev-parse", "--verify", "--quiet", "refs/remotes/"+ref); err == nil {
+			return ref, nil
+		}
+	}
+
+	return "", fmt.Errorf("could not determine base branch for remote %s", remote)
+}
+
+// FetchBranch updates the remote-tracking ref for a "remote/branch" ref
+func (g *Git) FetchBranch(ref string) error {
+	parts := strings.SplitN(ref, "/", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("invalid remote branch: %s", ref)
+	}
+	_, err := g.run("fetch", "--quiet", parts[0], parts[1])
+	return err
+}
+
+// AheadBehind returns how many commits HEAD is ahead of and behind base
+func (g *Git) AheadBehind(base string) (ahead, behind int, err error) {
+	output, err := g.run("rev-list", "--left-right", "--count", "HEAD..."+base)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	fields := strings.Fields(output)
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("unexpected rev-list output: %q", output)
+	}
+
+	if ahead, err = strconv.Atoi(fields[0]); err != nil {
+		return 0, 0, err
+	}
+	if behind, err = strconv.Atoi(fields[1]); err != nil {
+		return 0, 0, err
+	}
+	return ahead, behind, nil
+}
+
+// RemoteWebURL returns the browsable https URL of the default remote,
+// e.g. "https://github.com/owner/repo"
+func (g *Git) RemoteWebURL() (string, error) {
+	remote, err := g.GetRemote()
+	if err != nil {
+		return "", err
+	}
+
+	remoteURL, err := g.run("remote", "get-url", remote)
+	if err != nil {
+		return "", err
+	}
+
+	return remoteWebURL(remoteURL)
+}
+
+// remoteWebURL normalizes SSH and HTTPS remote URLs for GitHub, GitLab,
+// Bitbucket and self-hosted instances to a browsable base URL. SSH ports are
+// dropped since they don't apply to the web UI; HTTPS ports are kept.
+func remoteWebURL(remoteURL string) (string, error) {
+	remoteURL = strings.TrimSpace(remoteURL)
+	if remoteURL == "" {
+		return "", errors.New("empty remote URL")
+	}
+
+	var host, path string
+
+	if !strings.Contains(remoteURL, "://") {
+		// scp-like syntax: [user@]host:path
+		at := strings.LastIndex(remoteURL, "@")
+		colon := strings.Index(remoteURL[at+1:], ":")
+		if colon < 0 {
+			return "", fmt.Errorf("unrecognized remote URL: %s", remoteURL)
+		}
+		host = remoteURL[at+1 : at+1+colon]
+		path = remoteURL[at+1+colon+1:]
+	} else {
+		u, err := url.Parse(remoteURL)
+		if err != nil {
+			return "", fmt.Errorf("invalid remote URL %s: %w", remoteURL, err)
+		}
+
+		switch u.Scheme {
+		case "http", "https":
+			host = u.Host
+		case "ssh", "git", "git+ssh", "ssh+git":
+			host = u.Hostname()
+		default:
+			return "", fmt.Errorf("unsupported remote URL scheme: %s", u.Scheme)
+		}
+		path = u.Path
+
+		if u.Scheme == "http" {
+			return "http://" + host + "/" + cleanRepoPath(path), nil
+		}
+	}
+
+	path = cleanRepoPath(path)
+	if host == "" || path == "" {
+		return "", fmt.Errorf("unrecognized remote URL: %s", remoteURL)
+	}
+
+	return "https://" + host + "/" + path, nil
+}
+
+// cleanRepoPath trims slashes and the ".git" suffix from a repository path
+func cleanRepoPath(path string) string {
+	path = strings.Trim(path, "/")
+	path = strings.TrimSuffix(path, ".git")
+	return strings.TrimSuffix(path, "/")
+}
+
 // HasStagedChanges checks if there are staged changes
 func (g *Git) HasStagedChanges() (bool, error) {
 	output, err := g.run("diff", "--cached", "--name-only")
@@ -179,6 +561,69 @@ func (g *Git) HasUnstagedChanges() (bool, error) {
 	return output != "", nil
 }
 
+// GetStagedFiles returns the paths of the files with staged changes
+func (g *Git) GetStagedFiles() ([]string, error) {
+	output, err := g.run("diff", "--cached", "--name-only")
+	if err != nil {
+		return nil, err
+	}
+	if output == "" {
+		return nil, nil
+	}
+	return strings.Split(output, "\n"), nil
+}
+
+// Unstage removes paths from the index, keeping their changes in the working
+// tree. It uses reset rather than restore --staged, which needs git 2.23.
+func (g *Git) Unstage(paths ...string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+	if _, err := g.run("rev-parse", "--verify", "--quiet", "HEAD"); err != nil {
+		// No commits yet, so there's nothing to reset to
+		_, err := g.run(append([]string{"rm", "--cached", "--quiet", "--"}, paths...)...)
+		return err
+	}
+	_, err := g.run(append([]string{"reset", "--quiet", "HEAD", "--"}, paths...)...)
+	return err
+}
+
+// CreateBranch creates a branch at HEAD and switches to it, keeping any
+// staged and unstaged changes
+func (g *Git) CreateBranch(name string) error {
+	_, err := g.run("checkout", "-b", name)
+	return err
+}
+
+// Stage stages the given paths
+func (g *Git) Stage(paths ...string) error {
+	_, err := g.run(append([]string{"add", "--"}, paths...)...)
+	return err
+}
+
+// StagedPatch returns the staged changes to the given paths as a binary-safe
+// patch that ApplyToIndex can restage
+func (g *Git) StagedPatch(paths ...string) (string, error) {
+	output, err := g.run(append([]string{"diff", "--cached", "--binary", "--full-index", "--"}, paths...)...)
+	if err != nil || output == "" {
+		return output, err
+	}
+	return output + "\n", nil
+}
+
+// ApplyToIndex applies a patch to the index only, leaving the working tree alone
+func (g *Git) ApplyToIndex(patch string) error {
+	_, err := g.runWithInput(patch, "apply", "--cached", "-")
+	return err
+}
+
+// IsTracked reports whether git tracks the path, e.g. a deleted file whose
+// removal hasn't been staged yet
+func (g *Git) IsTracked(path string) bool {
+	_, err := g.run("ls-files", "--error-unmatch", "--", path)
+	return err == nil
+}
+
 // StageAll stages all changes
 func (g *Git) StageAll() error {
 	_, err := g.run("add", "-A")
@@ -187,18 +632,169 @@ func (g *Git) StageAll() error {
 
 // Commit creates a commit with the given message
 func (g *Git) Commit(message string) error {
-	_, err := g.run("commit", "-m", message)
+	_, err := g.run(append([]string{"commit"}, messageArgs(message)...)...)
 	return err
 }
 
+// CommitAt creates a commit with the given message and author date
+func (g *Git) CommitAt(message string, date time.Time) error {
+	_, err := g.run(append([]string{"commit", "--date=" + date.Format(time.RFC3339)}, messageArgs(message)...)...)
+	return err
+}
+
+// messageArgs passes each paragraph of a commit message as its own -m, so
+// git keeps the subject, body and footers as separate paragraphs
+func messageArgs(message string) []string {
+	var args []string
+	for _, paragraph := range strings.Split(strings.TrimSpace(message), "\n\n") {
+		if paragraph = strings.Trim(paragraph, "\n"); paragraph != "" {
+			args = append(args, "-m", paragraph)
+		}
+	}
+	if args == nil {
+		args = []string{"-m", message}
+	}
+	return args
+}
+
+// CommitFixup creates a "fixup! <subject>" commit of the staged changes that
+// rebase --autosquash folds into the given commit, keeping its message
+func (g *Git) CommitFixup(commit string) error {
+	_, err := g.run("commit", "--fixup="+commit)
+	return err
+}
+
+// CommitSquash creates a "squash! <subject>" commit of the staged changes that
+// rebase --autosquash squashes into the given commit, combining the messages
+func (g *Git) CommitSquash(commit string) error {
+	_, err := g.run("commit", "--squash="+commit, "--no-edit")
+	return err
+}
+
+// AddTrailers appends trailers (e.g. "Refs: PROJ-123") to a commit message
+// using git interpret-trailers, so they are formatted the way git expects
+func (g *Git) AddTrailers(message string, trailers []string) (string, error) {
+	if len(trailers) == 0 {
+		return message, nil
+	}
+
+	args := []string{"interpret-trailers"}
+	for _, t := range trailers {
+		args = append(args, "--trailer", t)
+	}
+	return g.runWithInput(message+"\n", args...)
+}
+
+// NormalizeTrailers reformats trailer lines (e.g. "Signed-off-by", "Co-authored-by")
+// with git interpret-trailers and separates them from the body with a blank line.
+// Messages without trailers are returned unchanged.
+func (g *Git) NormalizeTrailers(message string) (string, error) {
+	lines := strings.Split(strings.TrimRight(message, "\n"), "\n")
+	subject := lines[0]
+	rest := strings.Trim(strings.Join(lines[1:], "\n"), "\n")
+	if rest == "" {
+		return message, nil
+	}
+
+	// Parse with the subject in its own paragraph so trailers typed
+	// directly below it are still recognized
+	trailers, err := g.runWithInput(subject+"\n\n"+rest+"\n", "interpret-trailers", "--parse")
+	if err != nil {
+		return "", err
+	}
+	if trailers == "" {
+		return message, nil
+	}
+
+	keys := make(map[string]bool)
+	for _, t := range strings.Split(trailers, "\n") {
+		if i := strings.Index(t, ":"); i > 0 {
+			keys[strings.ToLower(strings.TrimSpace(t[:i]))] = true
+		}
+	}
+
+	// Drop the raw trailer block (and continuation lines) from the end of the body
+	bodyLines := strings.Split(rest, "\n")
+	end := len(bodyLines)
+	for end > 0 {
+		line := bodyLines[end-1]
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			end--
+			continue
+		}
+		i := strings.IndexAny(line, ":=")
+		if i <= 0 || !keys[strings.ToLower(strings.TrimSpace(line[:i]))] {
+			break
+		}
+		end--
+	}
+
+	body := strings.Trim(strings.Join(bodyLines[:end], "\n"), "\n")
+	if body == "" {
+		return subject + "\n\n" + trailers, nil
+	}
+	return subject + "\n\n" + body + "\n\n" + trailers, nil
+}
+
 // AmendCommit amends the last commit with a new message
 func (g *Git) AmendCommit(message string) error {
-	_, err := g.run("commit", "--amend", "-m", message)
+	_, err := g.run(append([]string{"commit", "--amend"}, messageArgs(message)...)...)
+	return err
+}
+
+// RewordLastCommit replaces the last commit's message without adding
+// anything from the index
+func (g *Git) RewordLastCommit(message string) error {
+	_, err := g.run(append([]string{"commit", "--amend", "--only"}, messageArgs(message)...)...)
 	return err
 }
 
-// Push pushes to the remote
+// IsHeadPushed reports whether HEAD is already contained in a remote-tracking branch
+func (g *Git) IsHeadPushed() (bool, error) {
+	output, err := g.run("branch", "-r", "--contains", "HEAD")
+	if err != nil {
+		return false, err
+	}
+	return output != "", nil
+}
+
+// Upstream returns the remote and remote branch name the current branch
+// tracks, i.e. what @{upstream} resolves to, which may differ from the
+// local name. ok is false if there is no upstream or it is a local branch.
+func (g *Git) Upstream() (remote, branch string, ok bool) {
+	local, err := g.GetCurrentBranch()
+	if err != nil {
+		return "", "", false
+	}
+	remote, err = g.run("config", "--get", "branch."+local+".remote")
+	if err != nil || remote == "" || remote == "." {
+		return "", "", false
+	}
+	merge, err := g.run("config", "--get", "branch."+local+".merge")
+	if err != nil || !strings.HasPrefix(merge, "refs/heads/") {
+		return "", "", false
+	}
+	return remote, strings.TrimPrefix(merge, "refs/heads/"), true
+}
+
+// Push pushes the current branch to its upstream, keeping the upstream's
+// branch name, or to the same name on the default remote if it has none
 func (g *Git) Push() error {
+	branch, err := g.GetCurrentBranch()
+	if err != nil {
+		return err
+	}
+
+	if remote, upstream, ok := g.Upstream(); ok {
+		_, err = g.run("push", remote, branch+":refs/heads/"+upstream)
+		return err
+	}
+	return g.PushSameName()
+}
+
+// PushSameName pushes the current branch to the branch of the same name on
+// the default remote, whatever its upstream
+func (g *Git) PushSameName() error {
 	remote, err := g.GetRemote()
 	if err != nil {
 		return err
@@ -229,6 +825,133 @@ func (g *Git) PushSetUpstream() error {
 	return err
 }
 
+// RemoteBranchExists reports whether the remote already has a branch with
+// this name, asking the remote rather than relying on fetched refs
+func (g *Git) RemoteBranchExists(branch string) (bool, error) {
+	commit, err := g.RemoteBranchCommit(branch)
+	return commit != "", err
+}
+
+// RemoteBranchCommit returns the commit the remote's branch points to, or ""
+// if the remote has no such branch
+func (g *Git) RemoteBranchCommit(branch string) (string, error) {
+	remote, err := g.GetRemote()
+	if err != nil {
+		return "", err
+	}
+	output, err := g.run("ls-remote", "--heads", remote, "refs/heads/"+branch)
+	if err != nil {
+		return "", err
+	}
+	commit, _, _ := strings.Cut(output, "\t")
+	return commit, nil
+}
+
+// PushForceWithLease pushes and sets upstream, replacing the remote branch
+// only if it still points to expected
+func (g *Git) PushForceWithLease(expected string) error {
+	remote, err := g.GetRemote()
+	if err != nil {
+		return err
+	}
+
+	branch, err := g.GetCurrentBranch()
+	if err != nil {
+		return err
+	}
+
+	_, err = g.run("push", "-u", "--force-with-lease=refs/heads/"+branch+":"+expected, remote, branch)
+	return err
+}
+
+// RenameBranch renames the current branch
+func (g *Git) RenameBranch(name string) error {
+	_, err := g.run("branch", "-m", name)
+	return err
+}
+
+// CreateAnnotatedTag creates an annotated tag at HEAD. The message is kept
+// as-is apart from whitespace cleanup, so Markdown headings survive.
+func (g *Git) CreateAnnotatedTag(name, message string) error {
+	_, err := g.runWithInput(message, "tag", "--annotate", "--cleanup=whitespace", "--file=-", name)
+	return err
+}
+
+// GetLastTag returns the most recent tag reachable from HEAD, or "" if there is none
+func (g *Git) GetLastTag() (string, error) {
+	tag, err := g.run("describe", "--tags", "--abbrev=0")
+	if err != nil {
+		// describe also fails when no tag is reachable
+		if strings.Contains(err.Error(), "No names found") || strings.Contains(err.Error(), "No tags can describe") {
+			return "", nil
+		}
+		return "", err
+	}
+	return tag, nil
+}
+
+// PushTags pushes all local tags to the remote
+func (g *Git) PushTags() error {
+	remote, err := g.GetRemote()
+	if err != nil {
+		return err
+	}
+
+	_, err = g.run("push", remote, "--tags")
+	return err
+}
+
+// UnpushedTags returns the local tags that don't exist on the remote
+func (g *Git) UnpushedTags() ([]string, error) {
+	remote, err := g.GetRemote()
+	if err != nil {
+		return nil, err
+	}
+
+	local, err := g.run("tag", "--list")
+	if err != nil {
+		return nil, err
+	}
+	if local == "" {
+		return nil, nil
+	}
+
+	output, err := g.run("ls-remote", "--tags", "--refs", remote)
+	if err != nil {
+		return nil, err
+	}
+	onRemote := make(map[string]bool)
+	for _, line := range strings.Split(output, "\n") {
+		if i := strings.Index(line, "refs/tags/"); i >= 0 {
+			onRemote[line[i+len("refs/tags/"):]] = true
+		}
+	}
+
+	var tags []string
+	for _, tag := range strings.Split(local, "\n") {
+		if !onRemote[tag] {
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
+
+// CommentChar returns core.commentChar, the character starting comment lines
+// in commit message files ("#" by default, and for "auto")
+func (g *Git) CommentChar() string {
+	char, err := g.run("config", "core.commentChar")
+	if err != nil || char == "" || char == "auto" {
+		return "#"
+	}
+	return char
+}
+
+// Editor returns the editor git uses for commit messages, honoring
+// GIT_EDITOR, core.editor, VISUAL and EDITOR
+func (g *Git) Editor() (string, error) {
+	return g.run("var", "GIT_EDITOR")
+}
+
 // GetStatus returns the git status
 func (g *Git) GetStatus() (string, error) {
 	return g.run("status", "--short")
@@ -239,6 +962,46 @@ func (g *Git) GetLastCommitMessage() (string, error) {
 	return g.run("log", "-1", "--format=%B")
 }
 
+// GetSubmoduleChanges describes staged submodule pointer updates, e.g.
+// "submodule lib/vendor updated to 1a2b3c4d5e6f"
+func (g *Git) GetSubmoduleChanges() ([]string, error) {
+	output, err := g.run("diff", "--cached", "--raw", "--abbrev=40")
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []string
+	for _, line := range strings.Split(output, "\n") {
+		// Format: ":<old mode> <new mode> <old sha> <new sha> <status>\t<path>"
+		meta, path, ok := strings.Cut(line, "\t")
+		fields := strings.Fields(strings.TrimPrefix(meta, ":"))
+		if !ok || len(fields) < 5 {
+			continue
+		}
+		oldMode, newMode, newSHA, status := fields[0], fields[1], fields[3], fields[4]
+		if oldMode != "160000" && newMode != "160000" {
+			continue
+		}
+
+		switch {
+		case status == "D" || newMode == "000000":
+			changes = append(changes, fmt.Sprintf("submodule %s removed", path))
+		case status == "A" || oldMode == "000000":
+			changes = append(changes, fmt.Sprintf("submodule %s added at %s", path, shortSHA(newSHA)))
+		default:
+			changes = append(changes, fmt.Sprintf("submodule %s updated to %s", path, shortSHA(newSHA)))
+		}
+	}
+	return changes, nil
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 12 {
+		return sha[:12]
+	}
+	return sha
+}
+
 // GetChangedFiles returns a list of changed files
 func (g *Git) GetChangedFiles() ([]string, error) {
 	output, err := g.run("diff", "--name-only", "HEAD")
@@ -0,0 +1,307 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newTestRepo creates an empty repository on branch main with git isolated
+// from the user's configuration
+func newTestRepo(t *testing.T) string {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	dir := t.TempDir()
+	runGit(t, dir, "init", "-q", "-b", "main")
+	return dir
+}
+
+// runGit runs git in dir and returns its trimmed output
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// commitFile writes a file and commits it, returning the commit's hash
+func commitFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, dir, "add", name)
+	runGit(t, dir, "commit", "-q", "-m", "add "+name)
+	return runGit(t, dir, "rev-parse", "HEAD")
+}
+
+func TestGetCommitDiff(t *testing.T) {
+	dir := newTestRepo(t)
+	root := commitFile(t, dir, "root.txt", "root\n")
+	runGit(t, dir, "checkout", "-q", "-b", "feature")
+	regular := commitFile(t, dir, "feature.txt", "feature\n")
+	runGit(t, dir, "checkout", "-q", "main")
+	commitFile(t, dir, "main.txt", "main\n")
+	runGit(t, dir, "merge", "-q", "--no-ff", "-m", "merge feature", "feature")
+	merge := runGit(t, dir, "rev-parse", "HEAD")
+
+	tests := []struct {
+		name    string
+		commit  string
+		want    []string
+		notWant []string
+	}{
+		{
+			name:   "root commit is diffed against the empty tree",
+			commit: root,
+			want:   []string{"+++ b/root.txt", "+root"},
+		},
+		{
+			name:    "regular commit",
+			commit:  regular,
+			want:    []string{"+++ b/feature.txt"},
+			notWant: []string{"root.txt", "main.txt"},
+		},
+		{
+			name:    "merge commit shows what it brought in over the first parent",
+			commit:  merge,
+			want:    []string{"+++ b/feature.txt", "+feature"},
+			notWant: []string{"main.txt", "root.txt", "diff --cc"},
+		},
+	}
+
+	g := New(dir)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff, err := g.GetCommitDiff(tt.commit)
+			if err != nil {
+				t.Fatalf("GetCommitDiff() error = %v", err)
+			}
+			for _, s := range tt.want {
+				if !strings.Contains(diff, s) {
+					t.Errorf("diff doesn't contain %q:\n%s", s, diff)
+				}
+			}
+			for _, s := range tt.notWant {
+				if strings.Contains(diff, s) {
+					t.Errorf("diff contains %q:\n%s", s, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestGetLastTag(t *testing.T) {
+	dir := newTestRepo(t)
+	commitFile(t, dir, "a.txt", "a\n")
+
+	if tag, err := New(dir).GetLastTag(); tag != "" || err != nil {
+		t.Errorf("GetLastTag() without tags = %q, %v, want no tag", tag, err)
+	}
+
+	runGit(t, dir, "checkout", "-q", "-b", "other")
+	commitFile(t, dir, "b.txt", "b\n")
+	runGit(t, dir, "tag", "v2.0.0")
+	runGit(t, dir, "checkout", "-q", "main")
+	if tag, err := New(dir).GetLastTag(); tag != "" || err != nil {
+		t.Errorf("GetLastTag() with only unreachable tags = %q, %v, want no tag", tag, err)
+	}
+
+	runGit(t, dir, "tag", "v1.0.0")
+	commitFile(t, dir, "c.txt", "c\n")
+	if tag, err := New(dir).GetLastTag(); tag != "v1.0.0" || err != nil {
+		t.Errorf("GetLastTag() = %q, %v, want v1.0.0", tag, err)
+	}
+
+	if tag, err := New(t.TempDir()).GetLastTag(); err == nil {
+		t.Errorf("GetLastTag() outside a repository = %q, want an error", tag)
+	}
+}
+
+func TestLinkedWorktree(t *testing.T) {
+	main := newTestRepo(t)
+	commitFile(t, main, "README.md", "hello\n")
+	remote := filepath.Join(t.TempDir(), "remote.git")
+	runGit(t, main, "init", "-q", "--bare", remote)
+	runGit(t, main, "remote", "add", "origin", remote)
+	runGit(t, main, "push", "-q", "-u", "origin", "main")
+
+	wt := filepath.Join(t.TempDir(), "wt")
+	runGit(t, main, "worktree", "add", "-q", "-b", "topic", wt)
+	runGit(t, wt, "push", "-q", "-u", "origin", "topic")
+
+	// Hooks run with GIT_DIR set, possibly for another worktree
+	t.Setenv("GIT_DIR", filepath.Join(main, ".git"))
+
+	g := New(wt)
+	if !g.IsLinkedWorktree() {
+		t.Error("IsLinkedWorktree() = false in a linked worktree")
+	}
+	if New(main).IsLinkedWorktree() {
+		t.Error("IsLinkedWorktree() = true in the main worktree")
+	}
+
+	commonDir, err := g.CommonDir()
+	if err != nil {
+		t.Fatalf("CommonDir() error = %v", err)
+	}
+	if want := evalSymlinks(t, filepath.Join(main, ".git")); evalSymlinks(t, commonDir) != want {
+		t.Errorf("CommonDir() = %s, want %s", commonDir, want)
+	}
+	top, err := g.Toplevel()
+	if err != nil {
+		t.Fatalf("Toplevel() error = %v", err)
+	}
+	if want := evalSymlinks(t, wt); evalSymlinks(t, top) != want {
+		t.Errorf("Toplevel() = %s, want %s", top, want)
+	}
+	if branch, _ := g.GetCurrentBranch(); branch != "topic" {
+		t.Errorf("GetCurrentBranch() = %q, want %q", branch, "topic")
+	}
+	if remote, branch, ok := g.Upstream(); !ok || remote != "origin" || branch != "topic" {
+		t.Errorf("Upstream() = %q, %q, %v, want origin, topic, true", remote, branch, ok)
+	}
+
+	if err := os.WriteFile(filepath.Join(wt, "topic.txt"), []byte("topic\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.StageAll(); err != nil {
+		t.Fatalf("StageAll() error = %v", err)
+	}
+	if err := g.Commit("feat: add topic"); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	if err := g.Push(); err != nil {
+		t.Fatalf("Push() error = %v", err)
+	}
+
+	// --git-dir overrides the GIT_DIR set above
+	if got := runGit(t, remote, "--git-dir", remote, "log", "-1", "--format=%s", "topic"); got != "feat: add topic" {
+		t.Errorf("remote topic is at %q, want %q", got, "feat: add topic")
+	}
+	if got := runGit(t, remote, "--git-dir", remote, "log", "-1", "--format=%s", "main"); got != "add README.md" {
+		t.Errorf("remote main is at %q, want it unchanged", got)
+	}
+	if branch, _ := New(main).GetCurrentBranch(); branch != "main" {
+		t.Errorf("main worktree is on %q, want main", branch)
+	}
+}
+
+func TestPush(t *testing.T) {
+	tests := []struct {
+		name string
+		// setup creates the branch being pushed
+		setup func(t *testing.T, dir string)
+		// upstream is the branch Upstream reports, "" for none
+		upstream string
+		// pushedTo is the remote branch that must get the commit and absent
+		// one the remote must not have afterwards
+		pushedTo, absent string
+		sameName         bool
+	}{
+		{
+			name: "upstream of the same name",
+			setup: func(t *testing.T, dir string) {
+				runGit(t, dir, "checkout", "-q", "-b", "topic")
+				runGit(t, dir, "push", "-q", "-u", "origin", "topic")
+			},
+			upstream: "topic",
+			pushedTo: "topic",
+		},
+		{
+			name: "upstream of another name",
+			setup: func(t *testing.T, dir string) {
+				runGit(t, dir, "checkout", "-q", "-b", "fix")
+				runGit(t, dir, "push", "-q", "-u", "origin", "fix:bugfix-123")
+			},
+			upstream: "bugfix-123",
+			pushedTo: "bugfix-123",
+			absent:   "fix",
+		},
+		{
+			name: "upstream of another name pushed to the same name",
+			setup: func(t *testing.T, dir string) {
+				runGit(t, dir, "checkout", "-q", "-b", "fix")
+				runGit(t, dir, "push", "-q", "-u", "origin", "fix:bugfix-123")
+			},
+			upstream: "bugfix-123",
+			pushedTo: "fix",
+			sameName: true,
+		},
+		{
+			name: "no upstream",
+			setup: func(t *testing.T, dir string) {
+				runGit(t, dir, "checkout", "-q", "-b", "feature")
+			},
+			pushedTo: "feature",
+		},
+		{
+			name: "local upstream",
+			setup: func(t *testing.T, dir string) {
+				runGit(t, dir, "checkout", "-q", "--track", "-b", "local", "main")
+			},
+			pushedTo: "local",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := newTestRepo(t)
+			commitFile(t, dir, "README.md", "hello\n")
+			remote := filepath.Join(t.TempDir(), "remote.git")
+			runGit(t, dir, "init", "-q", "--bare", remote)
+			runGit(t, dir, "remote", "add", "origin", remote)
+			runGit(t, dir, "push", "-q", "-u", "origin", "main")
+			tt.setup(t, dir)
+			want := commitFile(t, dir, "change.txt", "change\n")
+
+			g := New(dir)
+			remoteName, upstream, ok := g.Upstream()
+			if upstream != tt.upstream || ok != (tt.upstream != "") || (ok && remoteName != "origin") {
+				t.Errorf("Upstream() = %q, %q, %v, want upstream %q", remoteName, upstream, ok, tt.upstream)
+			}
+
+			push := g.Push
+			if tt.sameName {
+				push = g.PushSameName
+			}
+			if err := push(); err != nil {
+				t.Fatalf("push error = %v", err)
+			}
+
+			if got := runGit(t, remote, "rev-parse", tt.pushedTo); got != want {
+				t.Errorf("remote %s is at %s, want %s", tt.pushedTo, got, want)
+			}
+			if got := runGit(t, remote, "rev-parse", "main"); got == want {
+				t.Error("remote main got the commit")
+			}
+			if tt.absent != "" {
+				if branches := runGit(t, remote, "branch", "--list", tt.absent); branches != "" {
+					t.Errorf("remote has branch %s after the push", tt.absent)
+				}
+			}
+		})
+	}
+}
+
+// evalSymlinks resolves a path the way git reports it, e.g. through a
+// symlinked temp directory
+func evalSymlinks(t *testing.T, path string) string {
+	t.Helper()
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resolved
+}
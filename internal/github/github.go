@@ -0,0 +1,151 @@
+package github
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// defaultAPIURL is the REST API root for github.com
+const defaultAPIURL = "https://api.github.com"
+
+// Client provides GitHub API operations
+type Client struct {
+	apiURL     string
+	token      string
+	httpClient *http.Client
+}
+
+// Config holds GitHub client configuration
+type Config struct {
+	// APIURL is the REST API root, e.g. https://github.example.com/api/v3
+	// for GitHub Enterprise (default https://api.github.com)
+	APIURL string
+	Token  string
+}
+
+// Repo identifies a repository
+type Repo struct {
+	Owner string
+	Name  string
+	// APIURL is the REST API root serving the repository
+	APIURL string
+}
+
+// NewPullRequest describes a pull request to open
+type NewPullRequest struct {
+	Title string `json:"title"`
+	Body  string `json:"body,omitempty"`
+	Head  string `json:"head"`
+	Base  string `json:"base"`
+	Draft bool   `json:"draft,omitempty"`
+}
+
+// PullRequest represents a created pull request
+type PullRequest struct {
+	Number  int    `json:"number"`
+	HTMLURL string `json:"html_url"`
+	Draft   bool   `json:"draft"`
+}
+
+// New creates a new GitHub client
+func New(cfg Config) *Client {
+	if cfg.APIURL == "" {
+		cfg.APIURL = defaultAPIURL
+	}
+	return &Client{
+		apiURL: strings.TrimRight(cfg.APIURL, "/"),
+		token:  cfg.Token,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// IsConfigured returns true if a token is set
+func (c *Client) IsConfigured() bool {
+	return c.token != ""
+}
+
+// ParseRepo extracts the owner and name from a repository web URL such as
+// "https://github.com/owner/repo". Hosts other than github.com are treated as
+// GitHub Enterprise, served from https://<host>/api/v3.
+func ParseRepo(webURL string) (Repo, error) {
+	u, err := url.Parse(webURL)
+	if err != nil || u.Host == "" {
+		return Repo{}, fmt.Errorf("invalid repository URL: %s", webURL)
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return Repo{}, fmt.Errorf("not a GitHub repository URL: %s", webURL)
+	}
+
+	apiURL := defaultAPIURL
+	if !strings.EqualFold(u.Host, "github.com") {
+		apiURL = u.Scheme + "://" + u.Host + "/api/v3"
+	}
+	return Repo{Owner: parts[0], Name: parts[1], APIURL: apiURL}, nil
+}
+
+// CreatePullRequest opens a pull request in repo
+func (c *Client) CreatePullRequest(repo Repo, pr NewPullRequest) (*PullRequest, error) {
+	path := fmt.Sprintf("/repos/%s/%s/pulls", url.PathEscape(repo.Owner), url.PathEscape(repo.Name))
+	body, err := c.doRequest("POST", path, pr)
+	if err != nil {
+		return nil, err
+	}
+
+	var created PullRequest
+	if err := json.Unmarshal(body, &created); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return &created, nil
+}
+
+// doRequest sends an authenticated request to the GitHub API and returns the
+// response body. reqBody, if non-nil, is sent as JSON.
+func (c *Client) doRequest(method, path string, reqBody interface{}) ([]byte, error) {
+	var reader io.Reader
+	if reqBody != nil {
+		jsonBody, err := json.Marshal(reqBody)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewBuffer(jsonBody)
+	}
+
+	req, err := http.NewRequest(method, c.apiURL+path, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if reqBody != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("github API error (status %d): %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return body, nil
+}
@@ -0,0 +1,61 @@
+package jira
+
+import "strings"
+
+// adfNode is a node in an Atlassian Document Format document
+type adfNode struct {
+	Type    string    `json:"type"`
+	Version int       `json:"version,omitempty"`
+	Text    string    `json:"text,omitempty"`
+	Content []adfNode `json:"content,omitempty"`
+}
+
+// textToADF converts plain text to an ADF document. Blank lines separate
+// paragraphs and lines starting with "- " or "* " become bullet lists.
+func textToADF(text string) *adfNode {
+	doc := &adfNode{Type: "doc", Version: 1}
+
+	var paragraph []string
+	var bullets []adfNode
+
+	flushParagraph := func() {
+		if len(paragraph) > 0 {
+			doc.Content = append(doc.Content, adfNode{
+				Type:    "paragraph",
+				Content: []adfNode{{Type: "text", Text: strings.Join(paragraph, " ")}},
+			})
+			paragraph = nil
+		}
+	}
+	flushBullets := func() {
+		if len(bullets) > 0 {
+			doc.Content = append(doc.Content, adfNode{Type: "bulletList", Content: bullets})
+			bullets = nil
+		}
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+			flushParagraph()
+			flushBullets()
+		case strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "* "):
+			flushParagraph()
+			bullets = append(bullets, adfNode{
+				Type: "listItem",
+				Content: []adfNode{{
+					Type:    "paragraph",
+					Content: []adfNode{{Type: "text", Text: strings.TrimSpace(line[2:])}},
+				}},
+			})
+		default:
+			flushBullets()
+			paragraph = append(paragraph, line)
+		}
+	}
+	flushParagraph()
+	flushBullets()
+
+	return doc
+}
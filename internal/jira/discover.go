@@ -0,0 +1,67 @@
+package jira
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// cliConfig is the subset of the jira-cli config file
+// (https://github.com/ankitpokhrel/jira-cli) that gh-assistant can use
+type cliConfig struct {
+	Server  string `yaml:"server"`
+	Login   string `yaml:"login"`
+	Project struct {
+		Key string `yaml:"key"`
+	} `yaml:"project"`
+}
+
+// CLIConfigPath returns where jira-cli keeps its config: $JIRA_CONFIG_FILE,
+// or .jira/.config.yml under $XDG_CONFIG_HOME (default ~/.config)
+func CLIConfigPath() (string, error) {
+	if path := os.Getenv("JIRA_CONFIG_FILE"); path != "" {
+		return path, nil
+	}
+
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		configHome = filepath.Join(home, ".config")
+	}
+	return filepath.Join(configHome, ".jira", ".config.yml"), nil
+}
+
+// DiscoverCLIConfig reads Jira settings already set up for jira-cli: the
+// server, login and default project from its config file, and the API token
+// from JIRA_API_TOKEN. Settings that can't be found are left empty; a missing
+// config file is not an error.
+func DiscoverCLIConfig() (Config, error) {
+	cfg := Config{APIToken: os.Getenv("JIRA_API_TOKEN")}
+
+	path, err := CLIConfigPath()
+	if err != nil {
+		return cfg, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var file cliConfig
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return cfg, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	cfg.BaseURL = file.Server
+	cfg.Email = file.Login
+	cfg.Project = file.Project.Key
+	return cfg, nil
+}
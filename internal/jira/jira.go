@@ -3,9 +3,13 @@ package jira
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
 )
 
 // Client provides Jira API operations
@@ -14,6 +18,14 @@ type Client struct {
 	email    string
 	apiToken string
 	project  string
+	// branchRules pick the project, issue type and labels by branch
+	branchRules []BranchRule
+	// transitions remembers resolved transition IDs
+	transitions TransitionCache
+	// acceptanceField is the custom field holding acceptance criteria
+	acceptanceField string
+	// keySegment is the slash-separated branch segment holding the issue key
+	keySegment int
 }
 
 // Config holds Jira client configuration
@@ -22,6 +34,20 @@ type Config struct {
 	Email    string
 	APIToken string
 	Project  string // Project key, e.g., "PROJ"
+	// BranchRules override the project and issue type for matching
+	// branches; the first match wins
+	BranchRules []BranchRule
+	// Transitions caches transition IDs between runs; by default they are
+	// only remembered for the life of the client
+	Transitions TransitionCache
+	// AcceptanceField is the ID of the custom field holding acceptance
+	// criteria (e.g. customfield_10035); when empty only the description
+	// is read
+	AcceptanceField string
+	// KeySegment is the slash-separated branch segment BranchIssueKey reads
+	// the issue key from: 1 is the first, -1 the last and 0 (the default)
+	// searches every segment
+	KeySegment int
 }
 
 // Issue represents a Jira issue
@@ -43,9 +69,26 @@ type createIssueRequest struct {
 }
 
 type createIssueFields struct {
-	Project   projectField   `json:"project"`
-	Summary   string         `json:"summary"`
-	IssueType issueTypeField `json:"issuetype"`
+	Project     projectField   `json:"project"`
+	Summary     string         `json:"summary"`
+	Description *adfNode       `json:"description,omitempty"`
+	IssueType   issueTypeField `json:"issuetype"`
+	Labels      []string       `json:"labels,omitempty"`
+}
+
+// CreateOptions holds optional fields for new issues
+type CreateOptions struct {
+	// Description is plain text; blank lines separate paragraphs and
+	// "- " lines become bullet lists
+	Description string
+	// Labels are added to the issue, e.g. BranchLabel(branch)
+	Labels []string
+	// Branch selects the matching branch rule in CreateIssueWithTitle
+	Branch string
+	// Project and IssueType override the configured project and
+	// DefaultIssueType
+	Project   string
+	IssueType string
 }
 
 type projectField struct {
@@ -80,11 +123,20 @@ type transition struct {
 
 // New creates a new Jira client
 func New(cfg Config) *Client {
+	transitions := cfg.Transitions
+	if transitions == nil {
+		transitions = memoryTransitionCache{}
+	}
 	return &Client{
 		baseURL:  cfg.BaseURL,
 		email:    cfg.Email,
 		apiToken: cfg.APIToken,
 		project:  cfg.Project,
+
+		branchRules:     cfg.BranchRules,
+		transitions:     transitions,
+		acceptanceField: cfg.AcceptanceField,
+		keySegment:      cfg.KeySegment,
 	}
 }
 
@@ -93,29 +145,83 @@ func (c *Client) IsConfigured() bool {
 	return c.baseURL != "" && c.email != "" && c.apiToken != "" && c.project != ""
 }
 
+// Project returns the configured project key
+func (c *Client) Project() string {
+	return c.project
+}
+
 // CreateIssue creates a new Jira issue and returns the created issue
-func (c *Client) CreateIssue(summary string) (*Issue, error) {
+func (c *Client) CreateIssue(summary string, opts CreateOptions) (*Issue, error) {
+	project := c.project
+	if opts.Project != "" {
+		project = opts.Project
+	}
+	issueType := DefaultIssueType
+	if opts.IssueType != "" {
+		issueType = opts.IssueType
+	}
 	reqBody := createIssueRequest{
 		Fields: createIssueFields{
-			Project:   projectField{Key: c.project},
+			Project:   projectField{Key: project},
 			Summary:   summary,
-			IssueType: issueTypeField{Name: "Task"},
+			IssueType: issueTypeField{Name: issueType},
+			Labels:    opts.Labels,
 		},
 	}
+	if opts.Description != "" {
+		reqBody.Fields.Description = textToADF(opts.Description)
+	}
 
-	jsonBody, err := json.Marshal(reqBody)
+	body, err := c.doRequest("POST", "/rest/api/3/issue", reqBody)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+		return nil, err
+	}
+
+	var issue Issue
+	if err := json.Unmarshal(body, &issue); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", c.baseURL+"/rest/api/3/issue", bytes.NewBuffer(jsonBody))
+	return &issue, nil
+}
+
+// GetIssue fetches an existing issue by key
+func (c *Client) GetIssue(issueKey string) (*Issue, error) {
+	body, err := c.doRequest("GET", "/rest/api/3/issue/"+url.PathEscape(issueKey)+"?fields=summary,status", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var issue Issue
+	if err := json.Unmarshal(body, &issue); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &issue, nil
+}
+
+// doRequest sends an authenticated request to the Jira API and returns the
+// response body. reqBody, if non-nil, is sent as JSON.
+func (c *Client) doRequest(method, path string, reqBody interface{}) ([]byte, error) {
+	var reader io.Reader
+	if reqBody != nil {
+		jsonBody, err := json.Marshal(reqBody)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewBuffer(jsonBody)
+	}
+
+	req, err := http.NewRequest(method, c.baseURL+path, reader)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.SetBasicAuth(c.email, c.apiToken)
-	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
+	if reqBody != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
 
 	client := &http.Client{}
 	resp, err := client.Do(req)
@@ -130,23 +236,69 @@ func (c *Client) CreateIssue(summary string) (*Issue, error) {
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("jira API error (status %d): %s", resp.StatusCode, string(body))
+		return nil, &apiError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
-	var issue Issue
-	if err := json.Unmarshal(body, &issue); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
-	}
+	return body, nil
+}
 
-	return &issue, nil
+// apiError is a Jira API response with a non-2xx status
+type apiError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("jira API error (status %d): %s", e.StatusCode, e.Body)
+}
+
+// hasStatus reports whether err is a Jira API error with the given status
+func hasStatus(err error, status int) bool {
+	var apiErr *apiError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == status
 }
 
-// TransitionToInProgress moves the issue to "In Progress" status
+// TransitionToInProgress moves the issue to "In Progress" status. The
+// transition ID is cached per project, so usually this takes one request;
+// if a cached ID no longer works it is looked up again.
 func (c *Client) TransitionToInProgress(issueKey string) error {
+	cacheKey := c.transitionCacheKey(issueKey, statusInProgress)
+	if id, ok := c.transitions.TransitionID(cacheKey); ok {
+		if err := c.doTransition(issueKey, id); err == nil {
+			return nil
+		}
+		c.transitions.ForgetTransitionID(cacheKey)
+	}
+
+	inProgressID, err := c.findInProgressTransition(issueKey)
+	if err != nil {
+		return err
+	}
+
+	// Execute the transition. A 400 usually means the workflow changed
+	// after the transitions were fetched, so resolve it again once.
+	err = c.doTransition(issueKey, inProgressID)
+	if hasStatus(err, http.StatusBadRequest) {
+		inProgressID, err = c.findInProgressTransition(issueKey)
+		if err != nil {
+			return err
+		}
+		err = c.doTransition(issueKey, inProgressID)
+	}
+	if err != nil {
+		return err
+	}
+	c.transitions.SetTransitionID(cacheKey, inProgressID)
+	return nil
+}
+
+// findInProgressTransition fetches the issue's available transitions and
+// returns the ID of the one to "In Progress"
+func (c *Client) findInProgressTransition(issueKey string) (string, error) {
 	// First, get available transitions
 	transitions, err := c.getTransitions(issueKey)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	// Find the "In Progress" transition
@@ -171,36 +323,15 @@ func (c *Client) TransitionToInProgress(issueKey string) error {
 	}
 
 	if inProgressID == "" {
-		return fmt.Errorf("no 'In Progress' transition available for issue %s", issueKey)
+		return "", fmt.Errorf("no 'In Progress' transition available for issue %s", issueKey)
 	}
-
-	// Execute the transition
-	return c.doTransition(issueKey, inProgressID)
+	return inProgressID, nil
 }
 
 func (c *Client) getTransitions(issueKey string) ([]transition, error) {
-	req, err := http.NewRequest("GET", c.baseURL+"/rest/api/3/issue/"+issueKey+"/transitions", nil)
+	body, err := c.doRequest("GET", "/rest/api/3/issue/"+issueKey+"/transitions", nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.SetBasicAuth(c.email, c.apiToken)
-	req.Header.Set("Accept", "application/json")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("jira API error (status %d): %s", resp.StatusCode, string(body))
+		return nil, err
 	}
 
 	var transResp transitionsResponse
@@ -216,40 +347,25 @@ func (c *Client) doTransition(issueKey, transitionID string) error {
 		Transition: transitionField{ID: transitionID},
 	}
 
-	jsonBody, err := json.Marshal(reqBody)
-	if err != nil {
-		return fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	req, err := http.NewRequest("POST", c.baseURL+"/rest/api/3/issue/"+issueKey+"/transitions", bytes.NewBuffer(jsonBody))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.SetBasicAuth(c.email, c.apiToken)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json")
+	_, err := c.doRequest("POST", "/rest/api/3/issue/"+issueKey+"/transitions", reqBody)
+	return err
+}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to send request: %w", err)
+// CreateIssueWithTitle creates a Jira issue with title format "JIRA-ID - message"
+// and transitions it to In Progress. Returns the formatted title. The first
+// branch rule matching opts.Branch sets the project, issue type and extra labels.
+func (c *Client) CreateIssueWithTitle(commitMessage string, opts CreateOptions) (string, error) {
+	rule := c.RuleFor(opts.Branch)
+	if opts.Project == "" {
+		opts.Project = rule.Project
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("jira API error (status %d): %s", resp.StatusCode, string(body))
+	if opts.IssueType == "" {
+		opts.IssueType = rule.IssueType
 	}
+	opts.Labels = append(opts.Labels, rule.Labels...)
 
-	return nil
-}
-
-// CreateIssueWithTitle creates a Jira issue with title format "JIRA-ID - message"
-// and transitions it to In Progress. Returns the formatted title.
-func (c *Client) CreateIssueWithTitle(commitMessage string) (string, error) {
 	// Create the issue first (with just the commit message as summary)
-	issue, err := c.CreateIssue(commitMessage)
+	issue, err := c.CreateIssue(commitMessage, opts)
 	if err != nil {
 		return "", fmt.Errorf("failed to create issue: %w", err)
 	}
@@ -269,3 +385,50 @@ func (c *Client) GetIssueURL(issueKey string) string {
 	return fmt.Sprintf("%s/browse/%s", c.baseURL, issueKey)
 }
 
+// issueKeyPattern matches an upper-case Jira issue key bounded by
+// non-alphanumerics, so keys embedded in branch segments like
+// "feature/PROJ-123/login" are found but "fix/issue-42" isn't a key
+var issueKeyPattern = regexp.MustCompile(`(?:^|[^A-Za-z0-9])([A-Z][A-Z0-9]+-[0-9]+)(?:$|[^A-Za-z0-9])`)
+
+// ExtractIssueKey returns the first Jira issue key found in s (typically a branch
+// name or commit title), or "" if there is none. Without a project only
+// upper-case keys count; with one, only keys for that project are considered,
+// matched case-insensitively ("proj-7" in "fix/proj-7") and returned upper-cased.
+func ExtractIssueKey(s, project string) string {
+	pattern := issueKeyPattern
+	if project != "" {
+		pattern = regexp.MustCompile(`(?i)(?:^|[^a-z0-9])(` + regexp.QuoteMeta(project) + `-[0-9]+)(?:$|[^a-z0-9])`)
+	}
+	// Check every slash-separated segment so multi-segment branches
+	// (type/ticket/desc) are handled the same as single-segment ones
+	for _, segment := range strings.Split(s, "/") {
+		if m := pattern.FindStringSubmatch(segment); m != nil {
+			return strings.ToUpper(m[1])
+		}
+	}
+	return ""
+}
+
+// BranchIssueKey returns the issue key in the branch name, looking in the
+// configured segment only and for keys of the branch's project
+func (c *Client) BranchIssueKey(branch string) string {
+	return ExtractIssueKey(BranchSegment(branch, c.keySegment), c.ProjectForBranch(branch))
+}
+
+// BranchSegment returns the n-th slash-separated segment of the branch,
+// counting from 1, or from the end if n is negative, e.g. segment 2 (or -2)
+// of "feature/PROJ-123/login" is "PROJ-123". n == 0 returns the whole branch,
+// and a segment the branch doesn't have is "".
+func BranchSegment(branch string, n int) string {
+	if n == 0 {
+		return branch
+	}
+	segments := strings.Split(branch, "/")
+	if n < 0 {
+		n += len(segments) + 1
+	}
+	if n < 1 || n > len(segments) {
+		return ""
+	}
+	return segments[n-1]
+}
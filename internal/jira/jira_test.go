@@ -0,0 +1,88 @@
+package jira
+
+import "testing"
+
+func TestExtractIssueKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		project string
+		want    string
+	}{
+		{"single segment", "PROJ-123-login", "", "PROJ-123"},
+		{"type/ticket", "feature/PROJ-123", "", "PROJ-123"},
+		{"type/ticket/desc", "feature/PROJ-123/login", "", "PROJ-123"},
+		{"ticket in last segment", "users/ana/fix/PROJ-9-typo", "", "PROJ-9"},
+		{"first key wins", "feature/PROJ-1/OPS-2", "", "PROJ-1"},
+		{"lower-case word is no key", "fix/issue-42", "", ""},
+		{"lower-case version is no key", "release/v2-3", "", ""},
+		{"mixed case is no key", "feature/Proj-123/login", "", ""},
+		{"no key", "feature/login", "", ""},
+		{"key inside a word", "feature/XPROJ-123", "PROJ", ""},
+		{"project filter", "feature/OPS-1/PROJ-2", "PROJ", "PROJ-2"},
+		{"other project only", "feature/OPS-1/login", "PROJ", ""},
+		{"lower-case project key", "fix/proj-42/login", "PROJ", "PROJ-42"},
+		{"lower-case configured project", "fix/PROJ-42", "proj", "PROJ-42"},
+		{"commit title", "PROJ-7 - Add login", "PROJ", "PROJ-7"},
+		{"empty", "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExtractIssueKey(tt.s, tt.project); got != tt.want {
+				t.Errorf("ExtractIssueKey(%q, %q) = %q, want %q", tt.s, tt.project, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBranchSegment(t *testing.T) {
+	tests := []struct {
+		branch string
+		n      int
+		want   string
+	}{
+		{"feature/PROJ-123/login", 0, "feature/PROJ-123/login"},
+		{"feature/PROJ-123/login", 1, "feature"},
+		{"feature/PROJ-123/login", 2, "PROJ-123"},
+		{"feature/PROJ-123/login", 3, "login"},
+		{"feature/PROJ-123/login", 4, ""},
+		{"feature/PROJ-123/login", -1, "login"},
+		{"feature/PROJ-123/login", -2, "PROJ-123"},
+		{"feature/PROJ-123/login", -4, ""},
+		{"PROJ-123", 1, "PROJ-123"},
+		{"PROJ-123", -1, "PROJ-123"},
+	}
+	for _, tt := range tests {
+		if got := BranchSegment(tt.branch, tt.n); got != tt.want {
+			t.Errorf("BranchSegment(%q, %d) = %q, want %q", tt.branch, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestBranchIssueKey(t *testing.T) {
+	rules := []BranchRule{{Pattern: "infra/*", Project: "OPS"}}
+	tests := []struct {
+		name    string
+		project string
+		segment int
+		branch  string
+		want    string
+	}{
+		{"whole branch", "", 0, "feature/PROJ-123/login", "PROJ-123"},
+		{"configured segment", "", 2, "feature/PROJ-123/login", "PROJ-123"},
+		{"key outside the segment", "", 2, "feature/login/PROJ-123", ""},
+		{"last segment", "", -1, "feature/login/PROJ-123", "PROJ-123"},
+		{"key in the description", "", 1, "PROJ-1/fix-OPS-2", "PROJ-1"},
+		{"configured project", "PROJ", 0, "fix/proj-42", "PROJ-42"},
+		{"branch rule project", "PROJ", 0, "infra/PROJ-1/ops-7", "OPS-7"},
+		{"no project, lower-case", "", 0, "fix/issue-42", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(Config{Project: tt.project, BranchRules: rules, KeySegment: tt.segment})
+			if got := c.BranchIssueKey(tt.branch); got != tt.want {
+				t.Errorf("BranchIssueKey(%q) = %q, want %q", tt.branch, got, tt.want)
+			}
+		})
+	}
+}
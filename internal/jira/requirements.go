@@ -0,0 +1,94 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// GetRequirements returns an issue's description followed by its acceptance
+// criteria field, if configured, as plain text. It returns "" when both are
+// empty.
+func (c *Client) GetRequirements(issueKey string) (string, error) {
+	fields := "description"
+	if c.acceptanceField != "" {
+		fields += "," + c.acceptanceField
+	}
+	body, err := c.doRequest("GET", "/rest/api/3/issue/"+url.PathEscape(issueKey)+"?fields="+url.QueryEscape(fields), nil)
+	if err != nil {
+		return "", err
+	}
+
+	var issue struct {
+		Fields map[string]json.RawMessage `json:"fields"`
+	}
+	if err := json.Unmarshal(body, &issue); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	var parts []string
+	if text := fieldText(issue.Fields["description"]); text != "" {
+		parts = append(parts, text)
+	}
+	if c.acceptanceField != "" {
+		if text := fieldText(issue.Fields[c.acceptanceField]); text != "" {
+			parts = append(parts, "Acceptance criteria:\n"+text)
+		}
+	}
+	return strings.Join(parts, "\n\n"), nil
+}
+
+// fieldText reads a text field, which is ADF for rich text fields and a
+// plain string for older custom fields
+func fieldText(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return strings.TrimSpace(s)
+	}
+	var doc adfNode
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return ""
+	}
+	return adfToText(&doc)
+}
+
+var extraBlankLines = regexp.MustCompile(`\n{3,}`)
+
+// adfToText flattens an ADF document to plain text: blocks are separated by
+// blank lines and list items start with "- "
+func adfToText(doc *adfNode) string {
+	var b strings.Builder
+	writeADF(&b, doc)
+	return strings.TrimSpace(extraBlankLines.ReplaceAllString(b.String(), "\n\n"))
+}
+
+func writeADF(b *strings.Builder, node *adfNode) {
+	switch node.Type {
+	case "text":
+		b.WriteString(node.Text)
+		return
+	case "hardBreak":
+		b.WriteString("\n")
+		return
+	case "listItem":
+		var item strings.Builder
+		for i := range node.Content {
+			writeADF(&item, &node.Content[i])
+		}
+		b.WriteString("- " + strings.Join(strings.Fields(item.String()), " ") + "\n")
+		return
+	}
+
+	for i := range node.Content {
+		writeADF(b, &node.Content[i])
+	}
+	switch node.Type {
+	case "paragraph", "heading", "codeBlock", "blockquote", "bulletList", "orderedList":
+		b.WriteString("\n\n")
+	}
+}
@@ -0,0 +1,55 @@
+package jira
+
+import (
+	"regexp"
+	"strings"
+)
+
+// DefaultIssueType is the type of issues created when no branch rule sets one
+const DefaultIssueType = "Task"
+
+// BranchRule sets the project, issue type and extra labels of issues created
+// for branches matching Pattern. In the pattern "*" matches any run of
+// characters (including "/") and "?" any single character, so "frontend/*"
+// matches "frontend/login" and "frontend/WEB-1/login".
+type BranchRule struct {
+	Pattern   string   `mapstructure:"pattern"`
+	Project   string   `mapstructure:"project"`
+	IssueType string   `mapstructure:"issue_type"`
+	Labels    []string `mapstructure:"labels"`
+}
+
+// Matches reports whether the rule applies to the branch
+func (r BranchRule) Matches(branch string) bool {
+	pattern := regexp.QuoteMeta(r.Pattern)
+	pattern = strings.NewReplacer(`\*`, ".*", `\?`, ".").Replace(pattern)
+	matched, _ := regexp.MatchString("^"+pattern+"$", branch)
+	return matched
+}
+
+// RuleFor returns the first branch rule matching the branch, with the
+// configured project and DefaultIssueType filled in where the rule (or the
+// lack of one) leaves them empty
+func (c *Client) RuleFor(branch string) BranchRule {
+	var rule BranchRule
+	if branch != "" {
+		for _, r := range c.branchRules {
+			if r.Matches(branch) {
+				rule = r
+				break
+			}
+		}
+	}
+	if rule.Project == "" {
+		rule.Project = c.project
+	}
+	if rule.IssueType == "" {
+		rule.IssueType = DefaultIssueType
+	}
+	return rule
+}
+
+// ProjectForBranch returns the project issues for the branch are created in
+func (c *Client) ProjectForBranch(branch string) string {
+	return c.RuleFor(branch).Project
+}
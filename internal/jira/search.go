@@ -0,0 +1,69 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// searchResponse represents the response from a JQL search
+type searchResponse struct {
+	Issues []Issue `json:"issues"`
+}
+
+// searchLimit caps the number of issues Search returns
+const searchLimit = 50
+
+// Search runs a JQL query and returns the matching issues, at most searchLimit
+func (c *Client) Search(jql string) ([]Issue, error) {
+	return c.search(jql, searchLimit)
+}
+
+// search runs a JQL query and returns up to maxResults matching issues
+func (c *Client) search(jql string, maxResults int) ([]Issue, error) {
+	query := url.Values{}
+	query.Set("jql", jql)
+	query.Set("fields", "summary,status")
+	query.Set("maxResults", fmt.Sprint(maxResults))
+
+	body, err := c.doRequest("GET", "/rest/api/3/search?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp searchResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return resp.Issues, nil
+}
+
+// BranchLabel returns the label that marks issues created for a branch,
+// e.g. "branch:feature/login". Labels can't contain spaces.
+func BranchLabel(branch string) string {
+	return "branch:" + strings.Join(strings.Fields(branch), "-")
+}
+
+// FindIssueByLabel returns the most recent issue in the project with the
+// given label, or nil if there is none. An empty project means the
+// configured one.
+func (c *Client) FindIssueByLabel(label, project string) (*Issue, error) {
+	if project == "" {
+		project = c.project
+	}
+	jql := fmt.Sprintf("project = %s AND labels = %s ORDER BY created DESC", quoteJQL(project), quoteJQL(label))
+	issues, err := c.search(jql, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(issues) == 0 {
+		return nil, nil
+	}
+	return &issues[0], nil
+}
+
+// quoteJQL quotes a value for use in a JQL query
+func quoteJQL(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
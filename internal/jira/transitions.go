@@ -0,0 +1,40 @@
+package jira
+
+import "strings"
+
+// statusInProgress is the status TransitionToInProgress moves issues to
+const statusInProgress = "In Progress"
+
+// TransitionCache remembers transition IDs by key, e.g. in a state file so
+// they survive between runs
+type TransitionCache interface {
+	TransitionID(key string) (string, bool)
+	SetTransitionID(key, id string)
+	ForgetTransitionID(key string)
+}
+
+// transitionCacheKey identifies the transition to a status in the issue's
+// project on this Jira site
+func (c *Client) transitionCacheKey(issueKey, status string) string {
+	project := issueKey
+	if i := strings.LastIndexByte(issueKey, '-'); i > 0 {
+		project = issueKey[:i]
+	}
+	return c.baseURL + " " + project + " " + status
+}
+
+// memoryTransitionCache is the default TransitionCache, kept in memory
+type memoryTransitionCache map[string]string
+
+func (m memoryTransitionCache) TransitionID(key string) (string, bool) {
+	id, ok := m[key]
+	return id, ok
+}
+
+func (m memoryTransitionCache) SetTransitionID(key, id string) {
+	m[key] = id
+}
+
+func (m memoryTransitionCache) ForgetTransitionID(key string) {
+	delete(m, key)
+}
@@ -0,0 +1,71 @@
+package patch
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// goDeclPattern matches an exported Go func, method or type declaration line,
+// capturing the declaration up to the name (e.g. "func (c *Client) Do")
+var goDeclPattern = regexp.MustCompile(`^\s*((?:func\s+(?:\([^)]*\)\s*)?|type\s+)[A-Z]\w*)`)
+
+// DetectBreakingChanges looks for likely API breaks in a diff: exported Go
+// funcs, methods and types that are removed or whose declaration line changes.
+// A declaration moved unchanged to another file isn't reported. Test files and
+// internal packages are skipped since they aren't part of the public API.
+func DetectBreakingChanges(diff string) []string {
+	removed := make(map[string]string)
+	var order []string
+	added := make(map[string]string)
+
+	for _, f := range Parse(diff) {
+		if !strings.HasSuffix(f.Path, ".go") || strings.HasSuffix(f.Path, "_test.go") ||
+			strings.HasPrefix(f.Path, "internal/") || strings.Contains(f.Path, "/internal/") {
+			continue
+		}
+
+		for _, line := range strings.Split(f.Text, "\n") {
+			if strings.HasPrefix(line, "---") || strings.HasPrefix(line, "+++") {
+				continue
+			}
+			switch {
+			case strings.HasPrefix(line, "-"):
+				if decl, key := goDecl(line[1:]); key != "" {
+					if _, seen := removed[key]; !seen {
+						order = append(order, key)
+					}
+					removed[key] = decl
+				}
+			case strings.HasPrefix(line, "+"):
+				if decl, key := goDecl(line[1:]); key != "" {
+					added[key] = decl
+				}
+			}
+		}
+	}
+
+	var changes []string
+	for _, key := range order {
+		newDecl, ok := added[key]
+		switch {
+		case !ok:
+			changes = append(changes, fmt.Sprintf("removed exported %s", key))
+		case newDecl != removed[key]:
+			changes = append(changes, fmt.Sprintf("changed the signature of exported %s", key))
+		}
+	}
+	return changes
+}
+
+// goDecl returns a Go declaration line with whitespace normalized and the
+// part identifying the declared name, or "" if the line doesn't declare an
+// exported func, method or type
+func goDecl(line string) (decl, key string) {
+	m := goDeclPattern.FindStringSubmatch(line)
+	if m == nil {
+		return "", ""
+	}
+	decl = strings.Join(strings.Fields(strings.TrimSuffix(strings.TrimSpace(line), "{")), " ")
+	return decl, strings.Join(strings.Fields(m[1]), " ")
+}
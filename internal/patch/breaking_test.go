@@ -0,0 +1,66 @@
+package patch
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDetectBreakingChanges(t *testing.T) {
+	tests := []struct {
+		name string
+		diff string
+		want []string
+	}{
+		{
+			name: "removed func",
+			diff: "diff --git a/api.go b/api.go\n--- a/api.go\n+++ b/api.go\n@@ -1,3 +1,0 @@\n-func Login(user string) error {\n-\treturn nil\n-}\n",
+			want: []string{"removed exported func Login"},
+		},
+		{
+			name: "changed signature",
+			diff: "diff --git a/api.go b/api.go\n--- a/api.go\n+++ b/api.go\n@@ -1 +1 @@\n-func (c *Client) Do(req Request) error {\n+func (c *Client) Do(ctx context.Context, req Request) error {\n",
+			want: []string{"changed the signature of exported func (c *Client) Do"},
+		},
+		{
+			name: "removed type",
+			diff: "diff --git a/api.go b/api.go\n--- a/api.go\n+++ b/api.go\n@@ -1 +0,0 @@\n-type Options struct {\n",
+			want: []string{"removed exported type Options"},
+		},
+		{
+			name: "moved unchanged to another file",
+			diff: "diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -1 +0,0 @@\n-func Login(user string) error {\n" +
+				"diff --git a/b.go b/b.go\n--- a/b.go\n+++ b/b.go\n@@ -0,0 +1 @@\n+func  Login(user string)  error {\n",
+		},
+		{
+			name: "whitespace only",
+			diff: "diff --git a/api.go b/api.go\n--- a/api.go\n+++ b/api.go\n@@ -1 +1 @@\n-func Login(user string) error {\n+func Login(user string) error{\n",
+		},
+		{
+			name: "unexported",
+			diff: "diff --git a/api.go b/api.go\n--- a/api.go\n+++ b/api.go\n@@ -1 +0,0 @@\n-func login(user string) error {\n",
+		},
+		{
+			name: "added",
+			diff: "diff --git a/api.go b/api.go\n--- a/api.go\n+++ b/api.go\n@@ -0,0 +1 @@\n+func Logout() error {\n",
+		},
+		{
+			name: "test file",
+			diff: "diff --git a/api_test.go b/api_test.go\n--- a/api_test.go\n+++ b/api_test.go\n@@ -1 +0,0 @@\n-func TestLogin(t *testing.T) {\n",
+		},
+		{
+			name: "internal package",
+			diff: "diff --git a/internal/auth/auth.go b/internal/auth/auth.go\n--- a/internal/auth/auth.go\n+++ b/internal/auth/auth.go\n@@ -1 +0,0 @@\n-func Login() error {\n",
+		},
+		{
+			name: "not Go",
+			diff: "diff --git a/notes.md b/notes.md\n--- a/notes.md\n+++ b/notes.md\n@@ -1 +0,0 @@\n-func Login() error {\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectBreakingChanges(tt.diff); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DetectBreakingChanges() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
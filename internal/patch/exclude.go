@@ -0,0 +1,35 @@
+package patch
+
+import (
+	"path"
+	"strings"
+)
+
+// DefaultExcludes are directories of vendored or generated files that rarely
+// help describe a change
+var DefaultExcludes = []string{"vendor/", "node_modules/", "dist/", ".next/"}
+
+// DefaultGenerated match common code generator output: protobuf, gRPC,
+// swagger clients and mocks
+var DefaultGenerated = []string{"*.pb.go", "*.pb.gw.go", "*_pb2.py", "*_pb2_grpc.py", "*.swagger.json", "zz_generated*", "mock_*.go", "mocks/"}
+
+// IsExcluded reports whether filePath matches one of the patterns. A pattern
+// ending in "/" matches a directory of that name at any depth; other patterns
+// are globs matched against the whole path and against the file name.
+func IsExcluded(filePath string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if strings.HasSuffix(pattern, "/") {
+			if strings.Contains("/"+path.Dir(filePath)+"/", "/"+pattern) {
+				return true
+			}
+			continue
+		}
+		if ok, _ := path.Match(pattern, filePath); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, path.Base(filePath)); ok {
+			return true
+		}
+	}
+	return false
+}
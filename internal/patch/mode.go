@@ -0,0 +1,34 @@
+package patch
+
+import "strings"
+
+// ModeChange is a change of a file's permissions, e.g. from 100644 to 100755
+type ModeChange struct {
+	Path    string
+	OldMode string
+	NewMode string
+}
+
+// Executable reports whether the change makes the file executable
+func (m ModeChange) Executable() bool {
+	return m.NewMode == "100755"
+}
+
+// ModeOnly returns the file's permission change if its diff changes nothing
+// else: no content, rename or binary change
+func (f File) ModeOnly() (ModeChange, bool) {
+	change := ModeChange{Path: f.Path}
+	for _, line := range strings.Split(f.Text, "\n") {
+		switch {
+		case strings.HasPrefix(line, "old mode "):
+			change.OldMode = strings.TrimPrefix(line, "old mode ")
+		case strings.HasPrefix(line, "new mode "):
+			change.NewMode = strings.TrimPrefix(line, "new mode ")
+		case strings.HasPrefix(line, "@@"), strings.HasPrefix(line, "Binary files "),
+			strings.HasPrefix(line, "GIT binary patch"), strings.HasPrefix(line, "rename "),
+			strings.HasPrefix(line, "copy "):
+			return ModeChange{}, false
+		}
+	}
+	return change, change.OldMode != "" && change.NewMode != ""
+}
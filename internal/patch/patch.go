@@ -0,0 +1,87 @@
+package patch
+
+import "strings"
+
+// File is the portion of a unified git diff that belongs to one file
+type File struct {
+	// Path is the file's path after the change (or before, if deleted)
+	Path string
+	// Text is the file's full diff, starting at its "diff --git" line
+	Text string
+}
+
+// Parse splits a git diff into per-file sections. Text before the first
+// "diff --git" line is discarded.
+func Parse(diff string) []File {
+	var files []File
+	var current *strings.Builder
+	var path string
+
+	flush := func() {
+		if current != nil {
+			files = append(files, File{Path: path, Text: current.String()})
+		}
+	}
+
+	for _, line := range strings.SplitAfter(diff, "\n") {
+		if strings.HasPrefix(line, "diff --git ") {
+			flush()
+			current = &strings.Builder{}
+			path = pathFromHeader(strings.TrimRight(line, "\n"))
+		}
+		if current != nil {
+			current.WriteString(line)
+		}
+	}
+	flush()
+
+	return files
+}
+
+// Join reassembles per-file sections into a single diff
+func Join(files []File) string {
+	var b strings.Builder
+	for _, f := range files {
+		b.WriteString(f.Text)
+		if !strings.HasSuffix(f.Text, "\n") {
+			b.WriteString("\n")
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// Filter returns the diff with only the files for which keep returns true
+func Filter(diff string, keep func(File) bool) string {
+	var kept []File
+	for _, f := range Parse(diff) {
+		if keep(f) {
+			kept = append(kept, f)
+		}
+	}
+	return Join(kept)
+}
+
+// IsSubmodule reports whether the file's diff is a submodule pointer update
+func (f File) IsSubmodule() bool {
+	changed := false
+	for _, line := range strings.Split(f.Text, "\n") {
+		if strings.HasPrefix(line, "+Subproject commit ") || strings.HasPrefix(line, "-Subproject commit ") {
+			changed = true
+			continue
+		}
+		if (strings.HasPrefix(line, "+") || strings.HasPrefix(line, "-")) &&
+			!strings.HasPrefix(line, "+++") && !strings.HasPrefix(line, "---") {
+			return false
+		}
+	}
+	return changed
+}
+
+// pathFromHeader extracts the new path from a "diff --git a/x b/y" line
+func pathFromHeader(header string) string {
+	rest := strings.TrimPrefix(header, "diff --git ")
+	if i := strings.LastIndex(rest, " b/"); i >= 0 {
+		return rest[i+3:]
+	}
+	return strings.TrimPrefix(rest, "a/")
+}
@@ -0,0 +1,29 @@
+package patch
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// VerbatimLine returns the first changed or context line of the diff that is
+// at least minLength characters long, ignoring surrounding whitespace, and
+// appears verbatim in text. It returns "" if there is none or minLength is
+// not positive.
+func VerbatimLine(diff, text string, minLength int) string {
+	if minLength <= 0 {
+		return ""
+	}
+	for _, line := range strings.Split(diff, "\n") {
+		if line == "" || strings.HasPrefix(line, "+++ ") || strings.HasPrefix(line, "--- ") {
+			continue
+		}
+		if line[0] != '+' && line[0] != '-' && line[0] != ' ' {
+			continue
+		}
+		content := strings.TrimSpace(line[1:])
+		if utf8.RuneCountInString(content) >= minLength && strings.Contains(text, content) {
+			return content
+		}
+	}
+	return ""
+}
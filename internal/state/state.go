@@ -0,0 +1,102 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// State is persisted between invocations in the user's home directory
+type State struct {
+	Budget Budget `json:"budget"`
+	// JiraTransitions caches Jira transition IDs, see jira.TransitionCache
+	JiraTransitions map[string]string `json:"jira_transitions,omitempty"`
+
+	path string
+}
+
+// Budget tracks AI spend for the current budget period
+type Budget struct {
+	PeriodStart time.Time `json:"period_start"`
+	SpentUSD    float64   `json:"spent_usd"`
+}
+
+// Path returns the location of the state file
+func Path() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".gh-assistant-state.json"), nil
+}
+
+// Load reads the state file, returning empty state if it doesn't exist yet
+func Load() (*State, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &State{path: path}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("failed to read state: %w", err)
+	}
+
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("failed to parse state file %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// Save writes the state back to disk
+func (s *State) Save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize state: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to save state: %w", err)
+	}
+	return nil
+}
+
+// RollBudgetPeriod resets the spend when the current period ("daily", "weekly",
+// "monthly") has elapsed. Any other period disables automatic resets.
+func (s *State) RollBudgetPeriod(period string, now time.Time) {
+	start := periodStart(period, now)
+	if start.IsZero() {
+		if s.Budget.PeriodStart.IsZero() {
+			s.Budget.PeriodStart = now
+		}
+		return
+	}
+	if s.Budget.PeriodStart.Before(start) {
+		s.Budget = Budget{PeriodStart: start}
+	}
+}
+
+// ResetBudget clears the recorded spend
+func (s *State) ResetBudget(now time.Time) {
+	s.Budget = Budget{PeriodStart: now}
+}
+
+func periodStart(period string, now time.Time) time.Time {
+	y, m, d := now.Date()
+	switch period {
+	case "daily":
+		return time.Date(y, m, d, 0, 0, 0, 0, now.Location())
+	case "weekly":
+		offset := (int(now.Weekday()) + 6) % 7 // weeks start on Monday
+		return time.Date(y, m, d-offset, 0, 0, 0, 0, now.Location())
+	case "monthly":
+		return time.Date(y, m, 1, 0, 0, 0, 0, now.Location())
+	default:
+		return time.Time{}
+	}
+}